@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ic3network/mccs-alpha-api/global"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var (
+	file    = flag.String("file", "", "CSV file with an entityName,userEmail,... header")
+	execute = flag.Bool("execute", false, "create the entities and users, only the dry-run report is generated by default")
+	invite  = flag.Bool("invite", false, "email the imported users an invitation to set their password")
+	output  = flag.String("output", "", "results file, default is stdout")
+)
+
+func main() {
+	global.Init()
+	if *file == "" {
+		l.Logger.Fatal("[ERROR] importing entities failed: -file is required")
+	}
+
+	record, err := validate(*file)
+	if err != nil {
+		l.Logger.Fatal("[ERROR] importing entities failed:", zap.Error(err))
+	}
+	l.Logger.Info(fmt.Sprintf("Import %s: %d rows, %d invalid", record.ImportID, len(record.Rows), record.NumberOfRowsWithStatus(constant.EntityImportRow.Invalid)))
+
+	if *execute {
+		record, err = logic.EntityImport.Execute(record, *invite, "")
+		if err != nil {
+			l.Logger.Fatal("[ERROR] importing entities failed:", zap.Error(err))
+		}
+		l.Logger.Info(fmt.Sprintf("Import %s: %d entities created, %d users added to them, %d failed",
+			record.ImportID,
+			record.NumberOfRowsWithStatus(constant.EntityImportRow.Created),
+			record.NumberOfRowsWithStatus(constant.EntityImportRow.UserAdded),
+			record.NumberOfRowsWithStatus(constant.EntityImportRow.Failed),
+		))
+	}
+
+	err = writeResults(record, *output)
+	if err != nil {
+		l.Logger.Fatal("[ERROR] writing results failed:", zap.Error(err))
+	}
+}
+
+func validate(path string) (*types.EntityImport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := logic.EntityImport.Parse(f)
+	if err != nil {
+		return nil, err
+	}
+	return logic.EntityImport.Create(rows, filepath.Base(path), "")
+}
+
+func writeResults(record *types.EntityImport, path string) error {
+	if path == "" {
+		return logic.EntityImport.WriteResults(record, os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return logic.EntityImport.WriteResults(record, f)
+}
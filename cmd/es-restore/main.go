@@ -75,14 +75,20 @@ func restoreEntities() {
 			Wants:      entity.Wants,
 			Categories: entity.Categories,
 			// Address
-			City:    entity.City,
-			Region:  entity.Region,
-			Country: entity.Country,
+			City:     entity.City,
+			Region:   entity.Region,
+			Country:  entity.Country,
+			Location: types.NewGeoPoint(entity.Latitude, entity.Longitude),
 			// Account
 			AccountNumber: entity.AccountNumber,
 			Balance:       &account.Balance,
 			MaxNegBal:     &limit.MaxNegBal,
 			MaxPosBal:     &limit.MaxPosBal,
+			// Reviews
+			Rating: entity.Rating,
+		}
+		if entity.Rating != nil {
+			record.ReviewCount = &entity.ReviewCount
 		}
 		_, err = es.Client().Index().
 			Index("entities").
@@ -214,6 +220,7 @@ func restoreJournals() {
 			TransferID:        journal.TransferID,
 			FromAccountNumber: journal.FromAccountNumber,
 			ToAccountNumber:   journal.ToAccountNumber,
+			Type:              journal.Type,
 			Status:            journal.Status,
 			CreatedAt:         journal.CreatedAt,
 		}
@@ -5,6 +5,8 @@ import (
 	"github.com/ic3network/mccs-alpha-api/internal/app/http"
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic/balancecheck"
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic/dailyemail"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic/escrowrelease"
+	"github.com/ic3network/mccs-alpha-api/internal/migration"
 	"github.com/ic3network/mccs-alpha-api/util/l"
 	"github.com/robfig/cron"
 	"github.com/spf13/viper"
@@ -39,8 +41,16 @@ func ServeBackGround() {
 		balancecheck.Run()
 	})
 
+	viper.SetDefault("escrow_release_schedule", "0 30 * * * *")
+	c.AddFunc(viper.GetString("escrow_release_schedule"), func() {
+		l.Logger.Info("[ServeBackGround] Running escrow release schedule. \n")
+		escrowrelease.Run()
+	})
+
 	c.Start()
 }
 
 func RunMigration() {
+	migration.EntityOwnerRoles()
+	migration.VoucherKeys()
 }
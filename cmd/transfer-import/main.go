@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ic3network/mccs-alpha-api/global"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var (
+	file    = flag.String("file", "", "CSV file with a payer,payee,amount,description header")
+	execute = flag.Bool("execute", false, "create the transfers, only the dry-run report is generated by default")
+	mode    = flag.String("mode", constant.TransferImportMode.Atomic, "atomic or perRow")
+	output  = flag.String("output", "", "results file, default is stdout")
+	admin   = flag.String("admin", "", "email of the admin running the import, transfers above the approval threshold must be approved by another admin")
+)
+
+func main() {
+	global.Init()
+	if *file == "" {
+		l.Logger.Fatal("[ERROR] importing transfers failed: -file is required")
+	}
+	if *mode != constant.TransferImportMode.Atomic && *mode != constant.TransferImportMode.PerRow {
+		l.Logger.Fatal("[ERROR] importing transfers failed: -mode can be only atomic or perRow")
+	}
+	if *admin == "" {
+		l.Logger.Fatal("[ERROR] importing transfers failed: -admin is required")
+	}
+	adminUser, err := logic.AdminUser.FindByEmail(*admin)
+	if err != nil {
+		l.Logger.Fatal("[ERROR] importing transfers failed:", zap.Error(err))
+	}
+	adminID := adminUser.ID.Hex()
+
+	record, err := validate(*file, adminID)
+	if err != nil {
+		l.Logger.Fatal("[ERROR] importing transfers failed:", zap.Error(err))
+	}
+	l.Logger.Info(fmt.Sprintf("Import %s: %d rows, %d invalid", record.ImportID, len(record.Rows), record.NumberOfRowsWithStatus(constant.TransferImportRow.Invalid)))
+
+	if *execute {
+		executed, err := logic.TransferImport.Execute(record, *mode, adminID)
+		if executed == nil {
+			l.Logger.Fatal("[ERROR] importing transfers failed:", zap.Error(err))
+		}
+		if err != nil {
+			l.Logger.Error("[ERROR] importing transfers failed:", zap.Error(err))
+		}
+		record = executed
+		l.Logger.Info(fmt.Sprintf("Import %s: %d created, %d pending approval, %d failed",
+			record.ImportID,
+			record.NumberOfRowsWithStatus(constant.TransferImportRow.Created),
+			record.NumberOfRowsWithStatus(constant.TransferImportRow.PendingApproval),
+			record.NumberOfRowsWithStatus(constant.TransferImportRow.Failed),
+		))
+	}
+
+	err = writeResults(record, *output)
+	if err != nil {
+		l.Logger.Fatal("[ERROR] writing results failed:", zap.Error(err))
+	}
+}
+
+func validate(path string, adminID string) (*types.TransferImport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := logic.TransferImport.Parse(f)
+	if err != nil {
+		return nil, err
+	}
+	return logic.TransferImport.Create(rows, filepath.Base(path), adminID)
+}
+
+func writeResults(record *types.TransferImport, path string) error {
+	if path == "" {
+		return logic.TransferImport.WriteResults(record, os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return logic.TransferImport.WriteResults(record, f)
+}
@@ -0,0 +1,16 @@
+package constant
+
+// AccountClosure lists the steps of the account closure workflow in the order they are performed.
+var AccountClosure = struct {
+	Requested          string
+	TransfersCancelled string
+	BalanceSettled     string
+	BalanceVerified    string
+	Closed             string
+}{
+	Requested:          "closureRequested",
+	TransfersCancelled: "transfersCancelled",
+	BalanceSettled:     "balanceSettled",
+	BalanceVerified:    "balanceVerified",
+	Closed:             "accountClosed",
+}
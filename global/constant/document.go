@@ -0,0 +1,9 @@
+package constant
+
+var Document = struct {
+	Receipt   string
+	Statement string
+}{
+	Receipt:   "receipt",
+	Statement: "statement",
+}
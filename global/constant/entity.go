@@ -21,3 +21,28 @@ var Trading = struct {
 	Accepted: "tradingAccepted",
 	Rejected: "tradingRejected",
 }
+
+// EntityRole decides what a user can do on behalf of an entity.
+var EntityRole = struct {
+	// Owners can manage every member, including other owners.
+	Owner string
+	// Admins can update the entity and manage the members who are not owners.
+	Admin string
+	// Transactors can transfer credits, issue vouchers and manage payees.
+	Transactor string
+	// Viewers can only see the balance and the transfers.
+	Viewer string
+}{
+	Owner:      "owner",
+	Admin:      "admin",
+	Transactor: "transactor",
+	Viewer:     "viewer",
+}
+
+// EntityRoleRank orders the roles. A role has all the permissions of the roles ranked below it.
+var EntityRoleRank = map[string]int{
+	EntityRole.Viewer:     1,
+	EntityRole.Transactor: 2,
+	EntityRole.Admin:      3,
+	EntityRole.Owner:      4,
+}
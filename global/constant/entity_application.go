@@ -0,0 +1,50 @@
+package constant
+
+// EntityApplication lists the statuses of the membership application an entity submits for review.
+var EntityApplication = struct {
+	// The entity is still filling in the application.
+	Draft         string
+	Submitted     string
+	InfoRequested string
+	Approved      string
+	Rejected      string
+}{
+	Draft:         "draft",
+	Submitted:     "submitted",
+	InfoRequested: "infoRequested",
+	Approved:      "approved",
+	Rejected:      "rejected",
+}
+
+// ApplicationDocument lists the kinds of verification documents an entity can upload.
+var ApplicationDocument = struct {
+	IncorporationCertificate string
+	Identity                 string
+	ProofOfAddress           string
+	Other                    string
+}{
+	IncorporationCertificate: "incorporationCertificate",
+	Identity:                 "identity",
+	ProofOfAddress:           "proofOfAddress",
+	Other:                    "other",
+}
+
+// ApplicationDecision lists what an admin can do with a submitted application.
+var ApplicationDecision = struct {
+	RequestInfo string
+	Approve     string
+	Reject      string
+}{
+	RequestInfo: "requestInfo",
+	Approve:     "approve",
+	Reject:      "reject",
+}
+
+// ApplicationCommentAuthor tells who wrote a comment on an application.
+var ApplicationCommentAuthor = struct {
+	Entity string
+	Admin  string
+}{
+	Entity: "entity",
+	Admin:  "admin",
+}
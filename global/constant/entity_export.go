@@ -0,0 +1,11 @@
+package constant
+
+var EntityExportFormat = struct {
+	CSV   string
+	VCard string
+	JSON  string
+}{
+	CSV:   "csv",
+	VCard: "vcf",
+	JSON:  "json",
+}
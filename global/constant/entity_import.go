@@ -0,0 +1,28 @@
+package constant
+
+var EntityImport = struct {
+	Validated string
+	// An admin has started executing the import, it cannot be executed again.
+	Executing string
+	Executed  string
+}{
+	Validated: "importValidated",
+	Executing: "importExecuting",
+	Executed:  "importExecuted",
+}
+
+var EntityImportRow = struct {
+	Valid   string
+	Invalid string
+	// The entity and the user of the row are created.
+	Created string
+	// The user of the row is added to the entity created by a previous row.
+	UserAdded string
+	Failed    string
+}{
+	Valid:     "valid",
+	Invalid:   "invalid",
+	Created:   "created",
+	UserAdded: "userAdded",
+	Failed:    "failed",
+}
@@ -0,0 +1,21 @@
+package constant
+
+var FeedFormat = struct {
+	Atom string
+	RSS  string
+	// JSON Feed 1.1
+	JSON string
+}{
+	Atom: "atom",
+	RSS:  "rss",
+	JSON: "json",
+}
+
+// FeedTagType filters the new tags feed by offers or wants.
+var FeedTagType = struct {
+	Offer string
+	Want  string
+}{
+	Offer: "offer",
+	Want:  "want",
+}
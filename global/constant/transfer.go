@@ -7,6 +7,10 @@ func MapTransferType(name string) string {
 		return Transfer.Completed
 	} else if name == "cancelled" {
 		return Transfer.Cancelled
+	} else if name == "escrowed" {
+		return Transfer.Escrowed
+	} else if name == "pendingapproval" {
+		return Transfer.PendingApproval
 	}
 	return "unknown"
 }
@@ -15,10 +19,16 @@ var Transfer = struct {
 	Initiated string
 	Completed string
 	Cancelled string
+	// The amount has left the payer's account and is held in the escrow account.
+	Escrowed string
+	// The admin transfer is waiting for a second admin to approve it.
+	PendingApproval string
 }{
-	Initiated: "transferInitiated",
-	Completed: "transferCompleted",
-	Cancelled: "transferCancelled",
+	Initiated:       "transferInitiated",
+	Completed:       "transferCompleted",
+	Cancelled:       "transferCancelled",
+	Escrowed:        "transferEscrowed",
+	PendingApproval: "transferPendingApproval",
 }
 
 var TransferDirection = struct {
@@ -30,9 +40,51 @@ var TransferDirection = struct {
 }
 
 var TransferType = struct {
-	Transfer      string
-	AdminTransfer string
+	Transfer       string
+	AdminTransfer  string
+	Voucher        string
+	Escrow         string
+	Fee            string
+	Correction     string
+	OpeningBalance string
+	Reversal       string
 }{
-	Transfer:      "transfer",
-	AdminTransfer: "adminTransfer",
+	Transfer:       "transfer",
+	AdminTransfer:  "adminTransfer",
+	Voucher:        "voucher",
+	Escrow:         "escrow",
+	Fee:            "fee",
+	Correction:     "correction",
+	OpeningBalance: "openingBalance",
+	Reversal:       "reversal",
+}
+
+// TransferTypeRule describes how journals of a type are created.
+type TransferTypeRule struct {
+	// The balance limits of both accounts are checked.
+	EnforceLimits bool
+	// Both entities need to have trading member status.
+	RequireTradingMembers bool
+	// Only admins can create journals of this type. Admins can only create journals of these types,
+	// the others are created by the members.
+	AdminOnly bool
+	// The description has to explain why the journal was created.
+	RequireReason bool
+}
+
+// TransferTypeRules holds the rule of every journal type. A new type has to be added here to be usable.
+var TransferTypeRules = map[string]TransferTypeRule{
+	TransferType.Transfer:       {EnforceLimits: true, RequireTradingMembers: true},
+	TransferType.AdminTransfer:  {EnforceLimits: true, RequireTradingMembers: true, AdminOnly: true},
+	TransferType.Voucher:        {EnforceLimits: true, RequireTradingMembers: true},
+	TransferType.Escrow:         {EnforceLimits: true, RequireTradingMembers: true},
+	TransferType.Fee:            {AdminOnly: true},
+	TransferType.Correction:     {AdminOnly: true, RequireReason: true},
+	TransferType.OpeningBalance: {AdminOnly: true, RequireReason: true},
+	TransferType.Reversal:       {AdminOnly: true, RequireReason: true},
+}
+
+func IsValidTransferType(name string) bool {
+	_, ok := TransferTypeRules[name]
+	return ok
 }
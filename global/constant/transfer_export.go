@@ -0,0 +1,16 @@
+package constant
+
+var TransferExportFormat = struct {
+	CSV       string
+	OFX       string
+	QIF       string
+	Beancount string
+	// ISO 20022 BankToCustomerStatement.
+	Camt053 string
+}{
+	CSV:       "csv",
+	OFX:       "ofx",
+	QIF:       "qif",
+	Beancount: "beancount",
+	Camt053:   "camt053",
+}
@@ -0,0 +1,36 @@
+package constant
+
+var TransferImport = struct {
+	Validated string
+	// An admin has started executing the import, it cannot be executed again.
+	Executing string
+	Executed  string
+}{
+	Validated: "importValidated",
+	Executing: "importExecuting",
+	Executed:  "importExecuted",
+}
+
+var TransferImportMode = struct {
+	// Either every row is created or none of them.
+	Atomic string
+	// Each row is created on its own and invalid rows are skipped.
+	PerRow string
+}{
+	Atomic: "atomic",
+	PerRow: "perRow",
+}
+
+var TransferImportRow = struct {
+	Valid           string
+	Invalid         string
+	Created         string
+	PendingApproval string
+	Failed          string
+}{
+	Valid:           "valid",
+	Invalid:         "invalid",
+	Created:         "created",
+	PendingApproval: "pendingApproval",
+	Failed:          "failed",
+}
@@ -5,3 +5,9 @@ var Unit = struct {
 }{
 	UK: "ocn-uk",
 }
+
+// UnitCurrencyCode maps the units to the code used by formats that require an ISO 4217 currency code.
+// ISO 4217 has no codes for mutual credit units so "XXX" (no currency involved) is used.
+var UnitCurrencyCode = map[string]string{
+	Unit.UK: "XXX",
+}
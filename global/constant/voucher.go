@@ -0,0 +1,14 @@
+package constant
+
+var Voucher = struct {
+	Issued    string
+	Redeemed  string
+	Cancelled string
+	// Expired is not stored. Issued vouchers past their expiry are reported as expired.
+	Expired string
+}{
+	Issued:    "voucherIssued",
+	Redeemed:  "voucherRedeemed",
+	Cancelled: "voucherCancelled",
+	Expired:   "voucherExpired",
+}
@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var AccountHandler = newAccountHandler()
+
+type accountHandler struct {
+	once *sync.Once
+}
+
+func newAccountHandler() *accountHandler {
+	return &accountHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *accountHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		private.Path("/accounts/{accountNumber}/statement.pdf").HandlerFunc(handler.getStatement()).Methods("GET")
+
+		adminPrivate.Path("/accounts/{accountNumber}/freeze").HandlerFunc(handler.adminFreezeAccount()).Methods("PATCH")
+		adminPrivate.Path("/accounts/{accountNumber}/opening-balance").HandlerFunc(handler.adminCreateOpeningBalance()).Methods("POST")
+		adminPrivate.Path("/accounts/{accountNumber}/closure").HandlerFunc(handler.adminCloseAccount()).Methods("POST")
+		adminPrivate.Path("/accounts/{accountNumber}/closure").HandlerFunc(handler.adminGetAccountClosure()).Methods("GET")
+	})
+}
+
+// GET /accounts/{accountNumber}/statement.pdf
+
+func (handler *accountHandler) getStatement() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByAccountNumber(mux.Vars(r)["accountNumber"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if !UserHandler.IsEntityBelongsToUser(entity.ID.Hex(), r.Header.Get("userID")) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+		req, errs := types.NewStatementQuery(r, entity)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		content, document, err := logic.Document.Statement(req.Entity, req.From, req.To, r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] AccountHandler.getStatement failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.UserAction.IssueDocument(r.Header.Get("userID"), document)
+
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="statement-`+entity.AccountNumber+`-`+req.From.Format("20060102")+`.pdf"`)
+		w.Write(content)
+	}
+}
+
+// PATCH /admin/accounts/{accountNumber}/freeze
+
+func (handler *accountHandler) adminFreezeAccount() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminFreezeAccountRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newAdminFreezeAccountReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		updated, err := logic.Account.UpdateFreeze(req)
+		if err != nil {
+			l.Logger.Error("[Error] AccountHandler.adminFreezeAccount failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminFreezeAccount(r.Header.Get("userID"), req.OriginAccount, updated)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminFreezeAccountRespond(updated)})
+	}
+}
+
+func (handler *accountHandler) newAdminFreezeAccountReq(r *http.Request) (*types.AdminFreezeAccountReq, []error) {
+	var body types.AdminFreezeAccountUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	originAccount, err := logic.Account.FindByAccountNumber(mux.Vars(r)["accountNumber"])
+	if err != nil {
+		return nil, []error{err}
+	}
+	return types.NewAdminFreezeAccountReq(&body, originAccount)
+}
+
+// POST /admin/accounts/{accountNumber}/opening-balance
+
+func (handler *accountHandler) adminCreateOpeningBalance() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminTransferRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newAdminOpeningBalanceReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		journal, err := logic.Transfer.CreateOpeningBalance(req, r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] AccountHandler.adminCreateOpeningBalance failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminTransfer(r.Header.Get("userID"), journal)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewJournalToAdminTransferRespond(journal)})
+	}
+}
+
+func (handler *accountHandler) newAdminOpeningBalanceReq(r *http.Request) (*types.AdminOpeningBalanceReq, []error) {
+	var body types.AdminOpeningBalanceUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	entity, err := logic.Entity.FindByAccountNumber(mux.Vars(r)["accountNumber"])
+	if err != nil {
+		return nil, []error{err}
+	}
+	return types.NewAdminOpeningBalanceReq(&body, entity)
+}
+
+// POST /admin/accounts/{accountNumber}/closure
+
+func (handler *accountHandler) adminCloseAccount() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AccountClosureRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newAdminCloseAccountReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		closure, err := logic.AccountClosure.Request(req, r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] AccountHandler.adminCloseAccount failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		// The closure stops at the failed step and can be resumed by sending the request again.
+		processed, err := logic.AccountClosure.Process(closure, r.Header.Get("userID"))
+		if processed == nil {
+			l.Logger.Error("[Error] AccountHandler.adminCloseAccount failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminCloseAccount(r.Header.Get("userID"), processed)
+
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAccountClosureRespond(processed)})
+	}
+}
+
+func (handler *accountHandler) newAdminCloseAccountReq(r *http.Request) (*types.AdminCloseAccountReq, []error) {
+	var body types.AdminCloseAccountUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil && err != io.EOF {
+		return nil, []error{err}
+	}
+	account, err := logic.Account.FindByAccountNumber(mux.Vars(r)["accountNumber"])
+	if err != nil {
+		return nil, []error{err}
+	}
+	entity, err := logic.Entity.FindByAccountNumber(account.AccountNumber)
+	if err != nil {
+		return nil, []error{err}
+	}
+	var settlementEntity *types.Entity
+	if body.SettlementAccountNumber != "" {
+		settlementEntity, err = logic.Entity.FindByAccountNumber(body.SettlementAccountNumber)
+		if err != nil {
+			return nil, []error{err}
+		}
+	}
+	return types.NewAdminCloseAccountReq(&body, account, entity, settlementEntity)
+}
+
+// GET /admin/accounts/{accountNumber}/closure
+
+func (handler *accountHandler) adminGetAccountClosure() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AccountClosureRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		closure, err := logic.AccountClosure.FindLatest(mux.Vars(r)["accountNumber"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAccountClosureRespond(closure)})
+	}
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+)
+
+var DocumentHandler = newDocumentHandler()
+
+type documentHandler struct {
+	once *sync.Once
+}
+
+func newDocumentHandler() *documentHandler {
+	return &documentHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *documentHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		adminPrivate.Path("/documents/{code}").HandlerFunc(handler.adminVerifyDocument()).Methods("GET")
+	})
+}
+
+// GET /admin/documents/{code}
+
+func (handler *documentHandler) adminVerifyDocument() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.DocumentRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		document, err := logic.Document.Verify(mux.Vars(r)["code"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewDocumentRespond(document)})
+	}
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
 	"net/url"
 	"sync"
@@ -14,6 +15,7 @@ import (
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/geocode"
 	"github.com/ic3network/mccs-alpha-api/util"
 	"github.com/ic3network/mccs-alpha-api/util/l"
 	"github.com/spf13/viper"
@@ -41,12 +43,15 @@ func (handler *entityHandler) RegisterRoutes(
 ) {
 	handler.once.Do(func() {
 		public.Path("/entities").HandlerFunc(handler.searchEntity()).Methods("GET")
+		public.Path("/entities/export").HandlerFunc(handler.exportEntities()).Methods("GET")
 		public.Path("/entities/{searchEntityID}").HandlerFunc(handler.getEntity()).Methods("GET")
 		private.Path("/favorites").HandlerFunc(handler.addToFavoriteEntities()).Methods("POST")
 		private.Path("/send-email").HandlerFunc(handler.sendEmailToEntity()).Methods("POST")
 		private.Path("/balance").HandlerFunc(handler.getBalance()).Methods("GET")
 
 		adminPrivate.Path("/entities").HandlerFunc(handler.adminSearchEntity()).Methods("GET")
+		adminPrivate.Path("/entities/status-changes").HandlerFunc(handler.adminSearchStatusChanges()).Methods("GET")
+		adminPrivate.Path("/entities/export").HandlerFunc(handler.adminExportEntities()).Methods("GET")
 		adminPrivate.Path("/entities/{entityID}").HandlerFunc(handler.adminGetEntity()).Methods("GET")
 		adminPrivate.Path("/entities/{entityID}").HandlerFunc(handler.adminUpdateEntity()).Methods("PATCH")
 		adminPrivate.Path("/entities/{entityID}").HandlerFunc(handler.adminDeleteEntity()).Methods("DELETE")
@@ -131,7 +136,12 @@ func (handler *entityHandler) searchEntity() func(http.ResponseWriter, *http.Req
 		result := []*types.SearchEntityRespond{}
 		queryingEntityStatus := handler.getQueryingEntityStatus(query.QueryingEntityID)
 		for _, entity := range entities {
-			result = append(result, types.NewSearchEntityRespond(entity, queryingEntityStatus, query.FavoriteEntities))
+			res := types.NewSearchEntityRespond(entity, queryingEntityStatus, query.FavoriteEntities)
+			if query.Near != nil && entity.Latitude != nil && entity.Longitude != nil {
+				distance := math.Round(geocode.Distance(query.Near.Lat, query.Near.Lon, *entity.Latitude, *entity.Longitude)*100) / 100
+				res.Distance = &distance
+			}
+			result = append(result, res)
 		}
 		return result
 	}
@@ -182,6 +192,10 @@ func (handler *entityHandler) getSearchEntityQueryParams(q url.Values) (*types.S
 	if err != nil {
 		return nil, err
 	}
+	query.AccountNumber, err = logic.Handle.Resolve(query.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
 	query.FavoriteEntities = handler.getFavoriteEntities(q.Get("querying_entity_id"))
 	return query, nil
 }
@@ -194,6 +208,76 @@ func (handler *entityHandler) getQueryingEntityStatus(entityID string) string {
 	return ""
 }
 
+// GET /entities/export
+
+func (handler *entityHandler) exportEntities() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query, err := handler.getSearchEntityQueryParams(r.URL.Query())
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		errs := query.Validate()
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+		if query.QueryingEntityID != "" {
+			if r.Header.Get("userID") == "" {
+				api.Respond(w, r, http.StatusUnauthorized, api.ErrUnauthorized)
+				return
+			}
+			if !UserHandler.IsEntityBelongsToUser(query.QueryingEntityID, r.Header.Get("userID")) {
+				api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+				return
+			}
+		}
+
+		req, errs := types.NewEntityExportReq(r.URL.Query(), query, nil)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+		req.QueryingEntityStatus = handler.getQueryingEntityStatus(query.QueryingEntityID)
+
+		handler.writeExport(w, r, req, "EntityHandler.exportEntities")
+	}
+}
+
+// writeExport streams the export. The headers are only sent with the first page,
+// so the errors of the first search can still be responded as JSON.
+func (handler *entityHandler) writeExport(w http.ResponseWriter, r *http.Request, req *types.EntityExportReq, caller string) {
+	ew := &exportWriter{
+		ResponseWriter: w,
+		contentType:    logic.EntityExport.ContentType(req),
+		fileName:       logic.EntityExport.FileName(req),
+	}
+	err := logic.EntityExport.Export(req, ew)
+	if err != nil {
+		l.Logger.Error("[Error] "+caller+" failed:", zap.Error(err))
+		if !ew.started {
+			api.Respond(w, r, http.StatusInternalServerError, err)
+		}
+	}
+}
+
+// exportWriter sets the headers of the file when the first bytes are written.
+type exportWriter struct {
+	http.ResponseWriter
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (w *exportWriter) Write(b []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.Header().Set("Content-Type", w.contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="`+w.fileName+`"`)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
 // GET /entities/{entityID}
 
 func (handler *entityHandler) getEntity() func(http.ResponseWriter, *http.Request) {
@@ -241,7 +325,7 @@ func (handler *entityHandler) addToFavoriteEntities() func(http.ResponseWriter,
 			return
 		}
 
-		if !UserHandler.IsEntityBelongsToUser(req.AddToEntityID, r.Header.Get("userID")) {
+		if !UserHandler.HasEntityRole(req.AddToEntityID, r.Header.Get("userID"), constant.EntityRole.Transactor) {
 			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
 			return
 		}
@@ -290,7 +374,7 @@ func (handler *entityHandler) sendEmailToEntity() func(http.ResponseWriter, *htt
 			api.Respond(w, r, http.StatusInternalServerError, err)
 			return
 		}
-		if !UserHandler.IsEntityBelongsToUser(req.SenderEntityID, r.Header.Get("userID")) {
+		if !UserHandler.HasEntityRole(req.SenderEntityID, r.Header.Get("userID"), constant.EntityRole.Transactor) {
 			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
 			return
 		}
@@ -381,6 +465,12 @@ func (handler *entityHandler) adminSearchEntity() func(http.ResponseWriter, *htt
 			api.Respond(w, r, http.StatusBadRequest, errs)
 			return
 		}
+		accountNumber, err := logic.Handle.Resolve(req.AccountNumber)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req.AccountNumber = accountNumber
 
 		searchEntityResult, err := logic.Entity.AdminSearch(req)
 		if err != nil {
@@ -426,6 +516,33 @@ func (handler *entityHandler) newAdminSearchEntityRespond(searchEntityResult *ty
 	return respond, nil
 }
 
+// GET /admin/entities/export
+
+func (handler *entityHandler) adminExportEntities() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		search, errs := types.NewAdminSearchEntityReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+		accountNumber, err := logic.Handle.Resolve(search.AccountNumber)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		search.AccountNumber = accountNumber
+
+		req, errs := types.NewEntityExportReq(r.URL.Query(), nil, search)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+		go logic.UserAction.AdminExportEntities(r.Header.Get("userID"), req)
+
+		handler.writeExport(w, r, req, "EntityHandler.adminExportEntities")
+	}
+}
+
 // GET /admin/entities/{entityID}
 
 func (handler *entityHandler) adminGetEntity() func(http.ResponseWriter, *http.Request) {
@@ -474,7 +591,46 @@ func (handler *entityHandler) newAdminGetEntityRespond(entity *types.Entity) (*t
 	if err != nil {
 		return nil, err
 	}
-	return types.NewAdminGetEntityRespond(entity, users, account, balanceLimit, pendingTransfers), nil
+	statusChanges, err := logic.EntityStatus.FindByEntityID(entity)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewAdminGetEntityRespond(entity, users, account, balanceLimit, pendingTransfers, statusChanges), nil
+}
+
+// GET /admin/entities/status-changes
+
+func (handler *entityHandler) adminSearchStatusChanges() func(http.ResponseWriter, *http.Request) {
+	type meta struct {
+		NumberOfResults int `json:"numberOfResults"`
+		TotalPages      int `json:"totalPages"`
+	}
+	type respond struct {
+		Data []*types.EntityStatusChangeRespond `json:"data"`
+		Meta meta                               `json:"meta"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminSearchEntityStatusChangeReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		found, err := logic.EntityStatus.Search(req)
+		if err != nil {
+			l.Logger.Error("[Error] EntityHandler.adminSearchStatusChanges failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{
+			Data: types.NewEntityStatusChangesRespond(found.Changes),
+			Meta: meta{
+				TotalPages:      found.TotalPages,
+				NumberOfResults: found.NumberOfResults,
+			},
+		})
+	}
 }
 
 // PATCH /admin/entities/{entityID}
@@ -489,6 +645,13 @@ func (handler *entityHandler) adminUpdateEntity() func(http.ResponseWriter, *htt
 			api.Respond(w, r, http.StatusBadRequest, errs)
 			return
 		}
+		if req.Handle != "" {
+			err := logic.Handle.CheckAvailable(req.Handle, req.OriginEntity.ID, true)
+			if err != nil {
+				api.Respond(w, r, http.StatusBadRequest, err)
+				return
+			}
+		}
 
 		updated, err := logic.Entity.AdminFindOneAndUpdate(req)
 		if err != nil {
@@ -496,17 +659,20 @@ func (handler *entityHandler) adminUpdateEntity() func(http.ResponseWriter, *htt
 			api.Respond(w, r, http.StatusBadRequest, err)
 			return
 		}
+		err = logic.Handle.RecordChange(req.OriginEntity, updated)
+		if err != nil {
+			l.Logger.Error("[Error] EntityHandler.updateEntity failed:", zap.Error(err))
+		}
 
-		go handler.UpdateOfferAndWants(&types.UpdateOfferAndWants{
-			EntityID:      req.OriginEntity.ID,
-			OriginStatus:  req.OriginEntity.Status,
-			UpdatedStatus: updated.Status,
-			UpdatedOffers: types.TagFieldToNames(updated.Offers),
-			UpdatedWants:  types.TagFieldToNames(updated.Wants),
-			AddedOffers:   req.AddedOffers,
-			AddedWants:    req.AddedWants,
-		})
-		go handler.updateEntityMemberStartedAt(req.OriginEntity, req.Status)
+		change, err := handler.afterAdminUpdate(req, updated, r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] EntityHandler.updateEntity failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if change != nil {
+			go logic.EntityStatus.Notify(updated, change)
+		}
 		if req.Categories != nil {
 			go CategoryHandler.Update(*req.Categories)
 		}
@@ -525,6 +691,41 @@ func (handler *entityHandler) adminUpdateEntity() func(http.ResponseWriter, *htt
 	}
 }
 
+// AdminUpdateStatus moves the entity to req.Status the same way PATCH /admin/entities/{entityID} does.
+// The caller notifies the entity about the change.
+func (handler *entityHandler) AdminUpdateStatus(req *types.AdminUpdateEntityReq, adminID string) (*types.Entity, error) {
+	updated, err := logic.Entity.AdminFindOneAndUpdate(req)
+	if err != nil {
+		return nil, err
+	}
+	_, err = handler.afterAdminUpdate(req, updated, adminID)
+	if err != nil {
+		return nil, err
+	}
+	go logic.UserAction.AdminModifyEntity(adminID, req.OriginEntity, updated)
+	return updated, nil
+}
+
+// afterAdminUpdate keeps the tags and the member start date in line with the updated entity
+// and records its status change. It returns the recorded change, if any,
+// or an error when the status has changed but the change could not be recorded.
+func (handler *entityHandler) afterAdminUpdate(req *types.AdminUpdateEntityReq, updated *types.Entity, adminID string) (*types.EntityStatusChange, error) {
+	go handler.UpdateOfferAndWants(&types.UpdateOfferAndWants{
+		EntityID:      req.OriginEntity.ID,
+		OriginStatus:  req.OriginEntity.Status,
+		UpdatedStatus: updated.Status,
+		UpdatedOffers: types.TagFieldToNames(updated.Offers),
+		UpdatedWants:  types.TagFieldToNames(updated.Wants),
+		AddedOffers:   req.AddedOffers,
+		AddedWants:    req.AddedWants,
+	})
+	go handler.updateEntityMemberStartedAt(req.OriginEntity, req.Status)
+	if req.Status == "" {
+		return nil, nil
+	}
+	return logic.EntityStatus.Record(updated, req.OriginEntity.Status, req.Status, req.StatusReason, adminID)
+}
+
 func (handler *entityHandler) newAdminUpdateEntityReq(r *http.Request) (*types.AdminUpdateEntityReq, []error) {
 	originEntity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
 	if err != nil {
@@ -591,7 +792,7 @@ func (handler *entityHandler) UpdateOfferAndWants(req *types.UpdateOfferAndWants
 		if err != nil {
 			l.Logger.Error("[Error] EntityHandler.updateOfferAndWants failed:", zap.Error(err))
 		}
-		err = TagHandler.UpdateOffers(req.UpdatedWants)
+		err = TagHandler.UpdateWants(req.UpdatedWants)
 		if err != nil {
 			l.Logger.Error("[Error] EntityHandler.updateOfferAndWants failed:", zap.Error(err))
 		}
@@ -0,0 +1,532 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+var EntityApplicationHandler = newEntityApplicationHandler()
+
+type entityApplicationHandler struct {
+	once *sync.Once
+}
+
+func newEntityApplicationHandler() *entityApplicationHandler {
+	return &entityApplicationHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *entityApplicationHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		public.Path("/application-questions").HandlerFunc(handler.getQuestions()).Methods("GET")
+		private.Path("/user/entities/{entityID}/application").HandlerFunc(handler.getApplication()).Methods("GET")
+		private.Path("/user/entities/{entityID}/application/answers").HandlerFunc(handler.updateAnswers()).Methods("PUT")
+		private.Path("/user/entities/{entityID}/application/documents").HandlerFunc(handler.uploadDocument()).Methods("POST")
+		private.Path("/user/entities/{entityID}/application/documents/{documentID}").HandlerFunc(handler.downloadDocument()).Methods("GET")
+		private.Path("/user/entities/{entityID}/application/documents/{documentID}").HandlerFunc(handler.deleteDocument()).Methods("DELETE")
+		private.Path("/user/entities/{entityID}/application/submission").HandlerFunc(handler.submitApplication()).Methods("POST")
+
+		adminPrivate.Path("/application-questions").HandlerFunc(handler.getQuestions()).Methods("GET")
+		adminPrivate.Path("/application-questions").HandlerFunc(handler.adminCreateQuestion()).Methods("POST")
+		adminPrivate.Path("/application-questions/{questionID}").HandlerFunc(handler.adminUpdateQuestion()).Methods("PATCH")
+		adminPrivate.Path("/application-questions/{questionID}").HandlerFunc(handler.adminDeleteQuestion()).Methods("DELETE")
+		adminPrivate.Path("/applications").HandlerFunc(handler.adminSearchApplications()).Methods("GET")
+		adminPrivate.Path("/entities/{entityID}/application").HandlerFunc(handler.adminGetApplication()).Methods("GET")
+		adminPrivate.Path("/entities/{entityID}/application/documents/{documentID}").HandlerFunc(handler.adminDownloadDocument()).Methods("GET")
+		adminPrivate.Path("/entities/{entityID}/application/review").HandlerFunc(handler.adminReviewApplication()).Methods("POST")
+	})
+}
+
+// findEntity returns the entity in the URL if the logged in user can manage its application.
+func (handler *entityApplicationHandler) findEntity(r *http.Request) (*types.Entity, int, error) {
+	entityID := mux.Vars(r)["entityID"]
+	if !UserHandler.HasEntityRole(entityID, r.Header.Get("userID"), constant.EntityRole.Admin) {
+		return nil, http.StatusForbidden, api.ErrPermissionDenied
+	}
+	entity, err := logic.Entity.FindByStringID(entityID)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return entity, http.StatusOK, nil
+}
+
+func (handler *entityApplicationHandler) findQuestion(r *http.Request) (*types.ApplicationQuestion, error) {
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["questionID"])
+	if err != nil {
+		return nil, errors.New("Please specify a valid question id.")
+	}
+	return logic.ApplicationQuestion.FindByID(id)
+}
+
+// readDocument reads the "document" field of the multipart form.
+func (handler *entityApplicationHandler) readDocument(w http.ResponseWriter, r *http.Request) ([]byte, string, error) {
+	maxSize := viper.GetInt64("application.max_file_size")
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+
+	f, header, err := r.FormFile("document")
+	if err != nil {
+		return nil, "", handler.readError(err, maxSize)
+	}
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, "", handler.readError(err, maxSize)
+	}
+	if len(data) == 0 {
+		return nil, "", errors.New("Please upload a document.")
+	}
+	return data, header.Filename, nil
+}
+
+func (handler *entityApplicationHandler) readError(err error, maxSize int64) error {
+	// http.MaxBytesReader does not export its error.
+	if strings.Contains(err.Error(), "request body too large") {
+		return fmt.Errorf("The document must be smaller than %d MB.", maxSize/1024/1024)
+	}
+	if err == http.ErrMissingFile || err == http.ErrNotMultipart {
+		return errors.New("Please upload a document.")
+	}
+	return err
+}
+
+// writeDocument sends the document as a download. The stored content type was detected on upload.
+func (handler *entityApplicationHandler) writeDocument(w http.ResponseWriter, r *http.Request, application *types.EntityApplication) {
+	document, err := logic.EntityApplication.FindDocument(application, mux.Vars(r)["documentID"])
+	if err != nil {
+		api.Respond(w, r, http.StatusBadRequest, err)
+		return
+	}
+	data, err := logic.EntityApplication.ReadDocument(document)
+	if err != nil {
+		l.Logger.Error("[Error] EntityApplicationHandler.writeDocument failed:", zap.Error(err))
+		api.Respond(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(data)
+}
+
+// GET /application-questions
+// GET /admin/application-questions
+
+func (handler *entityApplicationHandler) getQuestions() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.ApplicationQuestionRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		questions, err := logic.ApplicationQuestion.FindAll()
+		if err != nil {
+			l.Logger.Error("[Error] EntityApplicationHandler.getQuestions failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewApplicationQuestionsRespond(questions)})
+	}
+}
+
+// GET /user/entities/{entityID}/application
+
+func (handler *entityApplicationHandler) getApplication() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+
+		application, err := logic.EntityApplication.Find(entity)
+		if err != nil {
+			l.Logger.Error("[Error] EntityApplicationHandler.getApplication failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewEntityApplicationRespond(application)})
+	}
+}
+
+// PUT /user/entities/{entityID}/application/answers
+
+func (handler *entityApplicationHandler) updateAnswers() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		questions, err := logic.ApplicationQuestion.FindAll()
+		if err != nil {
+			l.Logger.Error("[Error] EntityApplicationHandler.updateAnswers failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		req, errs := types.NewUpdateApplicationAnswersReq(r, entity, questions)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		application, err := logic.EntityApplication.UpdateAnswers(req)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.ModifyEntityApplication(r.Header.Get("userID"), entity, "updated answers")
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewEntityApplicationRespond(application)})
+	}
+}
+
+// POST /user/entities/{entityID}/application/documents
+
+func (handler *entityApplicationHandler) uploadDocument() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		data, fileName, err := handler.readDocument(w, r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req, errs := types.NewUploadApplicationDocumentReq(entity, r.FormValue("type"), fileName, data)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		document, application, err := logic.EntityApplication.AddDocument(req)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.ModifyEntityApplication(r.Header.Get("userID"), entity, "uploaded "+document.Type+" document "+document.ID)
+
+		api.Respond(w, r, http.StatusCreated, respond{Data: types.NewEntityApplicationRespond(application)})
+	}
+}
+
+// GET /user/entities/{entityID}/application/documents/{documentID}
+
+func (handler *entityApplicationHandler) downloadDocument() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		application, err := logic.EntityApplication.Find(entity)
+		if err != nil {
+			l.Logger.Error("[Error] EntityApplicationHandler.downloadDocument failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		handler.writeDocument(w, r, application)
+	}
+}
+
+// DELETE /user/entities/{entityID}/application/documents/{documentID}
+
+func (handler *entityApplicationHandler) deleteDocument() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		application, err := logic.EntityApplication.Find(entity)
+		if err != nil {
+			l.Logger.Error("[Error] EntityApplicationHandler.deleteDocument failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		updated, err := logic.EntityApplication.DeleteDocument(application, mux.Vars(r)["documentID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.ModifyEntityApplication(r.Header.Get("userID"), entity, "deleted document "+mux.Vars(r)["documentID"])
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewEntityApplicationRespond(updated)})
+	}
+}
+
+// POST /user/entities/{entityID}/application/submission
+
+func (handler *entityApplicationHandler) submitApplication() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		req, errs := types.NewSubmitApplicationReq(r, entity)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		application, err := logic.EntityApplication.Submit(req)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.ModifyEntityApplication(r.Header.Get("userID"), entity, "submitted the application")
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewEntityApplicationRespond(application)})
+	}
+}
+
+// POST /admin/application-questions
+
+func (handler *entityApplicationHandler) adminCreateQuestion() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.ApplicationQuestionRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminUpdateApplicationQuestionReq(r, &types.ApplicationQuestion{})
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		created, err := logic.ApplicationQuestion.Create(req.Question)
+		if err != nil {
+			l.Logger.Error("[Error] EntityApplicationHandler.adminCreateQuestion failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminModifyApplicationQuestion(r.Header.Get("userID"), created, "created")
+
+		api.Respond(w, r, http.StatusCreated, respond{Data: types.NewApplicationQuestionRespond(created)})
+	}
+}
+
+// PATCH /admin/application-questions/{questionID}
+
+func (handler *entityApplicationHandler) adminUpdateQuestion() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.ApplicationQuestionRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		question, err := handler.findQuestion(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req, errs := types.NewAdminUpdateApplicationQuestionReq(r, question)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		updated, err := logic.ApplicationQuestion.Update(req.Question)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminModifyApplicationQuestion(r.Header.Get("userID"), updated, "updated")
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewApplicationQuestionRespond(updated)})
+	}
+}
+
+// DELETE /admin/application-questions/{questionID}
+
+func (handler *entityApplicationHandler) adminDeleteQuestion() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.ApplicationQuestionRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		question, err := handler.findQuestion(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		deleted, err := logic.ApplicationQuestion.Delete(question.ID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminModifyApplicationQuestion(r.Header.Get("userID"), deleted, "deleted")
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewApplicationQuestionRespond(deleted)})
+	}
+}
+
+// GET /admin/applications
+
+func (handler *entityApplicationHandler) adminSearchApplications() func(http.ResponseWriter, *http.Request) {
+	type meta struct {
+		NumberOfResults int `json:"numberOfResults"`
+		TotalPages      int `json:"totalPages"`
+	}
+	type respond struct {
+		Data []*types.AdminEntityApplicationRespond `json:"data"`
+		Meta meta                                   `json:"meta"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminSearchEntityApplicationReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		found, err := logic.EntityApplication.Search(req)
+		if err != nil {
+			l.Logger.Error("[Error] EntityApplicationHandler.adminSearchApplications failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{
+			Data: types.NewAdminEntityApplicationsRespond(found.Applications),
+			Meta: meta{
+				TotalPages:      found.TotalPages,
+				NumberOfResults: found.NumberOfResults,
+			},
+		})
+	}
+}
+
+// GET /admin/entities/{entityID}/application
+
+func (handler *entityApplicationHandler) adminGetApplication() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminEntityApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		application, err := logic.EntityApplication.AdminFind(entity.ID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminEntityApplicationRespond(application)})
+	}
+}
+
+// GET /admin/entities/{entityID}/application/documents/{documentID}
+
+func (handler *entityApplicationHandler) adminDownloadDocument() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		application, err := logic.EntityApplication.AdminFind(entity.ID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		handler.writeDocument(w, r, application)
+	}
+}
+
+// POST /admin/entities/{entityID}/application/review
+
+func (handler *entityApplicationHandler) adminReviewApplication() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminEntityApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		balanceLimit, err := logic.BalanceLimit.FindByAccountNumber(entity.AccountNumber)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req, errs := types.NewAdminReviewApplicationReq(r, entity, balanceLimit)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		err = logic.EntityApplication.CheckReviewable(entity.ID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		// The entity status is changed first so the application is never reviewed without its status change.
+		if req.EntityUpdate != nil && req.EntityUpdate.Status != "" {
+			entity, err = EntityHandler.AdminUpdateStatus(req.EntityUpdate, req.AdminID)
+			if err != nil {
+				l.Logger.Error("[Error] EntityApplicationHandler.adminReviewApplication failed:", zap.Error(err))
+				api.Respond(w, r, http.StatusInternalServerError, err)
+				return
+			}
+		}
+		application, err := logic.EntityApplication.Review(req)
+		if err != nil {
+			l.Logger.Error("[Error] EntityApplicationHandler.adminReviewApplication failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.EntityApplication.NotifyReview(entity, req)
+		go logic.UserAction.AdminReviewEntityApplication(req.AdminID, req)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminEntityApplicationRespond(application)})
+	}
+}
@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var EntityImageHandler = newEntityImageHandler()
+
+type entityImageHandler struct {
+	once *sync.Once
+}
+
+func newEntityImageHandler() *entityImageHandler {
+	return &entityImageHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *entityImageHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		private.Path("/user/entities/{entityID}/logo").HandlerFunc(handler.setLogo()).Methods("PUT")
+		private.Path("/user/entities/{entityID}/logo").HandlerFunc(handler.deleteLogo()).Methods("DELETE")
+		private.Path("/user/entities/{entityID}/gallery").HandlerFunc(handler.addGalleryImage()).Methods("POST")
+		private.Path("/user/entities/{entityID}/gallery/{imageID}").HandlerFunc(handler.deleteGalleryImage()).Methods("DELETE")
+	})
+}
+
+// findEntity returns the entity in the URL if the logged in user can manage its profile.
+func (handler *entityImageHandler) findEntity(r *http.Request) (*types.Entity, int, error) {
+	entityID := mux.Vars(r)["entityID"]
+	if !UserHandler.HasEntityRole(entityID, r.Header.Get("userID"), constant.EntityRole.Admin) {
+		return nil, http.StatusForbidden, api.ErrPermissionDenied
+	}
+	entity, err := logic.Entity.FindByStringID(entityID)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return entity, http.StatusOK, nil
+}
+
+// readImage accepts either a multipart form with an "image" field or the image as the request body.
+func (handler *entityImageHandler) readImage(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	maxSize := viper.GetInt64("image.max_file_size")
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		f, _, err := r.FormFile("image")
+		if err != nil {
+			return nil, handler.readError(err, maxSize)
+		}
+		defer f.Close()
+		file = f
+	}
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, handler.readError(err, maxSize)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("Please upload an image.")
+	}
+	return data, nil
+}
+
+func (handler *entityImageHandler) readError(err error, maxSize int64) error {
+	// http.MaxBytesReader does not export its error.
+	if strings.Contains(err.Error(), "request body too large") {
+		return fmt.Errorf("The image must be smaller than %d MB.", maxSize/1024/1024)
+	}
+	return err
+}
+
+// respond responds with the entity after its images have been changed.
+func (handler *entityImageHandler) respond(w http.ResponseWriter, r *http.Request, entity *types.Entity, detail string) {
+	type respond struct {
+		Data *types.EntityRespond `json:"data"`
+	}
+	updated, err := logic.Entity.FindByID(entity.ID)
+	if err != nil {
+		l.Logger.Error("[Error] EntityImageHandler.respond failed:", zap.Error(err))
+		api.Respond(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	res, err := EntityHandler.NewEntityRespond(updated)
+	if err != nil {
+		l.Logger.Error("[Error] EntityImageHandler.respond failed:", zap.Error(err))
+		api.Respond(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	go logic.UserAction.ModifyEntityImage(r.Header.Get("userID"), entity, detail)
+
+	api.Respond(w, r, http.StatusOK, respond{Data: res})
+}
+
+// PUT /user/entities/{entityID}/logo
+
+func (handler *entityImageHandler) setLogo() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		data, err := handler.readImage(w, r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		image, err := logic.EntityImage.SetLogo(entity, data)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		handler.respond(w, r, entity, "uploaded logo "+image.ID)
+	}
+}
+
+// DELETE /user/entities/{entityID}/logo
+
+func (handler *entityImageHandler) deleteLogo() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+
+		err = logic.EntityImage.DeleteLogo(entity)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		handler.respond(w, r, entity, "deleted logo "+entity.Logo.ID)
+	}
+}
+
+// POST /user/entities/{entityID}/gallery
+
+func (handler *entityImageHandler) addGalleryImage() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		data, err := handler.readImage(w, r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		image, err := logic.EntityImage.AddGalleryImage(entity, data)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		handler.respond(w, r, entity, "added gallery image "+image.ID)
+	}
+}
+
+// DELETE /user/entities/{entityID}/gallery/{imageID}
+
+func (handler *entityImageHandler) deleteGalleryImage() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+
+		err = logic.EntityImage.DeleteGalleryImage(entity, mux.Vars(r)["imageID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		handler.respond(w, r, entity, "deleted gallery image "+mux.Vars(r)["imageID"])
+	}
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var EntityImportHandler = newEntityImportHandler()
+
+type entityImportHandler struct {
+	once *sync.Once
+}
+
+func newEntityImportHandler() *entityImportHandler {
+	return &entityImportHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *entityImportHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		adminPrivate.Path("/entities/imports").HandlerFunc(handler.adminCreateEntityImport()).Methods("POST")
+		adminPrivate.Path("/entities/imports/{importID}").HandlerFunc(handler.adminGetEntityImport()).Methods("GET")
+		adminPrivate.Path("/entities/imports/{importID}/execution").HandlerFunc(handler.adminExecuteEntityImport()).Methods("POST")
+		adminPrivate.Path("/entities/imports/{importID}/results.csv").HandlerFunc(handler.adminGetEntityImportResults()).Methods("GET")
+	})
+}
+
+// POST /admin/entities/imports
+
+func (handler *entityImportHandler) adminCreateEntityImport() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityImportRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, viper.GetInt64("entity_import.max_file_size"))
+		file, fileName, err := handler.openFile(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		defer file.Close()
+
+		rows, err := logic.EntityImport.Parse(file)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		record, err := logic.EntityImport.Create(rows, fileName, r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] EntityImportHandler.adminCreateEntityImport failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.UserAction.AdminEntityImport(r.Header.Get("userID"), record)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewEntityImportRespond(record)})
+	}
+}
+
+// openFile accepts either a multipart form with a "file" field or the CSV file as the request body.
+func (handler *entityImportHandler) openFile(r *http.Request) (io.ReadCloser, string, error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.Body, "", nil
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, "", err
+	}
+	return file, header.Filename, nil
+}
+
+// GET /admin/entities/imports/{importID}
+
+func (handler *entityImportHandler) adminGetEntityImport() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityImportRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, err := logic.EntityImport.FindByImportID(mux.Vars(r)["importID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewEntityImportRespond(record)})
+	}
+}
+
+// POST /admin/entities/imports/{importID}/execution
+
+func (handler *entityImportHandler) adminExecuteEntityImport() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityImportRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, err := logic.EntityImport.FindByImportID(mux.Vars(r)["importID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req, errs := types.NewAdminExecuteEntityImportReq(r, record)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		executed, err := logic.EntityImport.Execute(req.Import, req.SendInvitations, r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] EntityImportHandler.adminExecuteEntityImport failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.UserAction.AdminEntityImport(r.Header.Get("userID"), executed)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewEntityImportRespond(executed)})
+	}
+}
+
+// GET /admin/entities/imports/{importID}/results.csv
+
+func (handler *entityImportHandler) adminGetEntityImportResults() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, err := logic.EntityImport.FindByImportID(mux.Vars(r)["importID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="entity-import-`+record.ImportID+`.csv"`)
+		err = logic.EntityImport.WriteResults(record, w)
+		if err != nil {
+			l.Logger.Error("[Error] EntityImportHandler.adminGetEntityImportResults failed:", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var EntityMemberHandler = newEntityMemberHandler()
+
+type entityMemberHandler struct {
+	once *sync.Once
+}
+
+func newEntityMemberHandler() *entityMemberHandler {
+	return &entityMemberHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *entityMemberHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		private.Path("/user/entities/{entityID}/members").HandlerFunc(handler.listMembers()).Methods("GET")
+		private.Path("/user/entities/{entityID}/members/{userID}").HandlerFunc(handler.updateMember()).Methods("PATCH")
+		private.Path("/user/entities/{entityID}/members/{userID}").HandlerFunc(handler.removeMember()).Methods("DELETE")
+		private.Path("/user/entities/{entityID}/invitations").HandlerFunc(handler.listInvitations()).Methods("GET")
+		private.Path("/user/entities/{entityID}/invitations").HandlerFunc(handler.invite()).Methods("POST")
+		private.Path("/user/entities/{entityID}/invitations/{invitationID}").HandlerFunc(handler.revokeInvitation()).Methods("DELETE")
+		private.Path("/invitations/{token}").HandlerFunc(handler.acceptInvitation()).Methods("POST")
+	})
+}
+
+// findEntity returns the entity in the URL if the logged in user has at least the role in it.
+func (handler *entityMemberHandler) findEntity(r *http.Request, role string) (*types.Entity, int, error) {
+	entityID := mux.Vars(r)["entityID"]
+	if !UserHandler.HasEntityRole(entityID, r.Header.Get("userID"), role) {
+		return nil, http.StatusForbidden, api.ErrPermissionDenied
+	}
+	entity, err := logic.Entity.FindByStringID(entityID)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return entity, http.StatusOK, nil
+}
+
+// findMember returns the user in the URL if the user is a member of the entity.
+func (handler *entityMemberHandler) findMember(r *http.Request, entity *types.Entity) (*types.User, error) {
+	userID := mux.Vars(r)["userID"]
+	if !util.ContainID(entity.Users, userID) {
+		return nil, errors.New("The user is not a member of this entity.")
+	}
+	return logic.User.FindByStringID(userID)
+}
+
+// GET /user/entities/{entityID}/members
+
+func (handler *entityMemberHandler) listMembers() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.EntityMemberRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r, constant.EntityRole.Viewer)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+
+		users, err := logic.EntityMember.FindMembers(entity)
+		if err != nil {
+			l.Logger.Error("[Error] EntityMemberHandler.listMembers failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		data := []*types.EntityMemberRespond{}
+		for _, user := range users {
+			data = append(data, types.NewEntityMemberRespond(entity, user))
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// PATCH /user/entities/{entityID}/members/{userID}
+
+func (handler *entityMemberHandler) updateMember() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityMemberRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r, constant.EntityRole.Admin)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		member, err := handler.findMember(r, entity)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		req, errs := types.NewUpdateEntityMemberReq(r, entity, member, util.ToObjectID(r.Header.Get("userID")))
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		err = logic.EntityMember.UpdateRole(req)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		updated, err := logic.Entity.FindByID(entity.ID)
+		if err != nil {
+			l.Logger.Error("[Error] EntityMemberHandler.updateMember failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.ModifyEntityMember(r.Header.Get("userID"), entity, member, req.Role)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewEntityMemberRespond(updated, member)})
+	}
+}
+
+// DELETE /user/entities/{entityID}/members/{userID}
+
+func (handler *entityMemberHandler) removeMember() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityMemberRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Members can always leave the entity themselves.
+		role := constant.EntityRole.Admin
+		if mux.Vars(r)["userID"] == r.Header.Get("userID") {
+			role = constant.EntityRole.Viewer
+		}
+		entity, status, err := handler.findEntity(r, role)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		member, err := handler.findMember(r, entity)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		data := types.NewEntityMemberRespond(entity, member)
+		err = logic.EntityMember.Remove(entity, member, util.ToObjectID(r.Header.Get("userID")))
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.ModifyEntityMember(r.Header.Get("userID"), entity, member, "")
+
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// GET /user/entities/{entityID}/invitations
+
+func (handler *entityMemberHandler) listInvitations() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.EntityInvitationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r, constant.EntityRole.Admin)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+
+		invitations, err := logic.EntityMember.FindInvitations(entity)
+		if err != nil {
+			l.Logger.Error("[Error] EntityMemberHandler.listInvitations failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		data := []*types.EntityInvitationRespond{}
+		for _, invitation := range invitations {
+			data = append(data, types.NewEntityInvitationRespond(invitation))
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// POST /user/entities/{entityID}/invitations
+
+func (handler *entityMemberHandler) invite() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityInvitationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r, constant.EntityRole.Admin)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		user, err := logic.User.FindByStringID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] EntityMemberHandler.invite failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		req, errs := types.NewInviteEntityMemberReq(r, entity, user)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		invitation, err := logic.EntityMember.Invite(req)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go email.EntityInvitation(&email.EntityInvitationEmail{
+			EntityName:    entity.Name,
+			InviterName:   user.FirstName + " " + user.LastName,
+			Role:          invitation.Role,
+			ReceiverEmail: invitation.Email,
+			Token:         invitation.Token,
+		})
+		go logic.UserAction.InviteEntityMember(user, entity, invitation)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewEntityInvitationRespond(invitation)})
+	}
+}
+
+// DELETE /user/entities/{entityID}/invitations/{invitationID}
+
+func (handler *entityMemberHandler) revokeInvitation() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r, constant.EntityRole.Admin)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+
+		err = logic.EntityMember.RevokeInvitation(entity, mux.Vars(r)["invitationID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK)
+	}
+}
+
+// POST /invitations/{token}
+
+func (handler *entityMemberHandler) acceptInvitation() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := logic.User.FindByStringID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] EntityMemberHandler.acceptInvitation failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		entity, invitation, err := logic.EntityMember.Accept(mux.Vars(r)["token"], user)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := EntityHandler.NewEntityRespond(entity)
+		if err != nil {
+			l.Logger.Error("[Error] EntityMemberHandler.acceptInvitation failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.JoinEntity(user, entity, invitation.Role)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: res})
+	}
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type feedHandler struct {
+	once *sync.Once
+}
+
+var FeedHandler = newFeedHandler()
+
+func newFeedHandler() *feedHandler {
+	return &feedHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *feedHandler) RegisterRoutes(feed *mux.Router) {
+	handler.once.Do(func() {
+		feed.Path("/members").HandlerFunc(handler.members()).Methods("GET")
+		feed.Path("/tags").HandlerFunc(handler.tags()).Methods("GET")
+	})
+}
+
+// GET /feeds/members
+
+func (handler *feedHandler) members() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewFeedReq(r.URL.Query())
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		result, err := logic.Feed.Members(req, feedURL(r))
+		if err != nil {
+			l.Logger.Error("[Error] FeedHandler.members failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		handler.write(w, r, req, result)
+	}
+}
+
+// GET /feeds/tags
+
+func (handler *feedHandler) tags() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewFeedReq(r.URL.Query())
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		result, err := logic.Feed.Tags(req, feedURL(r))
+		if err != nil {
+			l.Logger.Error("[Error] FeedHandler.tags failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		handler.write(w, r, req, result)
+	}
+}
+
+// write responds the feed with its caching headers.
+// The ETag and Last-Modified headers let feed readers poll with conditional requests, which are answered with 304 Not Modified.
+func (handler *feedHandler) write(w http.ResponseWriter, r *http.Request, req *types.FeedReq, result *types.Feed) {
+	var buf bytes.Buffer
+	err := logic.Feed.Write(req, result, &buf)
+	if err != nil {
+		l.Logger.Error("[Error] FeedHandler.write failed:", zap.Error(err))
+		api.Respond(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	sum := sha1.Sum(buf.Bytes())
+
+	w.Header().Set("Content-Type", logic.Feed.ContentType(req))
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(viper.GetInt("feed.max_age")))
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	http.ServeContent(w, r, "", result.Updated, bytes.NewReader(buf.Bytes()))
+}
+
+// feedURL is the URL the feed was requested from, feeds link to themselves.
+func feedURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var PayeeHandler = newPayeeHandler()
+
+type payeeHandler struct {
+	once *sync.Once
+}
+
+func newPayeeHandler() *payeeHandler {
+	return &payeeHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *payeeHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		private.Path("/user/entities/{entityID}/payees").HandlerFunc(handler.listPayees()).Methods("GET")
+		private.Path("/user/entities/{entityID}/payees").HandlerFunc(handler.createPayee()).Methods("POST")
+		private.Path("/user/entities/{entityID}/payees/{payeeID}").HandlerFunc(handler.updatePayee()).Methods("PATCH")
+		private.Path("/user/entities/{entityID}/payees/{payeeID}").HandlerFunc(handler.deletePayee()).Methods("DELETE")
+	})
+}
+
+// findEntity returns the entity in the URL if the logged in user has at least the role in it.
+func (handler *payeeHandler) findEntity(r *http.Request, role string) (*types.Entity, int, error) {
+	entityID := mux.Vars(r)["entityID"]
+	if !UserHandler.HasEntityRole(entityID, r.Header.Get("userID"), role) {
+		return nil, http.StatusForbidden, api.ErrPermissionDenied
+	}
+	entity, err := logic.Entity.FindByStringID(entityID)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return entity, http.StatusOK, nil
+}
+
+// findPayee returns the payee in the URL if it belongs to the entity.
+func (handler *payeeHandler) findPayee(r *http.Request, entity *types.Entity) (*types.Payee, error) {
+	payee, err := logic.Payee.FindByStringID(mux.Vars(r)["payeeID"])
+	if err != nil {
+		return nil, errors.New("Payee not found.")
+	}
+	if payee.EntityID != entity.ID {
+		return nil, errors.New("Payee not found.")
+	}
+	return payee, nil
+}
+
+// resolveAccountNumber turns a handle into its account number and makes sure the account exists.
+func (handler *payeeHandler) resolveAccountNumber(accountNumber string, entity *types.Entity) (string, error) {
+	if accountNumber == "" {
+		return "", nil
+	}
+	accountNumber, err := logic.Handle.Resolve(accountNumber)
+	if err != nil {
+		return "", err
+	}
+	if accountNumber == entity.AccountNumber {
+		return "", errors.New("You cannot add yourself as a payee.")
+	}
+	_, err = logic.Entity.FindByAccountNumber(accountNumber)
+	if err != nil {
+		return "", errors.New("Account number does not exist.")
+	}
+	return accountNumber, nil
+}
+
+// GET /user/entities/{entityID}/payees
+
+func (handler *payeeHandler) listPayees() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.PayeeRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r, constant.EntityRole.Viewer)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+
+		payees, err := logic.Payee.FindByEntityID(entity.ID)
+		if err != nil {
+			l.Logger.Error("[Error] PayeeHandler.listPayees failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewPayeesRespond(payees)})
+	}
+}
+
+// POST /user/entities/{entityID}/payees
+
+func (handler *payeeHandler) createPayee() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.PayeeRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r, constant.EntityRole.Transactor)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+
+		req, errs := types.NewCreatePayeeReq(r, entity)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+		req.AccountNumber, err = handler.resolveAccountNumber(req.AccountNumber, entity)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		created, err := logic.Payee.Create(req)
+		if err != nil {
+			l.Logger.Error("[Error] PayeeHandler.createPayee failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewPayeeRespond(created)})
+	}
+}
+
+// PATCH /user/entities/{entityID}/payees/{payeeID}
+
+func (handler *payeeHandler) updatePayee() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.PayeeRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r, constant.EntityRole.Transactor)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		payee, err := handler.findPayee(r, entity)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		req, errs := types.NewUpdatePayeeReq(r, entity, payee)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+		req.AccountNumber, err = handler.resolveAccountNumber(req.AccountNumber, entity)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		updated, err := logic.Payee.FindOneAndUpdate(req)
+		if err != nil {
+			l.Logger.Error("[Error] PayeeHandler.updatePayee failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewPayeeRespond(updated)})
+	}
+}
+
+// DELETE /user/entities/{entityID}/payees/{payeeID}
+
+func (handler *payeeHandler) deletePayee() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.PayeeRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, status, err := handler.findEntity(r, constant.EntityRole.Transactor)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		payee, err := handler.findPayee(r, entity)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		err = logic.Payee.Delete(payee.ID)
+		if err != nil {
+			l.Logger.Error("[Error] PayeeHandler.deletePayee failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewPayeeRespond(payee)})
+	}
+}
@@ -0,0 +1,349 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var ReviewHandler = newReviewHandler()
+
+type reviewHandler struct {
+	once *sync.Once
+}
+
+func newReviewHandler() *reviewHandler {
+	return &reviewHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *reviewHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		public.Path("/entities/{searchEntityID}/reviews").HandlerFunc(handler.searchEntityReviews()).Methods("GET")
+		private.Path("/transfers/{transferID}/reviews").HandlerFunc(handler.createReview()).Methods("POST")
+		private.Path("/transfers/{transferID}/reviews").HandlerFunc(handler.getTransferReviews()).Methods("GET")
+		private.Path("/reviews/{reviewID}/reply").HandlerFunc(handler.replyReview()).Methods("POST")
+
+		adminPrivate.Path("/reviews").HandlerFunc(handler.adminSearchReviews()).Methods("GET")
+		adminPrivate.Path("/reviews/{reviewID}").HandlerFunc(handler.adminUpdateReview()).Methods("PATCH")
+		adminPrivate.Path("/reviews/{reviewID}").HandlerFunc(handler.adminDeleteReview()).Methods("DELETE")
+	})
+}
+
+// findParties returns the party of the transfer the user can act for with at least the role and the other party.
+func (handler *reviewHandler) findParties(j *types.Journal, userID string, role string) (*types.Entity, *types.Entity, int, error) {
+	from, fromErr := logic.Entity.FindByAccountNumber(j.FromAccountNumber)
+	to, toErr := logic.Entity.FindByAccountNumber(j.ToAccountNumber)
+
+	var party, counterparty *types.Entity
+	if fromErr == nil && UserHandler.HasEntityRole(from.ID.Hex(), userID, role) {
+		party, counterparty = from, to
+		if toErr != nil {
+			counterparty = nil
+		}
+	} else if toErr == nil && UserHandler.HasEntityRole(to.ID.Hex(), userID, role) {
+		party, counterparty = to, from
+		if fromErr != nil {
+			counterparty = nil
+		}
+	}
+	if party == nil {
+		return nil, nil, http.StatusForbidden, api.ErrPermissionDenied
+	}
+	return party, counterparty, http.StatusOK, nil
+}
+
+// visible filters out the reviews hidden by admins.
+func (handler *reviewHandler) visible(reviews []*types.Review) []*types.Review {
+	result := []*types.Review{}
+	for _, review := range reviews {
+		if !review.Hidden {
+			result = append(result, review)
+		}
+	}
+	return result
+}
+
+// POST /transfers/{transferID}/reviews
+
+func (handler *reviewHandler) createReview() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.ReviewRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		journal, err := logic.Transfer.FindByID(mux.Vars(r)["transferID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		reviewer, reviewee, status, err := handler.findParties(journal, r.Header.Get("userID"), constant.EntityRole.Transactor)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		if reviewee == nil {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("The other party of this transfer cannot be reviewed."))
+			return
+		}
+
+		req, errs := types.NewCreateReviewReq(r, journal, reviewer, reviewee)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+		reviewed, err := logic.Review.HasReviewed(journal.TransferID, reviewer.AccountNumber)
+		if err != nil {
+			l.Logger.Error("[Error] ReviewHandler.createReview failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if reviewed {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("You have already reviewed this transfer."))
+			return
+		}
+
+		review, err := logic.Review.Create(req)
+		if err != nil {
+			l.Logger.Error("[Error] ReviewHandler.createReview failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go email.EntityReview(&email.EntityReviewEmail{
+			ReviewerEntityName: reviewer.Name,
+			ReceiverEntityName: reviewee.Name,
+			ReceiverEmail:      reviewee.Email,
+			Rating:             review.Rating,
+			Body:               review.Body,
+			TransferID:         review.TransferID,
+		})
+		go logic.UserAction.ReviewEntity(r.Header.Get("userID"), review)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewReviewRespond(review)})
+	}
+}
+
+// GET /transfers/{transferID}/reviews
+
+func (handler *reviewHandler) getTransferReviews() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.ReviewRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		journal, err := logic.Transfer.FindByID(mux.Vars(r)["transferID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		_, _, status, err := handler.findParties(journal, r.Header.Get("userID"), constant.EntityRole.Viewer)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+
+		reviews, err := logic.Review.FindByTransferID(journal.TransferID)
+		if err != nil {
+			l.Logger.Error("[Error] ReviewHandler.getTransferReviews failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewReviewsRespond(handler.visible(reviews))})
+	}
+}
+
+// GET /entities/{searchEntityID}/reviews
+
+func (handler *reviewHandler) searchEntityReviews() func(http.ResponseWriter, *http.Request) {
+	type meta struct {
+		NumberOfResults int `json:"numberOfResults"`
+		TotalPages      int `json:"totalPages"`
+	}
+	type respond struct {
+		Data []*types.ReviewRespond `json:"data"`
+		Meta meta                   `json:"meta"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewSearchReviewReq(r.URL.Query())
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["searchEntityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		hidden := false
+		req.RevieweeAccountNumber = entity.AccountNumber
+		req.Hidden = &hidden
+
+		found, err := logic.Review.Search(req)
+		if err != nil {
+			l.Logger.Error("[Error] ReviewHandler.searchEntityReviews failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{
+			Data: types.NewReviewsRespond(found.Reviews),
+			Meta: meta{
+				TotalPages:      found.TotalPages,
+				NumberOfResults: found.NumberOfResults,
+			},
+		})
+	}
+}
+
+// POST /reviews/{reviewID}/reply
+
+func (handler *reviewHandler) replyReview() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.ReviewRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, err := logic.Review.FindByReviewID(mux.Vars(r)["reviewID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		// Only the reviewed entity has the right of reply.
+		reviewee, err := logic.Entity.FindByAccountNumber(review.RevieweeAccountNumber)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if !UserHandler.HasEntityRole(reviewee.ID.Hex(), r.Header.Get("userID"), constant.EntityRole.Transactor) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		req, errs := types.NewReplyReviewReq(r, review)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		updated, err := logic.Review.Reply(req)
+		if err != nil {
+			l.Logger.Error("[Error] ReviewHandler.replyReview failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.UserAction.ReplyReview(r.Header.Get("userID"), updated)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewReviewRespond(updated)})
+	}
+}
+
+// GET /admin/reviews
+
+func (handler *reviewHandler) adminSearchReviews() func(http.ResponseWriter, *http.Request) {
+	type meta struct {
+		NumberOfResults int `json:"numberOfResults"`
+		TotalPages      int `json:"totalPages"`
+	}
+	type respond struct {
+		Data []*types.AdminReviewRespond `json:"data"`
+		Meta meta                        `json:"meta"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewSearchReviewReq(r.URL.Query())
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+		var err error
+		req.RevieweeAccountNumber, err = logic.Handle.Resolve(req.RevieweeAccountNumber)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		found, err := logic.Review.Search(req)
+		if err != nil {
+			l.Logger.Error("[Error] ReviewHandler.adminSearchReviews failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{
+			Data: types.NewAdminReviewsRespond(found.Reviews),
+			Meta: meta{
+				TotalPages:      found.TotalPages,
+				NumberOfResults: found.NumberOfResults,
+			},
+		})
+	}
+}
+
+// PATCH /admin/reviews/{reviewID}
+
+func (handler *reviewHandler) adminUpdateReview() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminReviewRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, err := logic.Review.FindByReviewID(mux.Vars(r)["reviewID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		req, errs := types.NewAdminUpdateReviewReq(r, review)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		updated, err := logic.Review.AdminUpdate(req)
+		if err != nil {
+			l.Logger.Error("[Error] ReviewHandler.adminUpdateReview failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.UserAction.AdminModerateReview(r.Header.Get("userID"), updated)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminReviewRespond(updated)})
+	}
+}
+
+// DELETE /admin/reviews/{reviewID}
+
+func (handler *reviewHandler) adminDeleteReview() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminReviewRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, err := logic.Review.FindByReviewID(mux.Vars(r)["reviewID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		err = logic.Review.AdminDelete(review)
+		if err != nil {
+			l.Logger.Error("[Error] ReviewHandler.adminDeleteReview failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.UserAction.AdminDeleteReview(r.Header.Get("userID"), review)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminReviewRespond(review)})
+	}
+}
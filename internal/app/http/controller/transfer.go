@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"io"
@@ -14,6 +15,7 @@ import (
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"github.com/ic3network/mccs-alpha-api/util"
 	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"go.uber.org/zap"
 )
@@ -39,11 +41,20 @@ func (handler *transferHandler) RegisterRoutes(
 	handler.once.Do(func() {
 		private.Path("/transfers").HandlerFunc(handler.proposeTransfer()).Methods("POST")
 		private.Path("/transfers").HandlerFunc(handler.searchTransfer()).Methods("GET")
+		private.Path("/transfers/export").HandlerFunc(handler.exportTransfers()).Methods("GET")
+		private.Path("/transfers/{transferID}").HandlerFunc(handler.getTransfer()).Methods("GET")
 		private.Path("/transfers/{transferID}").HandlerFunc(handler.updateTransfer()).Methods("PATCH")
+		private.Path("/transfers/{transferID}/receipt.pdf").HandlerFunc(handler.getTransferReceipt()).Methods("GET")
+		private.Path("/transfers/{transferID}/comments").HandlerFunc(handler.createTransferComment()).Methods("POST")
+		private.Path("/transfers/{transferID}/comments").HandlerFunc(handler.getTransferComments()).Methods("GET")
 
-		adminPrivate.Path("/transfers").HandlerFunc(handler.adminCreateTransfer()).Methods("POST")
+		adminPrivate.Path("/transfers").HandlerFunc(handler.adminCreateTransfer(constant.TransferType.AdminTransfer)).Methods("POST")
+		adminPrivate.Path("/transfers/corrections").HandlerFunc(handler.adminCreateTransfer(constant.TransferType.Correction)).Methods("POST")
 		adminPrivate.Path("/transfers").HandlerFunc(handler.adminSearchTransfer()).Methods("GET")
+		adminPrivate.Path("/transfers/approvals").HandlerFunc(handler.adminGetPendingApproval()).Methods("GET")
+		adminPrivate.Path("/transfers/export").HandlerFunc(handler.adminExportTransfers()).Methods("GET")
 		adminPrivate.Path("/transfers/{transferID}").HandlerFunc(handler.adminGetTransfer()).Methods("GET")
+		adminPrivate.Path("/transfers/{transferID}").HandlerFunc(handler.adminUpdateTransfer()).Methods("PATCH")
 	})
 }
 
@@ -60,16 +71,37 @@ func (handler *transferHandler) proposeTransfer() func(http.ResponseWriter, *htt
 			return
 		}
 
-		if !UserHandler.IsEntityBelongsToUser(req.InitiatorEntity.ID.Hex(), r.Header.Get("userID")) {
+		if !UserHandler.HasEntityRole(req.InitiatorEntity.ID.Hex(), r.Header.Get("userID"), constant.EntityRole.Transactor) {
 			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
 			return
 		}
 
-		err := logic.Transfer.CheckBalance(req.FromAccountNumber, req.ToAccountNumber, req.Amount)
+		err := logic.Transfer.CheckFreeze(req.FromAccountNumber, req.ToAccountNumber)
 		if err != nil {
 			api.Respond(w, r, http.StatusBadRequest, err)
 			return
 		}
+		err = logic.Transfer.CheckBalance(req.FromAccountNumber, req.ToAccountNumber, req.Amount)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if req.Escrow {
+			escrowed, err := logic.Transfer.Escrow(req)
+			if err != nil {
+				l.Logger.Error("[Error] TransferHandler.proposeTransfer failed:", zap.Error(err))
+				api.Respond(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			go logic.UserAction.ProposeTransfer(r.Header.Get("userID"), req)
+			if req.Payee != nil {
+				go logic.Payee.UpdateLastUsedAt(req.Payee.ID)
+			}
+			api.Respond(w, r, http.StatusOK, respond{Data: types.NewProposeTransferRespond(escrowed)})
+			go logic.Email.Transfer.Escrow(escrowed)
+			return
+		}
 
 		journal, err := logic.Transfer.Propose(req)
 		if err != nil {
@@ -77,10 +109,25 @@ func (handler *transferHandler) proposeTransfer() func(http.ResponseWriter, *htt
 			api.Respond(w, r, http.StatusInternalServerError, err)
 			return
 		}
+		go logic.UserAction.ProposeTransfer(r.Header.Get("userID"), req)
+		if req.Payee != nil {
+			go logic.Payee.UpdateLastUsedAt(req.Payee.ID)
+		}
+
+		if logic.Transfer.ShouldAutoAccept(req) {
+			accepted, err := handler.acceptTransfer(journal)
+			if err == nil {
+				api.Respond(w, r, http.StatusOK, respond{Data: types.NewProposeTransferRespond(accepted)})
+				go logic.UserAction.AutoAcceptTransfer(req.ReceiverEntity, accepted)
+				go logic.Email.Transfer.AutoAccept(accepted)
+				return
+			}
+			// The transfer has been initiated, so it stays pending for the receiver to accept it.
+			l.Logger.Error("[Error] TransferHandler.proposeTransfer auto accept failed:", zap.Error(err))
+		}
 
 		api.Respond(w, r, http.StatusOK, respond{Data: types.NewProposeTransferRespond(journal)})
 
-		go logic.UserAction.ProposeTransfer(r.Header.Get("userID"), req)
 		go logic.Email.Transfer.Initiate(req)
 	}
 }
@@ -95,15 +142,43 @@ func (handler *transferHandler) newTransferReq(r *http.Request) (*types.Transfer
 		}
 		return nil, []error{err}
 	}
+	body.InitiatorAccountNumber, err = logic.Handle.Resolve(body.InitiatorAccountNumber)
+	if err != nil {
+		return nil, []error{err}
+	}
+	body.ReceiverAccountNumber, err = logic.Handle.Resolve(body.ReceiverAccountNumber)
+	if err != nil {
+		return nil, []error{err}
+	}
 	initiatorEntity, err := logic.Entity.FindByAccountNumber(body.InitiatorAccountNumber)
 	if err != nil {
 		return nil, []error{err}
 	}
+	payee, err := handler.findPayee(&body, initiatorEntity)
+	if err != nil {
+		return nil, []error{err}
+	}
 	receiverEntity, err := logic.Entity.FindByAccountNumber(body.ReceiverAccountNumber)
 	if err != nil {
 		return nil, []error{err}
 	}
-	return types.NewTransferReq(&body, initiatorEntity, receiverEntity)
+	return types.NewTransferReq(&body, initiatorEntity, receiverEntity, payee)
+}
+
+// findPayee resolves the receiver from the initiator's payees when a payeeID is given.
+func (handler *transferHandler) findPayee(body *types.TransferUserReq, initiatorEntity *types.Entity) (*types.Payee, error) {
+	if body.PayeeID == "" {
+		return nil, nil
+	}
+	payee, err := logic.Payee.FindByStringID(body.PayeeID)
+	if err != nil || payee.EntityID != initiatorEntity.ID {
+		return nil, errors.New("Payee not found.")
+	}
+	if body.ReceiverAccountNumber != "" && body.ReceiverAccountNumber != payee.AccountNumber {
+		return nil, errors.New("Please specify either the receiver or the payee.")
+	}
+	body.ReceiverAccountNumber = payee.AccountNumber
+	return payee, nil
 }
 
 // GET /transfers
@@ -158,6 +233,210 @@ func (handler *transferHandler) newSearchTransferQuery(r *http.Request) (*types.
 	return req, nil
 }
 
+// GET /transfers/export
+
+func (handler *transferHandler) exportTransfers() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(r.URL.Query().Get("querying_entity_id"))
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if !UserHandler.IsEntityBelongsToUser(entity.ID.Hex(), r.Header.Get("userID")) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+		req, errs := types.NewTransferExportQuery(r, entity)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		buf := &bytes.Buffer{}
+		err = logic.TransferExport.Export(req, buf)
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.exportTransfers failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.UserAction.ExportTransfers(r.Header.Get("userID"), req)
+
+		handler.writeExport(w, req, buf)
+	}
+}
+
+func (handler *transferHandler) writeExport(w http.ResponseWriter, req *types.TransferExportReq, buf *bytes.Buffer) {
+	w.Header().Set("Content-Type", logic.TransferExport.ContentType(req))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+logic.TransferExport.FileName(req)+`"`)
+	buf.WriteTo(w)
+}
+
+// GET /transfers/{transferID}
+
+func (handler *transferHandler) getTransfer() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.TransferDetailRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		journal, err := logic.Transfer.FindByID(mux.Vars(r)["transferID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		entity, err := handler.findPartyEntity(journal, r.Header.Get("userID"), constant.EntityRole.Viewer)
+		if err != nil {
+			api.Respond(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		counterpartyAccountNumber := journal.ToAccountNumber
+		if entity.AccountNumber == journal.ToAccountNumber {
+			counterpartyAccountNumber = journal.FromAccountNumber
+		}
+		// System accounts don't have an entity.
+		counterparty, _ := logic.Entity.FindByAccountNumber(counterpartyAccountNumber)
+
+		postings, err := logic.Transfer.FindPostings(journal)
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.getTransfer failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		comments, err := logic.TransferComment.FindByTransferID(journal.TransferID)
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.getTransfer failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewTransferDetailRespond(journal, entity, counterparty, postings, comments)})
+	}
+}
+
+// findPartyEntity returns the party of the transfer the user belongs to with at least the role.
+// System accounts, such as the escrow account, don't have an entity and are skipped.
+func (handler *transferHandler) findPartyEntity(j *types.Journal, userID string, role string) (*types.Entity, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, accountNumber := range []string{j.FromAccountNumber, j.ToAccountNumber} {
+		entity, err := logic.Entity.FindByAccountNumber(accountNumber)
+		if err != nil {
+			continue
+		}
+		if entity.HasRole(objID, role) {
+			return entity, nil
+		}
+	}
+	return nil, errors.New("You don't have permission to perform this action.")
+}
+
+// GET /transfers/{transferID}/receipt.pdf
+
+func (handler *transferHandler) getTransferReceipt() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		journal, err := logic.Transfer.FindByID(mux.Vars(r)["transferID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		entity, err := handler.findPartyEntity(journal, r.Header.Get("userID"), constant.EntityRole.Viewer)
+		if err != nil {
+			api.Respond(w, r, http.StatusForbidden, err)
+			return
+		}
+		if journal.Status != constant.Transfer.Completed {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("Receipts are only available for completed transfers."))
+			return
+		}
+
+		counterpartyAccountNumber := journal.ToAccountNumber
+		if entity.AccountNumber == journal.ToAccountNumber {
+			counterpartyAccountNumber = journal.FromAccountNumber
+		}
+		// System accounts don't have an entity.
+		counterparty, _ := logic.Entity.FindByAccountNumber(counterpartyAccountNumber)
+
+		content, document, err := logic.Document.Receipt(journal, entity, counterparty, r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.getTransferReceipt failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.UserAction.IssueDocument(r.Header.Get("userID"), document)
+
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="receipt-`+journal.TransferID+`.pdf"`)
+		w.Write(content)
+	}
+}
+
+// POST /transfers/{transferID}/comments
+
+func (handler *transferHandler) createTransferComment() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.TransferCommentRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		journal, err := logic.Transfer.FindByID(mux.Vars(r)["transferID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		entity, err := handler.findPartyEntity(journal, r.Header.Get("userID"), constant.EntityRole.Transactor)
+		if err != nil {
+			api.Respond(w, r, http.StatusForbidden, err)
+			return
+		}
+		req, errs := types.NewTransferCommentReq(r, journal, entity)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		comment, err := logic.TransferComment.Create(req)
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.createTransferComment failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.Email.Transfer.Comment(journal, comment)
+		go logic.UserAction.CommentTransfer(r.Header.Get("userID"), comment)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewTransferCommentRespond(comment)})
+	}
+}
+
+// GET /transfers/{transferID}/comments
+
+func (handler *transferHandler) getTransferComments() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.TransferCommentRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		journal, err := logic.Transfer.FindByID(mux.Vars(r)["transferID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		_, err = handler.findPartyEntity(journal, r.Header.Get("userID"), constant.EntityRole.Viewer)
+		if err != nil {
+			api.Respond(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		comments, err := logic.TransferComment.FindByTransferID(journal.TransferID)
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.getTransferComments failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewTransferCommentsRespond(comments)})
+	}
+}
+
 // PATCH /transfers/{transferID}
 
 func (handler *transferHandler) updateTransfer() func(http.ResponseWriter, *http.Request) {
@@ -206,6 +485,25 @@ func (handler *transferHandler) updateTransfer() func(http.ResponseWriter, *http
 			api.Respond(w, r, http.StatusUnauthorized, err)
 			return
 		}
+
+		if req.Journal.Status == constant.Transfer.Escrowed {
+			updated, status, err := handler.settleEscrow(req.Journal, req.Action, req.CancellationReason)
+			if err != nil {
+				api.Respond(w, r, status, err)
+				return
+			}
+			go logic.UserAction.SettleEscrow(r.Header.Get("userID"), updated)
+			api.Respond(w, r, http.StatusOK, respond{generateRespond(req, updated)})
+			return
+		}
+
+		if req.Action == "accept" {
+			err = logic.Transfer.CheckFreeze(req.Journal.FromAccountNumber, req.Journal.ToAccountNumber)
+			if err != nil {
+				api.Respond(w, r, http.StatusBadRequest, err)
+				return
+			}
+		}
 		err = handler.checkBalances(req)
 		if err != nil {
 			api.Respond(w, r, http.StatusBadRequest, err)
@@ -264,13 +562,29 @@ func (handler *transferHandler) newUpdateTransferReq(r *http.Request) (*types.Up
 	return types.NewUpdateTransferReq(r, journal, initiateEntity, fromEntity, toEntity)
 }
 
+// canTransact checks whether the user is a member of the entity with at least the transactor role.
+func (handler *transferHandler) canTransact(entity *types.Entity, userID string) bool {
+	return util.ContainID(entity.Users, userID) && entity.HasRole(util.ToObjectID(userID), constant.EntityRole.Transactor)
+}
+
 func (handler *transferHandler) checkPermissions(req *types.UpdateTransferReq) error {
-	if !util.ContainID(req.FromEntity.Users, req.LoggedInUserID) && !util.ContainID(req.ToEntity.Users, req.LoggedInUserID) {
+	if !handler.canTransact(req.FromEntity, req.LoggedInUserID) && !handler.canTransact(req.ToEntity, req.LoggedInUserID) {
+		return errors.New("You don't have permission to perform this action.")
+	}
+
+	// The payer releases the escrow once the goods have arrived and the payee can refund it.
+	if req.Journal.Status == constant.Transfer.Escrowed {
+		if req.Action == "release" && handler.canTransact(req.FromEntity, req.LoggedInUserID) {
+			return nil
+		}
+		if req.Action == "refund" && handler.canTransact(req.ToEntity, req.LoggedInUserID) {
+			return nil
+		}
 		return errors.New("You don't have permission to perform this action.")
 	}
 
-	// If the logged in user is the owner of the initiate entity, then the user can only "cancel" the transfer.
-	if util.ContainID(req.InitiateEntity.Users, req.LoggedInUserID) {
+	// If the logged in user is a transactor of the initiate entity, then the user can only "cancel" the transfer.
+	if handler.canTransact(req.InitiateEntity, req.LoggedInUserID) {
 		if req.Action != "cancel" {
 			return errors.New("You don't have permission to perform this action.")
 		}
@@ -293,13 +607,19 @@ func (handler *transferHandler) checkBalances(req *types.UpdateTransferReq) erro
 		return err
 	}
 
-	exceed, err := logic.BalanceLimit.IsExceedLimit(fromAccount.AccountNumber, fromAccount.Balance-req.Journal.Amount)
+	// The amount reserved by the sender's vouchers is not available for sending.
+	reserved, err := logic.Voucher.ReservedAmount(fromAccount.AccountNumber)
+	if err != nil {
+		return err
+	}
+
+	exceed, err := logic.BalanceLimit.IsExceedLimit(fromAccount.AccountNumber, fromAccount.Balance-reserved-req.Journal.Amount)
 	if err != nil {
 		return err
 	}
 	if exceed {
 		reason := "The sender will exceed its credit limit so this transfer has been cancelled."
-		_, err = logic.Transfer.Cancel(req.Journal.TransferID, reason)
+		_, err = logic.Transfer.Cancel(req.Journal.TransferID, reason, "")
 		if err != nil {
 			l.Logger.Error("[Error] TransferHandler.updateTransfer failed:", zap.Error(err))
 			return err
@@ -314,7 +634,7 @@ func (handler *transferHandler) checkBalances(req *types.UpdateTransferReq) erro
 	}
 	if exceed {
 		reason := "The recipient will exceed its maximum positive balance threshold so this transfer has been cancelled."
-		_, err = logic.Transfer.Cancel(req.Journal.TransferID, reason)
+		_, err = logic.Transfer.Cancel(req.Journal.TransferID, reason, "")
 		if err != nil {
 			l.Logger.Error("[Error] TransferHandler.updateTransfer failed:", zap.Error(err))
 			return err
@@ -327,7 +647,7 @@ func (handler *transferHandler) checkBalances(req *types.UpdateTransferReq) erro
 }
 
 func (handler *transferHandler) acceptTransfer(j *types.Journal) (*types.Journal, error) {
-	updated, err := logic.Transfer.Accept(j)
+	updated, err := logic.Transfer.Accept(j, j.ReceiverAccountNumber())
 	if err != nil {
 		return nil, err
 	}
@@ -336,7 +656,7 @@ func (handler *transferHandler) acceptTransfer(j *types.Journal) (*types.Journal
 }
 
 func (handler *transferHandler) rejectTransfer(j *types.Journal, reason string) (*types.Journal, error) {
-	updated, err := logic.Transfer.Cancel(j.TransferID, reason)
+	updated, err := logic.Transfer.Cancel(j.TransferID, reason, j.ReceiverAccountNumber())
 	if err != nil {
 		return nil, err
 	}
@@ -345,7 +665,7 @@ func (handler *transferHandler) rejectTransfer(j *types.Journal, reason string)
 }
 
 func (handler *transferHandler) cancelTransfer(j *types.Journal, reason string) (*types.Journal, error) {
-	updated, err := logic.Transfer.Cancel(j.TransferID, reason)
+	updated, err := logic.Transfer.Cancel(j.TransferID, reason, j.InitiatedBy)
 	if err != nil {
 		return nil, err
 	}
@@ -353,6 +673,31 @@ func (handler *transferHandler) cancelTransfer(j *types.Journal, reason string)
 	return updated, nil
 }
 
+// settleEscrow releases the escrowed amount to the payee or refunds it to the payer.
+func (handler *transferHandler) settleEscrow(j *types.Journal, action string, reason string) (*types.Journal, int, error) {
+	if action == "release" {
+		err := logic.Transfer.CheckReleaseEscrow(j)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		released, err := logic.Transfer.ReleaseEscrow(j)
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.settleEscrow failed:", zap.Error(err))
+			return nil, http.StatusInternalServerError, err
+		}
+		go logic.Email.Transfer.EscrowRelease(released)
+		return released, http.StatusOK, nil
+	}
+
+	refunded, err := logic.Transfer.RefundEscrow(j, reason)
+	if err != nil {
+		l.Logger.Error("[Error] TransferHandler.settleEscrow failed:", zap.Error(err))
+		return nil, http.StatusInternalServerError, err
+	}
+	go logic.Email.Transfer.EscrowRefund(refunded, reason)
+	return refunded, http.StatusOK, nil
+}
+
 // GET /admin/transfers
 
 func (handler *transferHandler) adminSearchTransfer() func(http.ResponseWriter, *http.Request) {
@@ -370,6 +715,12 @@ func (handler *transferHandler) adminSearchTransfer() func(http.ResponseWriter,
 			api.Respond(w, r, http.StatusBadRequest, errs)
 			return
 		}
+		accountNumber, err := logic.Handle.Resolve(req.AccountNumber)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req.AccountNumber = accountNumber
 
 		found, err := logic.Transfer.AdminSearch(req)
 		if err != nil {
@@ -389,25 +740,39 @@ func (handler *transferHandler) adminSearchTransfer() func(http.ResponseWriter,
 }
 
 // POST /admin/transfers
+// POST /admin/transfers/corrections
 
-func (handler *transferHandler) adminCreateTransfer() func(http.ResponseWriter, *http.Request) {
+func (handler *transferHandler) adminCreateTransfer(transferType string) func(http.ResponseWriter, *http.Request) {
 	type respond struct {
 		Data *types.AdminTransferRespond `json:"data"`
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
-		req, errs := handler.newAdminTransferReq(r)
+		req, errs := handler.newAdminTransferReq(r, transferType)
 		if len(errs) > 0 {
 			api.Respond(w, r, http.StatusBadRequest, errs)
 			return
 		}
 
-		err := logic.Transfer.CheckBalance(req.PayerEntity.AccountNumber, req.PayeeEntity.AccountNumber, req.Amount)
+		// Frozen accounts can't move money through any admin journal, only the balance limits depend on the type.
+		err := logic.Transfer.CheckFreeze(req.PayerEntity.AccountNumber, req.PayeeEntity.AccountNumber)
 		if err != nil {
 			api.Respond(w, r, http.StatusBadRequest, err)
 			return
 		}
+		if constant.TransferTypeRules[transferType].EnforceLimits {
+			err = logic.Transfer.CheckBalance(req.PayerEntity.AccountNumber, req.PayeeEntity.AccountNumber, req.Amount)
+			if err != nil {
+				api.Respond(w, r, http.StatusBadRequest, err)
+				return
+			}
+		}
 
-		journal, err := logic.Transfer.Create(req)
+		var journal *types.Journal
+		if logic.Transfer.RequiresApproval(req) {
+			journal, err = logic.Transfer.CreatePendingApproval(req, r.Header.Get("userID"))
+		} else {
+			journal, err = logic.Transfer.Create(req)
+		}
 		if err != nil {
 			l.Logger.Error("[Error] TransferHandler.adminCreateTransfer failed:", zap.Error(err))
 			api.Respond(w, r, http.StatusInternalServerError, err)
@@ -420,7 +785,7 @@ func (handler *transferHandler) adminCreateTransfer() func(http.ResponseWriter,
 	}
 }
 
-func (handler *transferHandler) newAdminTransferReq(r *http.Request) (*types.AdminTransferReq, []error) {
+func (handler *transferHandler) newAdminTransferReq(r *http.Request, transferType string) (*types.AdminTransferReq, []error) {
 	var body types.AdminTransferUserReq
 	decoder := json.NewDecoder(r.Body)
 	err := decoder.Decode(&body)
@@ -430,6 +795,14 @@ func (handler *transferHandler) newAdminTransferReq(r *http.Request) (*types.Adm
 		}
 		return nil, []error{err}
 	}
+	body.Payer, err = logic.Handle.Resolve(body.Payer)
+	if err != nil {
+		return nil, []error{err}
+	}
+	body.Payee, err = logic.Handle.Resolve(body.Payee)
+	if err != nil {
+		return nil, []error{err}
+	}
 	payerEntity, err := logic.Entity.FindByAccountNumber(body.Payer)
 	if err != nil {
 		return nil, []error{err}
@@ -438,7 +811,40 @@ func (handler *transferHandler) newAdminTransferReq(r *http.Request) (*types.Adm
 	if err != nil {
 		return nil, []error{err}
 	}
-	return types.NewAdminTransferReq(&body, payerEntity, payeeEntity)
+	return types.NewAdminTransferReq(&body, payerEntity, payeeEntity, transferType)
+}
+
+// GET /admin/transfers/export
+
+func (handler *transferHandler) adminExportTransfers() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// The export covers every account unless one is specified.
+		var entity *types.Entity
+		if accountNumber := r.URL.Query().Get("account_number"); accountNumber != "" {
+			found, err := logic.Entity.FindByAccountNumber(accountNumber)
+			if err != nil {
+				api.Respond(w, r, http.StatusBadRequest, err)
+				return
+			}
+			entity = found
+		}
+		req, errs := types.NewTransferExportQuery(r, entity)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		buf := &bytes.Buffer{}
+		err := logic.TransferExport.Export(req, buf)
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.adminExportTransfers failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.UserAction.AdminExportTransfers(r.Header.Get("userID"), req)
+
+		handler.writeExport(w, req, buf)
+	}
 }
 
 // GET /admin/transfers/{transferID}
@@ -460,7 +866,95 @@ func (handler *transferHandler) adminGetTransfer() func(http.ResponseWriter, *ht
 			api.Respond(w, r, http.StatusInternalServerError, err)
 			return
 		}
+		comments, err := logic.TransferComment.FindByTransferID(journal.TransferID)
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.adminGetTransfer failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
 
-		api.Respond(w, r, http.StatusOK, respond{Data: types.NewJournalToAdminTransferRespond(journal)})
+		transfer := types.NewJournalToAdminTransferRespond(journal)
+		transfer.Comments = types.NewTransferCommentsRespond(comments)
+		api.Respond(w, r, http.StatusOK, respond{Data: transfer})
+	}
+}
+
+// PATCH /admin/transfers/{transferID}
+
+func (handler *transferHandler) adminUpdateTransfer() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminTransferRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		journal, err := logic.Transfer.AdminGetTransfer(mux.Vars(r)["transferID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req, errs := types.NewAdminUpdateTransferReq(r, journal)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if req.Action == "approve" || req.Action == "reject" {
+			updated, status, err := handler.reviewTransfer(req.Journal, req.Action, req.Reason, r.Header.Get("userID"))
+			if err != nil {
+				api.Respond(w, r, status, err)
+				return
+			}
+			go logic.UserAction.AdminReviewTransfer(r.Header.Get("userID"), updated)
+			api.Respond(w, r, http.StatusOK, respond{Data: types.NewJournalToAdminTransferRespond(updated)})
+			return
+		}
+
+		updated, status, err := handler.settleEscrow(req.Journal, req.Action, req.Reason)
+		if err != nil {
+			api.Respond(w, r, status, err)
+			return
+		}
+		go logic.UserAction.AdminSettleEscrow(r.Header.Get("userID"), updated)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewJournalToAdminTransferRespond(updated)})
+	}
+}
+
+// reviewTransfer approves or rejects an admin transfer pending approval.
+func (handler *transferHandler) reviewTransfer(j *types.Journal, action string, reason string, adminID string) (*types.Journal, int, error) {
+	if action == "approve" {
+		err := logic.Transfer.CheckApprove(j, adminID)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		approved, err := logic.Transfer.Approve(j, adminID)
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.reviewTransfer failed:", zap.Error(err))
+			return nil, http.StatusInternalServerError, err
+		}
+		return approved, http.StatusOK, nil
+	}
+
+	rejected, err := logic.Transfer.Reject(j, adminID, reason)
+	if err != nil {
+		l.Logger.Error("[Error] TransferHandler.reviewTransfer failed:", zap.Error(err))
+		return nil, http.StatusInternalServerError, err
+	}
+	return rejected, http.StatusOK, nil
+}
+
+// GET /admin/transfers/approvals
+
+func (handler *transferHandler) adminGetPendingApproval() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.AdminTransferRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		journals, err := logic.Transfer.GetPendingApproval()
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.adminGetPendingApproval failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewJournalsToAdminTransfersRespond(journals)})
 	}
 }
@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var TransferImportHandler = newTransferImportHandler()
+
+type transferImportHandler struct {
+	once *sync.Once
+}
+
+func newTransferImportHandler() *transferImportHandler {
+	return &transferImportHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *transferImportHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		adminPrivate.Path("/transfers/imports").HandlerFunc(handler.adminCreateTransferImport()).Methods("POST")
+		adminPrivate.Path("/transfers/imports/{importID}").HandlerFunc(handler.adminGetTransferImport()).Methods("GET")
+		adminPrivate.Path("/transfers/imports/{importID}/execution").HandlerFunc(handler.adminExecuteTransferImport()).Methods("POST")
+		adminPrivate.Path("/transfers/imports/{importID}/reset").HandlerFunc(handler.adminResetTransferImport()).Methods("POST")
+		adminPrivate.Path("/transfers/imports/{importID}/results.csv").HandlerFunc(handler.adminGetTransferImportResults()).Methods("GET")
+	})
+}
+
+// POST /admin/transfers/imports
+
+func (handler *transferImportHandler) adminCreateTransferImport() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.TransferImportRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, viper.GetInt64("transfer_import.max_file_size"))
+		file, fileName, err := handler.openFile(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		defer file.Close()
+
+		rows, err := logic.TransferImport.Parse(file)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		record, err := logic.TransferImport.Create(rows, fileName, r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] TransferImportHandler.adminCreateTransferImport failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.UserAction.AdminTransferImport(r.Header.Get("userID"), record)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewTransferImportRespond(record)})
+	}
+}
+
+// openFile accepts either a multipart form with a "file" field or the CSV file as the request body.
+func (handler *transferImportHandler) openFile(r *http.Request) (io.ReadCloser, string, error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.Body, "", nil
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, "", err
+	}
+	return file, header.Filename, nil
+}
+
+// GET /admin/transfers/imports/{importID}
+
+func (handler *transferImportHandler) adminGetTransferImport() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.TransferImportRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, err := logic.TransferImport.FindByImportID(mux.Vars(r)["importID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewTransferImportRespond(record)})
+	}
+}
+
+// POST /admin/transfers/imports/{importID}/execution
+
+func (handler *transferImportHandler) adminExecuteTransferImport() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.TransferImportRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, err := logic.TransferImport.FindByImportID(mux.Vars(r)["importID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req, errs := types.NewAdminExecuteTransferImportReq(r, record)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		executed, err := logic.TransferImport.Execute(req.Import, req.Mode, r.Header.Get("userID"))
+		if err == logic.ErrTransferImportExecuted {
+			api.Respond(w, r, http.StatusConflict, err)
+			return
+		}
+		if executed == nil {
+			l.Logger.Error("[Error] TransferImportHandler.adminExecuteTransferImport failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		// The atomic import is not executed when some rows are invalid, the report lists the errors.
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		go logic.UserAction.AdminTransferImport(r.Header.Get("userID"), executed)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewTransferImportRespond(executed)})
+	}
+}
+
+// POST /admin/transfers/imports/{importID}/reset
+
+func (handler *transferImportHandler) adminResetTransferImport() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.TransferImportRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, err := logic.TransferImport.FindByImportID(mux.Vars(r)["importID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if record.Status != constant.TransferImport.Executing {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("Only an executing import can be reset."))
+			return
+		}
+
+		reset, err := logic.TransferImport.Reset(record)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		go logic.UserAction.AdminResetTransferImport(r.Header.Get("userID"), reset)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewTransferImportRespond(reset)})
+	}
+}
+
+// GET /admin/transfers/imports/{importID}/results.csv
+
+func (handler *transferImportHandler) adminGetTransferImportResults() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, err := logic.TransferImport.FindByImportID(mux.Vars(r)["importID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="transfer-import-`+record.ImportID+`.csv"`)
+		err = logic.TransferImport.WriteResults(record, w)
+		if err != nil {
+			l.Logger.Error("[Error] TransferImportHandler.adminGetTransferImportResults failed:", zap.Error(err))
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gofrs/uuid"
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/api"
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
@@ -51,6 +52,7 @@ func (handler *userHandler) RegisterRoutes(
 		private.Path("/user").HandlerFunc(handler.userProfile()).Methods("GET")
 		private.Path("/user").HandlerFunc(handler.updateUser()).Methods("PATCH")
 		private.Path("/user/entities").HandlerFunc(handler.listUserEntities()).Methods("GET")
+		private.Path("/user/entities").HandlerFunc(handler.createUserEntity()).Methods("POST")
 		private.Path("/user/entities/{entityID}").HandlerFunc(handler.updateUserEntity()).Methods("PATCH")
 
 		adminPrivate.Path("/users").HandlerFunc(handler.adminSearchUser()).Methods("GET")
@@ -82,17 +84,27 @@ func (handler *userHandler) FindByEntityID(id string) (*types.User, error) {
 }
 
 func (handler *userHandler) IsEntityBelongsToUser(entityID, userID string) bool {
+	return handler.HasEntityRole(entityID, userID, constant.EntityRole.Viewer)
+}
+
+// HasEntityRole checks whether the user is a member of the entity with at least the given role.
+func (handler *userHandler) HasEntityRole(entityID, userID string, role string) bool {
 	uID, _ := primitive.ObjectIDFromHex(userID)
 	user, err := logic.User.FindByID(uID)
 	if err != nil {
 		return false
 	}
-	for _, entity := range user.Entities {
-		if entity.Hex() == entityID {
-			return true
-		}
+	if !util.ContainID(user.Entities, entityID) {
+		return false
+	}
+	if role == constant.EntityRole.Viewer {
+		return true
+	}
+	entity, err := logic.Entity.FindByStringID(entityID)
+	if err != nil {
+		return false
 	}
-	return false
+	return entity.HasRole(uID, role)
 }
 
 // POST /login
@@ -184,6 +196,8 @@ func (handler *userHandler) signup() func(http.ResponseWriter, *http.Request) {
 			Region:                             req.Region,
 			PostalCode:                         req.PostalCode,
 			Country:                            req.Country,
+			Latitude:                           req.Latitude,
+			Longitude:                          req.Longitude,
 			Offers:                             types.ToTagFields(req.Offers),
 			Wants:                              types.ToTagFields(req.Wants),
 			ShowTagsMatchedSinceLastLogin:      req.ShowTagsMatchedSinceLastLogin,
@@ -492,6 +506,64 @@ func (handler *userHandler) listUserEntities() func(http.ResponseWriter, *http.R
 	}
 }
 
+// POST /user/entities
+
+// createUserEntity creates another entity for the logged in user. The user becomes its owner and,
+// like a new signup, the entity stays pending until an administrator approves it.
+func (handler *userHandler) createUserEntity() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := logic.User.FindByStringID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] UserHandler.createUserEntity failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		req, errs := types.NewCreateUserEntityReq(r, user)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		createdEntity, err := logic.Entity.Create(req.Entity())
+		if err != nil {
+			l.Logger.Error("[Error] UserHandler.createUserEntity failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		err = logic.Entity.AssociateUser(createdEntity.ID, user.ID)
+		if err != nil {
+			l.Logger.Error("[Error] UserHandler.createUserEntity failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		err = logic.User.AssociateEntity(user.ID, createdEntity.ID)
+		if err != nil {
+			l.Logger.Error("[Error] UserHandler.createUserEntity failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		res, err := EntityHandler.NewEntityRespond(createdEntity)
+		if err != nil {
+			l.Logger.Error("[Error] UserHandler.createUserEntity failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.CreateEntity(user, createdEntity)
+		go email.Signup(&email.SignupNotificationEmail{
+			EntityName:   createdEntity.Name,
+			ContactEmail: createdEntity.Email,
+		})
+
+		api.Respond(w, r, http.StatusOK, respond{Data: res})
+	}
+}
+
 // PATCH /user/entities/{entityID}
 
 func (handler *userHandler) updateUserEntity() func(http.ResponseWriter, *http.Request) {
@@ -505,10 +577,17 @@ func (handler *userHandler) updateUserEntity() func(http.ResponseWriter, *http.R
 			return
 		}
 
-		if !handler.IsEntityBelongsToUser(req.OriginEntity.ID.Hex(), r.Header.Get("userID")) {
+		if !handler.HasEntityRole(req.OriginEntity.ID.Hex(), r.Header.Get("userID"), constant.EntityRole.Admin) {
 			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
 			return
 		}
+		if req.Handle != "" {
+			err := logic.Handle.CheckAvailable(req.Handle, req.OriginEntity.ID, false)
+			if err != nil {
+				api.Respond(w, r, http.StatusBadRequest, err)
+				return
+			}
+		}
 
 		updated, err := logic.Entity.FindOneAndUpdate(req)
 		if err != nil {
@@ -516,6 +595,10 @@ func (handler *userHandler) updateUserEntity() func(http.ResponseWriter, *http.R
 			api.Respond(w, r, http.StatusBadRequest, err)
 			return
 		}
+		err = logic.Handle.RecordChange(req.OriginEntity, updated)
+		if err != nil {
+			l.Logger.Error("[Error] UserHandler.updateUserEntity failed:", zap.Error(err))
+		}
 
 		go EntityHandler.UpdateOfferAndWants(&types.UpdateOfferAndWants{
 			EntityID:      req.OriginEntity.ID,
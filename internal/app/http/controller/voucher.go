@@ -0,0 +1,254 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var VoucherHandler = newVoucherHandler()
+
+type voucherHandler struct {
+	once *sync.Once
+}
+
+func newVoucherHandler() *voucherHandler {
+	return &voucherHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *voucherHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		private.Path("/vouchers").HandlerFunc(handler.issueVoucher()).Methods("POST")
+		private.Path("/vouchers").HandlerFunc(handler.searchVoucher()).Methods("GET")
+		private.Path("/vouchers/redeem").HandlerFunc(handler.redeemVoucher()).Methods("POST")
+		private.Path("/vouchers/{voucherID}").HandlerFunc(handler.cancelVoucher()).Methods("DELETE")
+	})
+}
+
+// POST /vouchers
+
+func (handler *voucherHandler) issueVoucher() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.VoucherRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newIssueVoucherReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if !UserHandler.HasEntityRole(req.PayerEntity.ID.Hex(), r.Header.Get("userID"), constant.EntityRole.Transactor) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		err := logic.Voucher.CheckIssue(req)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		voucher, key, err := logic.Voucher.Issue(req)
+		if err != nil {
+			l.Logger.Error("[Error] VoucherHandler.issueVoucher failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		go logic.UserAction.IssueVoucher(r.Header.Get("userID"), voucher)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewVoucherRespond(voucher, key.PublicKey)})
+	}
+}
+
+func (handler *voucherHandler) newIssueVoucherReq(r *http.Request) (*types.IssueVoucherReq, []error) {
+	var body types.IssueVoucherUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	body.Payer, err = logic.Handle.Resolve(body.Payer)
+	if err != nil {
+		return nil, []error{err}
+	}
+	payerEntity, err := logic.Entity.FindByAccountNumber(body.Payer)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return types.NewIssueVoucherReq(&body, payerEntity)
+}
+
+// GET /vouchers
+
+func (handler *voucherHandler) searchVoucher() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.VoucherRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewSearchVoucherReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if !UserHandler.IsEntityBelongsToUser(req.QueryingEntityID, r.Header.Get("userID")) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		entity, err := logic.Entity.FindByStringID(req.QueryingEntityID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		vouchers, err := logic.Voucher.FindByAccountNumber(entity.AccountNumber)
+		if err != nil {
+			l.Logger.Error("[Error] VoucherHandler.searchVoucher failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		publicKey, err := logic.Voucher.PublicKey(entity.AccountNumber)
+		if err != nil {
+			l.Logger.Error("[Error] VoucherHandler.searchVoucher failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewVouchersRespond(vouchers, publicKey)})
+	}
+}
+
+// POST /vouchers/redeem
+
+func (handler *voucherHandler) redeemVoucher() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.VoucherRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newRedeemVoucherReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if !UserHandler.HasEntityRole(req.PayeeEntity.ID.Hex(), r.Header.Get("userID"), constant.EntityRole.Transactor) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		err := logic.Voucher.CheckRedeem(req)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		journal, err := logic.Voucher.Redeem(req)
+		if err != nil {
+			l.Logger.Info("[INFO] VoucherHandler.redeemVoucher failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		go logic.UserAction.RedeemVoucher(r.Header.Get("userID"), journal)
+
+		redeemed, err := logic.Voucher.FindByID(req.Voucher.Nonce)
+		if err != nil {
+			l.Logger.Error("[Error] VoucherHandler.redeemVoucher failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		publicKey, err := logic.Voucher.PublicKey(redeemed.PayerAccountNumber)
+		if err != nil {
+			l.Logger.Error("[Error] VoucherHandler.redeemVoucher failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewVoucherRespond(redeemed, publicKey)})
+	}
+}
+
+func (handler *voucherHandler) newRedeemVoucherReq(r *http.Request) (*types.RedeemVoucherReq, []error) {
+	var body types.RedeemVoucherUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	voucher, err := logic.Voucher.Verify(body.Voucher)
+	if err != nil {
+		return nil, []error{err}
+	}
+	body.Payee, err = logic.Handle.Resolve(body.Payee)
+	if err != nil {
+		return nil, []error{err}
+	}
+	payeeEntity, err := logic.Entity.FindByAccountNumber(body.Payee)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return types.NewRedeemVoucherReq(voucher, payeeEntity)
+}
+
+// DELETE /vouchers/{voucherID}
+
+func (handler *voucherHandler) cancelVoucher() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.VoucherRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		voucher, err := logic.Voucher.FindByID(mux.Vars(r)["voucherID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		payerEntity, err := logic.Entity.FindByAccountNumber(voucher.PayerAccountNumber)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if !UserHandler.HasEntityRole(payerEntity.ID.Hex(), r.Header.Get("userID"), constant.EntityRole.Transactor) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		cancelled, err := logic.Voucher.Cancel(voucher.Nonce)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		go logic.UserAction.CancelVoucher(r.Header.Get("userID"), cancelled)
+
+		publicKey, err := logic.Voucher.PublicKey(cancelled.PayerAccountNumber)
+		if err != nil {
+			l.Logger.Error("[Error] VoucherHandler.cancelVoucher failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewVoucherRespond(cancelled, publicKey)})
+	}
+}
@@ -1,9 +1,12 @@
 package http
 
 import (
+	"log"
+
 	"github.com/gorilla/mux"
 	"github.com/ic3network/mccs-alpha-api/internal/app/http/controller"
 	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/storage"
 )
 
 func RegisterRoutes(r *mux.Router) {
@@ -15,6 +18,9 @@ func RegisterRoutes(r *mux.Router) {
 	adminPublic.Use(middleware.Recover(), middleware.RateLimiting(), middleware.NoCache(), middleware.Logging(), middleware.GetLoggedInUser())
 	adminPrivate := r.PathPrefix("/api/v1/admin").Subrouter()
 	adminPrivate.Use(middleware.Recover(), middleware.RateLimiting(), middleware.NoCache(), middleware.Logging(), middleware.GetLoggedInUser(), middleware.RequireAdmin())
+	// Feeds are cached by feed readers and proxies, their handlers set the caching headers.
+	feed := r.PathPrefix("/api/v1/feeds").Subrouter()
+	feed.Use(middleware.Recover(), middleware.RateLimiting(), middleware.Logging())
 
 	controller.ServiceDiscovery.RegisterRoutes(public, private)
 	controller.UserHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
@@ -23,5 +29,28 @@ func RegisterRoutes(r *mux.Router) {
 	controller.TagHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
 	controller.CategoryHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
 	controller.TransferHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.TransferImportHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.AccountHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.DocumentHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.EntityMemberHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.EntityImageHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.PayeeHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.VoucherHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.ReviewHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.EntityApplicationHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.EntityImportHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.FeedHandler.RegisterRoutes(feed)
 	controller.UserAction.RegisterRoutes(adminPrivate)
+
+	// Uploaded files are served by the API itself when they are stored on the local file system.
+	// The prefix is the path of "storage.local.url", so the URLs of the files are the ones they are served from.
+	if local, ok := storage.Default().(*storage.Local); ok {
+		prefix, err := local.PathPrefix()
+		if err != nil {
+			log.Fatal(err)
+		}
+		r.PathPrefix(prefix).Handler(local.Handler(prefix))
+	}
+	// Makes sure the private storage is configured before the server starts.
+	storage.Private()
 }
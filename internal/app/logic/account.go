@@ -48,3 +48,13 @@ func (a *account) FindByEntityID(entityID string) (*types.Account, error) {
 
 	return account, nil
 }
+
+// PATCH /admin/accounts/{accountNumber}/freeze
+
+func (a *account) UpdateFreeze(req *types.AdminFreezeAccountReq) (*types.Account, error) {
+	account, err := pg.Account.UpdateFreeze(req)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
@@ -0,0 +1,288 @@
+package logic
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type accountClosure struct{}
+
+var AccountClosure = &accountClosure{}
+
+// POST /admin/accounts/{accountNumber}/closure
+
+// Request starts a new closure or returns the one that is still in progress so it can be resumed.
+func (a *accountClosure) Request(req *types.AdminCloseAccountReq, adminUserID string) (*types.AccountClosure, error) {
+	closure, err := pg.AccountClosure.FindLatest(req.Account.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if closure != nil && closure.Step != constant.AccountClosure.Closed {
+		// The settlement account can still be changed as long as the balance hasn't been moved.
+		if req.SettlementEntity != nil && closure.SettlementTransferID == "" {
+			closure.SettlementAccountNumber = req.SettlementEntity.AccountNumber
+		}
+		if req.Reason != "" {
+			closure.Reason = req.Reason
+		}
+		return closure, nil
+	}
+
+	// Stop the account from moving money while it is being closed.
+	_, err = pg.Account.UpdateFreeze(&types.AdminFreezeAccountReq{
+		OriginAccount:   req.Account,
+		FrozenSending:   true,
+		FrozenReceiving: true,
+		Reason:          "Account closure requested.",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	closure = &types.AccountClosure{
+		AccountNumber: req.Account.AccountNumber,
+		EntityID:      req.Entity.ID.Hex(),
+		RequestedBy:   adminUserID,
+		Reason:        req.Reason,
+		Step:          constant.AccountClosure.Requested,
+	}
+	if req.SettlementEntity != nil {
+		closure.SettlementAccountNumber = req.SettlementEntity.AccountNumber
+	}
+	return pg.AccountClosure.Create(closure)
+}
+
+// Process runs the remaining steps of the closure. The progress is saved after every step
+// and the error of the failed step is kept on the closure until it is resumed.
+// adminUserID is the admin running the steps, a settlement above the approval threshold has to be approved by another admin.
+func (a *accountClosure) Process(closure *types.AccountClosure, adminUserID string) (*types.AccountClosure, error) {
+	for closure.Step != constant.AccountClosure.Closed {
+		stepErr := a.runNextStep(closure, adminUserID)
+		if stepErr != nil {
+			closure.LastError = stepErr.Error()
+			if len(closure.LastError) > 510 {
+				closure.LastError = closure.LastError[:510]
+			}
+		} else {
+			closure.LastError = ""
+		}
+
+		err := pg.AccountClosure.Update(closure)
+		if err != nil {
+			return nil, err
+		}
+		if stepErr != nil {
+			return closure, stepErr
+		}
+	}
+	return closure, nil
+}
+
+func (a *accountClosure) runNextStep(closure *types.AccountClosure, adminUserID string) error {
+	now := time.Now()
+
+	switch closure.Step {
+	case constant.AccountClosure.Requested:
+		err := a.cancelPendingTransfers(closure)
+		if err != nil {
+			return err
+		}
+		closure.Step = constant.AccountClosure.TransfersCancelled
+		closure.TransfersCancelledAt = &now
+	case constant.AccountClosure.TransfersCancelled:
+		err := a.settleBalance(closure, adminUserID)
+		if err != nil {
+			return err
+		}
+		closure.Step = constant.AccountClosure.BalanceSettled
+		closure.BalanceSettledAt = &now
+	case constant.AccountClosure.BalanceSettled:
+		zeroBalance, err := Account.IsZeroBalance(closure.AccountNumber)
+		if err != nil {
+			return err
+		}
+		if !zeroBalance {
+			// Go back to the settlement step so resuming the closure settles the balance again.
+			closure.Step = constant.AccountClosure.TransfersCancelled
+			closure.SettlementTransferID = ""
+			return errors.New("The account balance is not zero after the settlement.")
+		}
+		closure.Step = constant.AccountClosure.BalanceVerified
+		closure.BalanceVerifiedAt = &now
+	case constant.AccountClosure.BalanceVerified:
+		entityID, err := primitive.ObjectIDFromHex(closure.EntityID)
+		if err != nil {
+			return err
+		}
+		// Soft-deletes the entity together with its account and balance limits.
+		_, err = Entity.AdminFindOneAndDelete(entityID)
+		if err != nil {
+			return err
+		}
+		closure.Step = constant.AccountClosure.Closed
+		closure.ClosedAt = &now
+	default:
+		return errors.New("Unknown account closure step: " + closure.Step)
+	}
+
+	return nil
+}
+
+func (a *accountClosure) cancelPendingTransfers(closure *types.AccountClosure) error {
+	journals, err := pg.Journal.GetPending(closure.AccountNumber)
+	if err != nil {
+		return err
+	}
+	reason := "The account is being closed so this transfer has been cancelled."
+	for _, j := range journals {
+		_, err := Transfer.Cancel(j.TransferID, reason, "")
+		if err != nil {
+			return err
+		}
+		go Email.Transfer.CancelBySystem(j, reason)
+	}
+
+	escrowed, err := pg.Journal.GetEscrowed(closure.AccountNumber)
+	if err != nil {
+		return err
+	}
+	for _, j := range escrowed {
+		refunded, err := Transfer.RefundEscrow(j, reason)
+		if err != nil {
+			return err
+		}
+		go Email.Transfer.EscrowRefund(refunded, reason)
+	}
+
+	// The amount reserved by the vouchers would otherwise be redeemed after the balance has been settled.
+	err = Voucher.CancelByAccountNumber(closure.AccountNumber)
+	if err != nil {
+		return err
+	}
+
+	pendingApproval, err := pg.Journal.GetPendingApproval()
+	if err != nil {
+		return err
+	}
+	for _, j := range pendingApproval {
+		if j.FromAccountNumber != closure.AccountNumber && j.ToAccountNumber != closure.AccountNumber {
+			continue
+		}
+		_, err := Transfer.Cancel(j.TransferID, reason, "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settleBalance moves the residual balance to the settlement account like an admin transfer.
+// The closure waits at this step while the settlement is pending approval and is resumed once it has been approved.
+func (a *accountClosure) settleBalance(closure *types.AccountClosure, adminUserID string) error {
+	if closure.SettlementTransferID != "" {
+		return a.checkSettlement(closure)
+	}
+
+	account, err := pg.Account.FindByAccountNumber(closure.AccountNumber)
+	if err != nil {
+		return err
+	}
+	if account.Balance == 0.0 {
+		return nil
+	}
+	if closure.SettlementAccountNumber == "" {
+		return errors.New("The account has a non-zero balance. Please specify an account to settle the residual balance to.")
+	}
+
+	entity, err := Entity.FindByAccountNumber(closure.AccountNumber)
+	if err != nil {
+		return err
+	}
+	settlementEntity, err := Entity.FindByAccountNumber(closure.SettlementAccountNumber)
+	if err != nil {
+		return err
+	}
+
+	req := &types.AdminTransferReq{
+		PayerEntity:  entity,
+		PayeeEntity:  settlementEntity,
+		TransferType: constant.TransferType.AdminTransfer,
+		Amount:       math.Round(math.Abs(account.Balance)*100) / 100,
+		Description:  "Settlement of the residual balance on account closure.",
+	}
+	// A negative balance is settled by the designated account paying the debt off.
+	if account.Balance < 0 {
+		req.PayerEntity, req.PayeeEntity = settlementEntity, entity
+	}
+
+	errs := req.Validate()
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	// The account being closed is frozen, only the settlement account is checked.
+	if account.Balance > 0 {
+		err = Transfer.checkReceivingFreeze(settlementEntity.AccountNumber)
+	} else {
+		err = Transfer.checkSendingFreeze(settlementEntity.AccountNumber)
+	}
+	if err != nil {
+		return err
+	}
+	err = Transfer.CheckBalance(req.PayerEntity.AccountNumber, req.PayeeEntity.AccountNumber, req.Amount)
+	if err != nil {
+		return err
+	}
+
+	var journal *types.Journal
+	if Transfer.RequiresApproval(req) {
+		journal, err = Transfer.CreatePendingApproval(req, adminUserID)
+	} else {
+		journal, err = Transfer.Create(req)
+	}
+	if err != nil {
+		return err
+	}
+	closure.SettlementTransferID = journal.TransferID
+
+	go UserAction.AdminTransfer(adminUserID, journal)
+
+	return a.checkSettlement(closure)
+}
+
+// checkSettlement checks the settlement transfer has been completed.
+// A rejected settlement is forgotten so resuming the closure requests a new one.
+func (a *accountClosure) checkSettlement(closure *types.AccountClosure) error {
+	journal, err := Transfer.FindByID(closure.SettlementTransferID)
+	if err != nil {
+		return err
+	}
+	switch journal.Status {
+	case constant.Transfer.Completed:
+		return nil
+	case constant.Transfer.PendingApproval:
+		return errors.New("The settlement transfer " + journal.TransferID + " is waiting for the approval of another admin. Resume the closure once it has been approved.")
+	default:
+		closure.SettlementTransferID = ""
+		return errors.New("The settlement transfer " + journal.TransferID + " has been rejected. Resume the closure to request a new settlement.")
+	}
+}
+
+// GET /admin/accounts/{accountNumber}/closure
+
+func (a *accountClosure) FindLatest(accountNumber string) (*types.AccountClosure, error) {
+	closure, err := pg.AccountClosure.FindLatest(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if closure == nil {
+		return nil, errors.New("The closure of this account has not been requested.")
+	}
+	return closure, nil
+}
@@ -0,0 +1,40 @@
+package logic
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type applicationQuestion struct{}
+
+var ApplicationQuestion = &applicationQuestion{}
+
+// POST /admin/application-questions
+
+func (a *applicationQuestion) Create(question *types.ApplicationQuestion) (*types.ApplicationQuestion, error) {
+	return mongo.ApplicationQuestion.Create(question)
+}
+
+// GET /application-questions
+// GET /admin/application-questions
+
+func (a *applicationQuestion) FindAll() ([]*types.ApplicationQuestion, error) {
+	return mongo.ApplicationQuestion.FindAll()
+}
+
+func (a *applicationQuestion) FindByID(id primitive.ObjectID) (*types.ApplicationQuestion, error) {
+	return mongo.ApplicationQuestion.FindByID(id)
+}
+
+// PATCH /admin/application-questions/{questionID}
+
+func (a *applicationQuestion) Update(question *types.ApplicationQuestion) (*types.ApplicationQuestion, error) {
+	return mongo.ApplicationQuestion.FindOneAndUpdate(question)
+}
+
+// DELETE /admin/application-questions/{questionID}
+
+func (a *applicationQuestion) Delete(id primitive.ObjectID) (*types.ApplicationQuestion, error) {
+	return mongo.ApplicationQuestion.FindOneAndDelete(id)
+}
@@ -0,0 +1,357 @@
+package logic
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/pdf"
+	"github.com/spf13/viper"
+)
+
+type document struct{}
+
+var Document = &document{}
+
+// GET /transfers/{transferID}/receipt.pdf
+
+// Receipt renders the receipt of a completed transfer for one of its parties.
+// The counterparty is nil when it is a system account.
+func (d *document) Receipt(j *types.Journal, entity *types.Entity, counterparty *types.Entity, userID string) ([]byte, *types.Document, error) {
+	code, err := d.generateCode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payer, payee := entity, counterparty
+	if entity.AccountNumber == j.ToAccountNumber {
+		payer, payee = counterparty, entity
+	}
+
+	l := newDocumentLayout("Transfer receipt", code)
+	l.field("Transfer ID", j.TransferID)
+	l.field("Date", formatDocumentTime(j.CompletedAt))
+	l.field("Type", j.Type)
+	l.y += 10
+
+	top := l.y
+	l.partyBlock(documentMargin, "From", payer, j.FromAccountNumber, j.FromEntityName)
+	fromBottom := l.y
+	l.y = top
+	l.partyBlock(pdf.PageWidth/2, "To", payee, j.ToAccountNumber, j.ToEntityName)
+	if fromBottom > l.y {
+		l.y = fromBottom
+	}
+	l.y += 10
+
+	if j.Description != "" {
+		l.doc.Text(documentMargin, l.y, pdf.Bold, 10, "Description")
+		l.y += 14
+		for _, line := range pdf.Wrap(pdf.Regular, 10, j.Description, pdf.PageWidth-2*documentMargin) {
+			l.ensure(14)
+			l.doc.Text(documentMargin, l.y, pdf.Regular, 10, line)
+			l.y += 14
+		}
+		l.y += 10
+	}
+
+	l.ensure(40)
+	l.rule()
+	l.y += 22
+	l.doc.Text(documentMargin, l.y, pdf.Bold, 14, "Amount")
+	l.doc.TextRight(pdf.PageWidth-documentMargin, l.y, pdf.Bold, 14, formatDocumentAmount(j.Amount)+" "+constant.Unit.UK)
+
+	content, err := l.render()
+	if err != nil {
+		return nil, nil, err
+	}
+	record, err := pg.Document.Create(&types.Document{
+		Code:          code,
+		Type:          constant.Document.Receipt,
+		AccountNumber: entity.AccountNumber,
+		EntityName:    entity.Name,
+		UserID:        userID,
+		TransferID:    j.TransferID,
+		Amount:        j.Amount,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, record, nil
+}
+
+// GET /accounts/{accountNumber}/statement.pdf
+
+// Statement renders the postings of the entity's account in [from, to) with the running balance.
+func (d *document) Statement(entity *types.Entity, from time.Time, to time.Time, userID string) ([]byte, *types.Document, error) {
+	openingBalance, err := pg.Posting.BalanceAt(entity.AccountNumber, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := pg.Posting.FindStatementEntries(entity.AccountNumber, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	code, err := d.generateCode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var received, sent float64
+	for _, entry := range entries {
+		if entry.Amount > 0 {
+			received += entry.Amount
+		} else {
+			sent -= entry.Amount
+		}
+	}
+	closingBalance := openingBalance + received - sent
+
+	l := newDocumentLayout("Account statement", code)
+	top := l.y
+	l.partyBlock(documentMargin, "Account holder", entity, entity.AccountNumber, entity.Name)
+	entityBottom := l.y
+	l.y = top
+	l.doc.Text(pdf.PageWidth/2, l.y, pdf.Bold, 10, "Period")
+	l.y += 14
+	// The period is [from, to) so the last day shown is the one before to.
+	l.doc.Text(pdf.PageWidth/2, l.y, pdf.Regular, 10, from.Format("2 January 2006")+" - "+to.Add(-time.Nanosecond).Format("2 January 2006"))
+	l.y += 14
+	l.doc.Text(pdf.PageWidth/2, l.y, pdf.Regular, 10, "Amounts in "+constant.Unit.UK)
+	l.y += 14
+	if entityBottom > l.y {
+		l.y = entityBottom
+	}
+	l.y += 10
+
+	l.summaryLine("Opening balance", openingBalance, false)
+	l.summaryLine("Total received", received, false)
+	l.summaryLine("Total sent", -sent, false)
+	l.summaryLine("Closing balance", closingBalance, true)
+	l.y += 16
+
+	l.statementHeader()
+	balance := openingBalance
+	for _, entry := range entries {
+		balance += entry.Amount
+		counterparty := entry.CounterpartyEntityName()
+		details := pdf.Wrap(pdf.Regular, 9, counterparty+" - "+entry.Description, 240)
+		if entry.Description == "" {
+			details = pdf.Wrap(pdf.Regular, 9, counterparty, 240)
+		}
+
+		if l.ensure(float64(len(details))*12 + 4) {
+			l.statementHeader()
+		}
+		l.doc.Text(documentMargin, l.y, pdf.Regular, 9, entry.CreatedAt.Format("2006-01-02"))
+		l.doc.TextRight(455, l.y, pdf.Regular, 9, formatDocumentAmount(entry.Amount))
+		l.doc.TextRight(pdf.PageWidth-documentMargin, l.y, pdf.Regular, 9, formatDocumentAmount(balance))
+		for _, line := range details {
+			l.doc.Text(125, l.y, pdf.Regular, 9, line)
+			l.y += 12
+		}
+		l.y += 4
+	}
+	if len(entries) == 0 {
+		l.doc.Text(documentMargin, l.y, pdf.Regular, 9, "There were no transfers in this period.")
+		l.y += 12
+	}
+
+	content, err := l.render()
+	if err != nil {
+		return nil, nil, err
+	}
+	record, err := pg.Document.Create(&types.Document{
+		Code:           code,
+		Type:           constant.Document.Statement,
+		AccountNumber:  entity.AccountNumber,
+		EntityName:     entity.Name,
+		UserID:         userID,
+		PeriodFrom:     &from,
+		PeriodTo:       &to,
+		OpeningBalance: openingBalance,
+		ClosingBalance: closingBalance,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, record, nil
+}
+
+// GET /admin/documents/{code}
+
+// Verify finds the document issued with the verification code.
+// The code is accepted in any case and with or without the dashes.
+func (d *document) Verify(code string) (*types.Document, error) {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(normalized) == 16 {
+		normalized = normalized[0:4] + "-" + normalized[4:8] + "-" + normalized[8:12] + "-" + normalized[12:16]
+	}
+	return pg.Document.FindByCode(normalized)
+}
+
+// generateCode returns a random code formatted as XXXX-XXXX-XXXX-XXXX.
+func (d *document) generateCode() (string, error) {
+	b := make([]byte, 10)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	s := base32.StdEncoding.EncodeToString(b)
+	return s[0:4] + "-" + s[4:8] + "-" + s[8:12] + "-" + s[12:16], nil
+}
+
+const (
+	documentMargin = 50.0
+	// Content below this line is moved to the next page to leave room for the footer.
+	documentBottom = pdf.PageHeight - 80
+)
+
+// documentLayout writes the content from top to bottom and starts a new page when it runs out of space.
+// Every page carries the community branding and the verification code of the document.
+type documentLayout struct {
+	doc   *pdf.Document
+	title string
+	code  string
+	y     float64
+}
+
+func newDocumentLayout(title string, code string) *documentLayout {
+	l := &documentLayout{
+		doc:   pdf.New(viper.GetString("branding.community_name") + " - " + title),
+		title: title,
+		code:  code,
+	}
+	l.newPage()
+	return l
+}
+
+func (l *documentLayout) newPage() {
+	l.doc.AddPage()
+	l.y = documentMargin + 16
+
+	l.doc.Text(documentMargin, l.y, pdf.Bold, 16, viper.GetString("branding.community_name"))
+	l.doc.TextRight(pdf.PageWidth-documentMargin, l.y, pdf.Bold, 14, l.title)
+	l.y += 14
+	l.doc.SetGray(0.4)
+	for _, key := range []string{"branding.address", "branding.website", "branding.email"} {
+		if viper.GetString(key) != "" {
+			l.doc.Text(documentMargin, l.y, pdf.Regular, 9, viper.GetString(key))
+			l.y += 11
+		}
+	}
+	l.doc.SetGray(0)
+	l.y += 6
+	l.rule()
+	l.y += 20
+
+	l.doc.SetGray(0.4)
+	footerY := pdf.PageHeight - 50
+	l.doc.Line(documentMargin, footerY-14, pdf.PageWidth-documentMargin, footerY-14, 0.5)
+	if viper.GetString("branding.footer") != "" {
+		l.doc.Text(documentMargin, footerY, pdf.Regular, 8, viper.GetString("branding.footer"))
+	}
+	l.doc.Text(documentMargin, footerY+12, pdf.Regular, 8, "Verification code: "+l.code)
+	l.doc.TextRight(pdf.PageWidth-documentMargin, footerY+12, pdf.Regular, 8, fmt.Sprintf("Page %d", l.doc.NumberOfPages()))
+	l.doc.SetGray(0)
+}
+
+// ensure starts a new page if the height doesn't fit in the current one and reports whether it did.
+func (l *documentLayout) ensure(height float64) bool {
+	if l.y+height <= documentBottom {
+		return false
+	}
+	l.newPage()
+	return true
+}
+
+func (l *documentLayout) rule() {
+	l.doc.Line(documentMargin, l.y, pdf.PageWidth-documentMargin, l.y, 0.5)
+}
+
+func (l *documentLayout) field(label string, value string) {
+	l.doc.Text(documentMargin, l.y, pdf.Bold, 10, label)
+	l.doc.Text(documentMargin+90, l.y, pdf.Regular, 10, value)
+	l.y += 14
+}
+
+// partyBlock writes the details of the entity. System accounts don't have an entity so only the name is shown.
+func (l *documentLayout) partyBlock(x float64, label string, entity *types.Entity, accountNumber string, name string) {
+	l.doc.Text(x, l.y, pdf.Bold, 10, label)
+	l.y += 14
+	if entity != nil {
+		name = entity.Name
+	}
+	l.doc.Text(x, l.y, pdf.Regular, 10, name)
+	l.y += 13
+	lines := []string{"Account " + accountNumber}
+	if entity != nil {
+		lines = append(lines,
+			entity.Address,
+			strings.Join(nonEmpty(entity.City, entity.Region, entity.PostalCode), ", "),
+			entity.Country,
+			entity.Email,
+			entity.Telephone,
+		)
+		if entity.CompanyNumber != "" {
+			lines = append(lines, "Company number "+entity.CompanyNumber)
+		}
+	}
+	for _, line := range nonEmpty(lines...) {
+		l.doc.Text(x, l.y, pdf.Regular, 9, line)
+		l.y += 12
+	}
+}
+
+func (l *documentLayout) summaryLine(label string, amount float64, bold bool) {
+	font := pdf.Regular
+	if bold {
+		font = pdf.Bold
+	}
+	l.doc.Text(documentMargin, l.y, font, 10, label)
+	l.doc.TextRight(pdf.PageWidth-documentMargin, l.y, font, 10, formatDocumentAmount(amount))
+	l.y += 15
+}
+
+func (l *documentLayout) statementHeader() {
+	l.doc.Text(documentMargin, l.y, pdf.Bold, 9, "Date")
+	l.doc.Text(125, l.y, pdf.Bold, 9, "Details")
+	l.doc.TextRight(455, l.y, pdf.Bold, 9, "Amount")
+	l.doc.TextRight(pdf.PageWidth-documentMargin, l.y, pdf.Bold, 9, "Balance")
+	l.y += 6
+	l.rule()
+	l.y += 14
+}
+
+func (l *documentLayout) render() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := l.doc.Write(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func formatDocumentAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+func formatDocumentTime(t time.Time) string {
+	return t.UTC().Format("2 January 2006 15:04 UTC")
+}
+
+func nonEmpty(values ...string) []string {
+	result := []string{}
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -30,6 +30,15 @@ func (transfer *t) Accept(j *types.Journal) {
 	mail.Transfer.Accept(info)
 }
 
+func (transfer *t) AutoAccept(j *types.Journal) {
+	info, err := transfer.getTransferEmailInfo(j)
+	if err != nil {
+		l.Logger.Error("logic.Email.Transfer.AutoAccept failed", zap.Error(err))
+		return
+	}
+	mail.Transfer.AutoAccept(info)
+}
+
 func (transfer *t) Reject(j *types.Journal, reason string) {
 	info, err := transfer.getTransferEmailInfo(j, reason)
 	if err != nil {
@@ -57,6 +66,52 @@ func (transfer *t) CancelBySystem(j *types.Journal, reason string) {
 	mail.Transfer.CancelBySystem(info)
 }
 
+func (transfer *t) Escrow(j *types.Journal) {
+	info, err := transfer.getTransferEmailInfo(j)
+	if err != nil {
+		l.Logger.Error("logic.Email.Transfer.Escrow failed", zap.Error(err))
+		return
+	}
+	mail.Transfer.Escrow(info)
+}
+
+func (transfer *t) EscrowRelease(j *types.Journal) {
+	info, err := transfer.getTransferEmailInfo(j)
+	if err != nil {
+		l.Logger.Error("logic.Email.Transfer.EscrowRelease failed", zap.Error(err))
+		return
+	}
+	mail.Transfer.EscrowRelease(info)
+}
+
+func (transfer *t) EscrowRefund(j *types.Journal, reason string) {
+	info, err := transfer.getTransferEmailInfo(j, reason)
+	if err != nil {
+		l.Logger.Error("logic.Email.Transfer.EscrowRefund failed", zap.Error(err))
+		return
+	}
+	mail.Transfer.EscrowRefund(info)
+}
+
+// Comment notifies the other party of the transfer.
+func (transfer *t) Comment(j *types.Journal, comment *types.TransferComment) {
+	receiverAccountNumber := j.ToAccountNumber
+	if comment.AccountNumber == j.ToAccountNumber {
+		receiverAccountNumber = j.FromAccountNumber
+	}
+	receiver, err := Entity.FindByAccountNumber(receiverAccountNumber)
+	if err != nil {
+		l.Logger.Error("logic.Email.Transfer.Comment failed", zap.Error(err))
+		return
+	}
+	mail.Transfer.Comment(&mail.TransferEmailInfo{
+		InitiatorEntityName: comment.EntityName,
+		ReceiverEmail:       receiver.Email,
+		ReceiverEntityName:  receiver.Name,
+		Amount:              j.Amount,
+	}, comment)
+}
+
 func (transfer *t) getTransferEmailInfo(j *types.Journal, reason ...string) (*mail.TransferEmailInfo, error) {
 	info := &mail.TransferEmailInfo{
 		Amount: j.Amount,
@@ -8,6 +8,7 @@ import (
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/geocode"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -16,6 +17,7 @@ type entity struct{}
 var Entity = &entity{}
 
 func (_ *entity) Create(entity *types.Entity) (*types.Entity, error) {
+	entity.Latitude, entity.Longitude = locate(entity.PostalCode, entity.Latitude, entity.Longitude)
 	account, err := pg.Account.Create()
 	if err != nil {
 		return nil, err
@@ -32,6 +34,26 @@ func (_ *entity) Create(entity *types.Entity) (*types.Entity, error) {
 	return created, nil
 }
 
+// locate geocodes the postal code when the coordinates are not given.
+func locate(postalCode string, latitude, longitude *float64) (*float64, *float64) {
+	if latitude != nil || postalCode == "" {
+		return latitude, longitude
+	}
+	location, ok := geocode.Lookup(postalCode)
+	if !ok {
+		return nil, nil
+	}
+	return &location.Latitude, &location.Longitude
+}
+
+// relocate locates the entity again for the update. The stored coordinates belong to the previous postal code,
+// so they are cleared when the postal code changes and the new one cannot be geocoded.
+func relocate(origin *types.Entity, postalCode string, latitude, longitude *float64) (*float64, *float64, bool) {
+	latitude, longitude = locate(postalCode, latitude, longitude)
+	clear := latitude == nil && postalCode != "" && postalCode != origin.PostalCode
+	return latitude, longitude, clear
+}
+
 // POST /signup
 
 func (_ *entity) AssociateUser(entityID, userID primitive.ObjectID) error {
@@ -86,6 +108,7 @@ func (_ *entity) EmailExists(email string) bool {
 // PATCH /user/entities/{entityID}
 
 func (_ *entity) FindOneAndUpdate(req *types.UpdateUserEntityReq) (*types.Entity, error) {
+	req.Latitude, req.Longitude, req.ClearLocation = relocate(req.OriginEntity, req.PostalCode, req.Latitude, req.Longitude)
 	err := es.Entity.Update(req)
 	if err != nil {
 		return nil, err
@@ -100,6 +123,7 @@ func (_ *entity) FindOneAndUpdate(req *types.UpdateUserEntityReq) (*types.Entity
 // PATCH /admin/entities/{entityID}
 
 func (_ *entity) AdminFindOneAndUpdate(req *types.AdminUpdateEntityReq) (*types.Entity, error) {
+	req.Latitude, req.Longitude, req.ClearLocation = relocate(req.OriginEntity, req.PostalCode, req.Latitude, req.Longitude)
 	err := es.Entity.AdminUpdate(req)
 	if err != nil {
 		return nil, err
@@ -127,7 +151,7 @@ func (_ *entity) AdminFindOneAndDelete(id primitive.ObjectID) (*types.Entity, er
 		return nil, err
 	}
 	if !zeroBalance {
-		return nil, errors.New("Cannot delete an entity with a non-zero balance.")
+		return nil, errors.New("Cannot delete an entity with a non-zero balance. Please close its account to settle the balance first.")
 	}
 
 	err = es.Entity.Delete(id.Hex())
@@ -0,0 +1,249 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	mail "github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/storage"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type entityApplication struct{}
+
+var EntityApplication = &entityApplication{}
+
+// The entity can only change its application while it is not waiting for a review or approved.
+var editableApplicationStatuses = []string{
+	constant.EntityApplication.Draft,
+	constant.EntityApplication.InfoRequested,
+	constant.EntityApplication.Rejected,
+}
+
+func isEditableApplication(application *types.EntityApplication) bool {
+	for _, status := range editableApplicationStatuses {
+		if application.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// GET /user/entities/{entityID}/application
+
+func (e *entityApplication) Find(entity *types.Entity) (*types.EntityApplication, error) {
+	return mongo.EntityApplication.FindOrCreate(entity)
+}
+
+// GET /admin/entities/{entityID}/application
+
+func (e *entityApplication) AdminFind(entityID primitive.ObjectID) (*types.EntityApplication, error) {
+	return mongo.EntityApplication.FindByEntityID(entityID)
+}
+
+// PUT /user/entities/{entityID}/application/answers
+
+func (e *entityApplication) UpdateAnswers(req *types.UpdateApplicationAnswersReq) (*types.EntityApplication, error) {
+	_, err := mongo.EntityApplication.FindOrCreate(req.Entity)
+	if err != nil {
+		return nil, err
+	}
+	return mongo.EntityApplication.SetAnswers(req.Entity.ID, req.Answers, editableApplicationStatuses)
+}
+
+// POST /user/entities/{entityID}/application/documents
+
+func (e *entityApplication) AddDocument(req *types.UploadApplicationDocumentReq) (*types.ApplicationDocument, *types.EntityApplication, error) {
+	application, err := mongo.EntityApplication.FindOrCreate(req.Entity)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isEditableApplication(application) {
+		return nil, nil, errors.New("The application cannot be changed in its current status.")
+	}
+	max := viper.GetInt("application.max_documents")
+	// Checked before storing the file so a full application does not cost an upload.
+	if len(application.Documents) >= max {
+		return nil, nil, fmt.Errorf("An application can have up to %d documents.", max)
+	}
+
+	id := primitive.NewObjectID().Hex()
+	document := &types.ApplicationDocument{
+		ID:          id,
+		Type:        req.Type,
+		FileName:    req.FileName,
+		ContentType: req.ContentType,
+		Size:        len(req.Data),
+		Key:         "applications/" + req.Entity.ID.Hex() + "/" + id + req.Extension(),
+		CreatedAt:   time.Now(),
+	}
+	if document.FileName == "" {
+		document.FileName = req.Type + req.Extension()
+	}
+	_, err = storage.Private().Put(document.Key, document.ContentType, req.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	updated, err := mongo.EntityApplication.AddDocument(req.Entity.ID, document, max, editableApplicationStatuses)
+	if err != nil {
+		e.deleteFile(document)
+		return nil, nil, err
+	}
+	return document, updated, nil
+}
+
+// DELETE /user/entities/{entityID}/application/documents/{documentID}
+
+func (e *entityApplication) DeleteDocument(application *types.EntityApplication, documentID string) (*types.EntityApplication, error) {
+	document, err := e.FindDocument(application, documentID)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := mongo.EntityApplication.RemoveDocument(application.EntityID, documentID, editableApplicationStatuses)
+	if err != nil {
+		return nil, err
+	}
+	e.deleteFile(document)
+	return updated, nil
+}
+
+// GET /user/entities/{entityID}/application/documents/{documentID}
+// GET /admin/entities/{entityID}/application/documents/{documentID}
+
+func (e *entityApplication) FindDocument(application *types.EntityApplication, documentID string) (*types.ApplicationDocument, error) {
+	for _, d := range application.Documents {
+		if d.ID == documentID {
+			return d, nil
+		}
+	}
+	return nil, errors.New("Document not found.")
+}
+
+func (e *entityApplication) ReadDocument(document *types.ApplicationDocument) ([]byte, error) {
+	return storage.Private().Get(document.Key)
+}
+
+// POST /user/entities/{entityID}/application/submission
+
+// Submit sends the application for review once every required question is answered
+// and every required document is uploaded.
+func (e *entityApplication) Submit(req *types.SubmitApplicationReq) (*types.EntityApplication, error) {
+	application, err := mongo.EntityApplication.FindOrCreate(req.Entity)
+	if err != nil {
+		return nil, err
+	}
+	if !isEditableApplication(application) {
+		return nil, errors.New("The application has already been submitted.")
+	}
+	questions, err := ApplicationQuestion.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	errs := e.checkComplete(application, questions)
+	if len(errs) != 0 {
+		return nil, errors.New(strings.Join(errs, " "))
+	}
+
+	var comment *types.ApplicationComment
+	if req.Comment != "" {
+		comment = &types.ApplicationComment{
+			Author:    constant.ApplicationCommentAuthor.Entity,
+			AuthorID:  util.ToObjectID(req.UserID),
+			Body:      req.Comment,
+			CreatedAt: time.Now(),
+		}
+	}
+	return mongo.EntityApplication.Submit(req.Entity.ID, comment, editableApplicationStatuses)
+}
+
+func (e *entityApplication) checkComplete(application *types.EntityApplication, questions []*types.ApplicationQuestion) []string {
+	errs := []string{}
+
+	answered := map[primitive.ObjectID]bool{}
+	for _, a := range application.Answers {
+		answered[a.QuestionID] = true
+	}
+	for _, q := range questions {
+		if q.Required && !answered[q.ID] {
+			errs = append(errs, "Please answer \""+q.Text+"\".")
+		}
+	}
+
+	uploaded := map[string]bool{}
+	for _, d := range application.Documents {
+		uploaded[d.Type] = true
+	}
+	for _, documentType := range viper.GetStringSlice("application.required_documents") {
+		if !uploaded[documentType] {
+			errs = append(errs, "Please upload a "+documentType+" document.")
+		}
+	}
+
+	return errs
+}
+
+// POST /admin/entities/{entityID}/application/review
+
+// CheckReviewable returns an error unless the application of the entity has been submitted.
+func (e *entityApplication) CheckReviewable(entityID primitive.ObjectID) error {
+	application, err := mongo.EntityApplication.FindByEntityID(entityID)
+	if err != nil {
+		return err
+	}
+	if application.Status != constant.EntityApplication.Submitted {
+		return errors.New("Only submitted applications can be reviewed.")
+	}
+	return nil
+}
+
+func (e *entityApplication) Review(req *types.AdminReviewApplicationReq) (*types.EntityApplication, error) {
+	return mongo.EntityApplication.Review(req.Entity.ID, req.ApplicationStatus, &types.ApplicationComment{
+		Author:    constant.ApplicationCommentAuthor.Admin,
+		AuthorID:  util.ToObjectID(req.AdminID),
+		Body:      req.Comment,
+		CreatedAt: time.Now(),
+	})
+}
+
+// NotifyReview emails the decision to every user of the entity.
+// It replaces the status change email when the decision changes the entity status.
+func (e *entityApplication) NotifyReview(entity *types.Entity, req *types.AdminReviewApplicationReq) {
+	users, err := User.FindByIDs(entity.Users)
+	if err != nil {
+		l.Logger.Error("logic.EntityApplication.NotifyReview failed", zap.Error(err))
+		return
+	}
+	for _, user := range users {
+		mail.EntityApplicationReviewed(&mail.EntityApplicationReviewedEmail{
+			EntityName:    entity.Name,
+			Decision:      req.Decision,
+			Status:        entity.Status,
+			Comment:       req.Comment,
+			ReceiverName:  user.FirstName + " " + user.LastName,
+			ReceiverEmail: user.Email,
+		})
+	}
+}
+
+// GET /admin/applications
+
+func (e *entityApplication) Search(req *types.AdminSearchEntityApplicationReq) (*types.SearchEntityApplicationResult, error) {
+	return mongo.EntityApplication.Search(req)
+}
+
+// deleteFile removes the file of the document. Failures are only logged since the document is no longer referenced.
+func (e *entityApplication) deleteFile(document *types.ApplicationDocument) {
+	err := storage.Private().Delete(document.Key)
+	if err != nil {
+		l.Logger.Error("[Error] logic.EntityApplication.deleteFile failed:", zap.Error(err))
+	}
+}
@@ -0,0 +1,374 @@
+package logic
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/spf13/viper"
+)
+
+type entityExport struct{}
+
+var EntityExport = &entityExport{}
+
+var entityExportContentTypes = map[string]string{
+	constant.EntityExportFormat.CSV:   "text/csv",
+	constant.EntityExportFormat.VCard: "text/vcard; charset=utf-8",
+	constant.EntityExportFormat.JSON:  "application/json",
+}
+
+var entityExportColumns = []string{
+	"id", "accountNumber", "handle", "name", "email", "telephone", "incType", "companyNumber", "website", "declaredTurnover",
+	"description", "address", "city", "region", "postalCode", "country", "latitude", "longitude", "status",
+	"offers", "wants", "categories", "rating", "reviewCount",
+}
+
+var adminEntityExportColumns = []string{
+	"createdAt", "memberStartedAt", "showTagsMatchedSinceLastLogin", "receiveDailyMatchNotificationEmail",
+	"balance", "maxPositiveBalance", "maxNegativeBalance", "users",
+}
+
+func (e *entityExport) ContentType(req *types.EntityExportReq) string {
+	return entityExportContentTypes[req.Format]
+}
+
+func (e *entityExport) FileName(req *types.EntityExportReq) string {
+	if req.AdminSearch != nil {
+		return "entities." + req.Format
+	}
+	return "directory." + req.Format
+}
+
+// entityExportWriter writes the exported entities in one of the formats.
+type entityExportWriter interface {
+	begin() error
+	write(entity *types.EntityExportRespond) error
+	end() error
+}
+
+// GET /entities/export
+// GET /admin/entities/export
+
+// Export writes every entity found by the search in the requested format.
+// The search is scrolled page by page and every page is written as soon as it is found, so large directories are not built in memory.
+func (e *entityExport) Export(req *types.EntityExportReq, w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	writer := e.newWriter(req, buf)
+	err := writer.begin()
+	if err != nil {
+		return err
+	}
+	err = e.scroll(req, func(ids []string) error {
+		entities, err := mongo.Entity.FindByStringIDs(ids)
+		if err != nil {
+			return err
+		}
+		for _, entity := range entities {
+			record, err := e.newRecord(req, entity)
+			if err != nil {
+				return err
+			}
+			err = writer.write(record)
+			if err != nil {
+				return err
+			}
+		}
+		return buf.Flush()
+	})
+	if err != nil {
+		return err
+	}
+	err = writer.end()
+	if err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+func (e *entityExport) newWriter(req *types.EntityExportReq, w io.Writer) entityExportWriter {
+	switch req.Format {
+	case constant.EntityExportFormat.VCard:
+		return &entityVCardWriter{w: w}
+	case constant.EntityExportFormat.JSON:
+		return &entityJSONWriter{w: w}
+	default:
+		return &entityCSVWriter{w: csv.NewWriter(w), admin: req.AdminSearch != nil}
+	}
+}
+
+// scroll calls fn with the IDs of every page of the search.
+// Scrolling is not limited by the max result window of the index, so every entity is exported however many are found.
+func (e *entityExport) scroll(req *types.EntityExportReq, fn func(ids []string) error) error {
+	pageSize := viper.GetInt("entity_export.page_size")
+	if req.AdminSearch != nil {
+		return es.Entity.AdminScrollSearch(req.AdminSearch, pageSize, fn)
+	}
+	return es.Entity.ScrollSearch(req.Search, pageSize, fn)
+}
+
+func (e *entityExport) newRecord(req *types.EntityExportReq, entity *types.Entity) (*types.EntityExportRespond, error) {
+	if req.AdminSearch == nil {
+		return types.NewEntityExportRespond(entity, req.QueryingEntityStatus), nil
+	}
+	users, err := User.FindByIDs(entity.Users)
+	if err != nil {
+		return nil, err
+	}
+	account, err := Account.FindByAccountNumber(entity.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	balanceLimit, err := BalanceLimit.FindByAccountNumber(entity.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewAdminEntityExportRespond(entity, users, account, balanceLimit), nil
+}
+
+// CSV
+
+type entityCSVWriter struct {
+	w     *csv.Writer
+	admin bool
+}
+
+func (c *entityCSVWriter) begin() error {
+	header := entityExportColumns
+	if c.admin {
+		header = append(append([]string{}, entityExportColumns...), adminEntityExportColumns...)
+	}
+	return c.w.Write(header)
+}
+
+func (c *entityCSVWriter) write(entity *types.EntityExportRespond) error {
+	record := []string{
+		entity.ID,
+		entity.AccountNumber,
+		entity.Handle,
+		entity.Name,
+		entity.Email,
+		entity.Telephone,
+		entity.IncType,
+		entity.CompanyNumber,
+		entity.Website,
+		formatExportInt(entity.DeclaredTurnover),
+		entity.Description,
+		entity.Address,
+		entity.City,
+		entity.Region,
+		entity.PostalCode,
+		entity.Country,
+		formatExportFloat(entity.Latitude, -1),
+		formatExportFloat(entity.Longitude, -1),
+		entity.Status,
+		strings.Join(entity.Offers, ";"),
+		strings.Join(entity.Wants, ";"),
+		strings.Join(entity.Categories, ";"),
+		formatExportFloat(entity.Rating, 2),
+		strconv.Itoa(entity.ReviewCount),
+	}
+	if c.admin {
+		users := make([]string, 0, len(entity.Users))
+		for _, user := range entity.Users {
+			users = append(users, user.Email)
+		}
+		record = append(record,
+			formatExportTime(entity.CreatedAt),
+			formatExportTime(entity.MemberStartedAt),
+			formatExportBool(entity.ShowTagsMatchedSinceLastLogin),
+			formatExportBool(entity.ReceiveDailyMatchNotificationEmail),
+			formatExportFloat(entity.Balance, 2),
+			formatExportFloat(entity.MaxPositiveBalance, 2),
+			formatExportFloat(entity.MaxNegativeBalance, 2),
+			strings.Join(users, ";"),
+		)
+	}
+	err := c.w.Write(record)
+	if err != nil {
+		return err
+	}
+	// The CSV writer has its own buffer, the page buffer is flushed by the caller.
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *entityCSVWriter) end() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatExportInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
+
+// formatExportFloat uses the smallest number of digits when prec is -1.
+func formatExportFloat(f *float64, prec int) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', prec, 64)
+}
+
+func formatExportBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// JSON
+
+// entityJSONWriter writes an array of entities one element at a time.
+type entityJSONWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *entityJSONWriter) begin() error {
+	_, err := io.WriteString(j.w, "[")
+	return err
+}
+
+func (j *entityJSONWriter) write(entity *types.EntityExportRespond) error {
+	b, err := json.Marshal(entity)
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if j.count == 0 {
+		separator = "\n"
+	}
+	j.count++
+	_, err = io.WriteString(j.w, separator+string(b))
+	return err
+}
+
+func (j *entityJSONWriter) end() error {
+	_, err := io.WriteString(j.w, "\n]\n")
+	return err
+}
+
+// vCard
+
+// entityVCardWriter writes a vCard 3.0 (RFC 2426) business card per entity.
+// Only the contact fields fit in a card, the offers and wants are added to the note.
+type entityVCardWriter struct {
+	w io.Writer
+}
+
+func (v *entityVCardWriter) begin() error {
+	return nil
+}
+
+func (v *entityVCardWriter) write(entity *types.EntityExportRespond) error {
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		"UID:" + escapeVCard(entity.ID),
+		"FN:" + escapeVCard(entity.Name),
+		"N:" + escapeVCard(entity.Name) + ";;;;",
+		"ORG:" + escapeVCard(entity.Name),
+	}
+	if entity.Email != "" {
+		lines = append(lines, "EMAIL;TYPE=INTERNET,WORK:"+escapeVCard(entity.Email))
+	}
+	for _, user := range entity.Users {
+		lines = append(lines, "EMAIL;TYPE=INTERNET:"+escapeVCard(user.Email))
+	}
+	if entity.Telephone != "" {
+		lines = append(lines, "TEL;TYPE=WORK,VOICE:"+escapeVCard(entity.Telephone))
+	}
+	if entity.Website != "" {
+		lines = append(lines, "URL:"+escapeVCard(entity.Website))
+	}
+	if entity.Address != "" || entity.City != "" || entity.Region != "" || entity.PostalCode != "" || entity.Country != "" {
+		lines = append(lines, "ADR;TYPE=WORK:;;"+strings.Join([]string{
+			escapeVCard(entity.Address),
+			escapeVCard(entity.City),
+			escapeVCard(entity.Region),
+			escapeVCard(entity.PostalCode),
+			escapeVCard(entity.Country),
+		}, ";"))
+	}
+	if entity.Latitude != nil && entity.Longitude != nil {
+		lines = append(lines, "GEO:"+formatExportFloat(entity.Latitude, -1)+";"+formatExportFloat(entity.Longitude, -1))
+	}
+	if len(entity.Categories) != 0 {
+		categories := make([]string, 0, len(entity.Categories))
+		for _, category := range entity.Categories {
+			categories = append(categories, escapeVCard(category))
+		}
+		lines = append(lines, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+	note := []string{}
+	if entity.Description != "" {
+		note = append(note, entity.Description)
+	}
+	if len(entity.Offers) != 0 {
+		note = append(note, "Offers: "+strings.Join(entity.Offers, ", "))
+	}
+	if len(entity.Wants) != 0 {
+		note = append(note, "Wants: "+strings.Join(entity.Wants, ", "))
+	}
+	if len(note) != 0 {
+		lines = append(lines, "NOTE:"+escapeVCard(strings.Join(note, "\n")))
+	}
+	lines = append(lines, "END:VCARD")
+
+	for _, line := range lines {
+		_, err := io.WriteString(v.w, foldVCardLine(line))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *entityVCardWriter) end() error {
+	return nil
+}
+
+var vCardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeVCard(s string) string {
+	return vCardEscaper.Replace(s)
+}
+
+// foldVCardLine splits the line into lines of at most 75 octets, the next lines start with a space.
+func foldVCardLine(line string) string {
+	b := &strings.Builder{}
+	maxLen := 75
+	for len(line) > maxLen {
+		i := maxLen
+		// Don't split a multi-byte character.
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+		b.WriteString(line[:i] + "\r\n ")
+		line = line[i:]
+		// The space counts towards the length of the next lines.
+		maxLen = 74
+	}
+	b.WriteString(line + "\r\n")
+	return b.String()
+}
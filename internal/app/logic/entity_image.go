@@ -0,0 +1,137 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/imaging"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/storage"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type entityImage struct{}
+
+var EntityImage = &entityImage{}
+
+// PUT /user/entities/{entityID}/logo
+
+func (e *entityImage) SetLogo(entity *types.Entity, data []byte) (*types.EntityImage, error) {
+	image, err := e.store(entity, data)
+	if err != nil {
+		return nil, err
+	}
+	err = mongo.Entity.SetLogo(entity.ID, image)
+	if err != nil {
+		e.delete(image)
+		return nil, err
+	}
+	e.delete(entity.Logo)
+	return image, nil
+}
+
+// DELETE /user/entities/{entityID}/logo
+
+func (e *entityImage) DeleteLogo(entity *types.Entity) error {
+	if entity.Logo == nil {
+		return errors.New("The entity does not have a logo.")
+	}
+	err := mongo.Entity.SetLogo(entity.ID, nil)
+	if err != nil {
+		return err
+	}
+	e.delete(entity.Logo)
+	return nil
+}
+
+// POST /user/entities/{entityID}/gallery
+
+func (e *entityImage) AddGalleryImage(entity *types.Entity, data []byte) (*types.EntityImage, error) {
+	max := viper.GetInt("image.max_gallery_images")
+	// Checked before storing the files so a full gallery does not cost a resize.
+	if len(entity.Gallery) >= max {
+		return nil, fmt.Errorf("An entity can have up to %d gallery images.", max)
+	}
+	image, err := e.store(entity, data)
+	if err != nil {
+		return nil, err
+	}
+	err = mongo.Entity.AddGalleryImage(entity.ID, image, max)
+	if err != nil {
+		e.delete(image)
+		return nil, err
+	}
+	return image, nil
+}
+
+// DELETE /user/entities/{entityID}/gallery/{imageID}
+
+func (e *entityImage) DeleteGalleryImage(entity *types.Entity, imageID string) error {
+	var image *types.EntityImage
+	for _, i := range entity.Gallery {
+		if i.ID == imageID {
+			image = i
+		}
+	}
+	if image == nil {
+		return errors.New("Image not found.")
+	}
+	err := mongo.Entity.RemoveGalleryImage(entity.ID, imageID)
+	if err != nil {
+		return err
+	}
+	e.delete(image)
+	return nil
+}
+
+// store resizes the uploaded image and stores it together with its thumbnail.
+func (e *entityImage) store(entity *types.Entity, data []byte) (*types.EntityImage, error) {
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	full, err := imaging.Encode(imaging.Fit(img, viper.GetInt("image.max_size")))
+	if err != nil {
+		return nil, err
+	}
+	thumbnail, err := imaging.Encode(imaging.Fit(img, viper.GetInt("image.thumbnail_size")))
+	if err != nil {
+		return nil, err
+	}
+
+	id := primitive.NewObjectID().Hex()
+	result := &types.EntityImage{
+		ID:           id,
+		Key:          "entities/" + entity.ID.Hex() + "/" + id + full.Extension,
+		ThumbnailKey: "entities/" + entity.ID.Hex() + "/" + id + "_thumb" + thumbnail.Extension,
+		CreatedAt:    time.Now(),
+	}
+	result.URL, err = storage.Default().Put(result.Key, full.ContentType, full.Data)
+	if err != nil {
+		return nil, err
+	}
+	result.ThumbnailURL, err = storage.Default().Put(result.ThumbnailKey, thumbnail.ContentType, thumbnail.Data)
+	if err != nil {
+		e.delete(result)
+		return nil, err
+	}
+	return result, nil
+}
+
+// delete removes the files of the image. Failures are only logged since the image is no longer referenced.
+func (e *entityImage) delete(image *types.EntityImage) {
+	if image == nil {
+		return
+	}
+	for _, key := range []string{image.Key, image.ThumbnailKey} {
+		err := storage.Default().Delete(key)
+		if err != nil {
+			l.Logger.Error("[Error] logic.EntityImage.delete failed:", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,436 @@
+package logic
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	mail "github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/segmentio/ksuid"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type entityImport struct{}
+
+var EntityImport = &entityImport{}
+
+var entityImportColumns = []string{
+	"entityName", "entityEmail", "incType", "companyNumber", "entityPhone", "website", "declaredTurnover", "description",
+	"address", "city", "region", "postalCode", "country", "offers", "wants", "categories", "entityStatus",
+	"maxPositiveBalance", "maxNegativeBalance", "firstName", "lastName", "userEmail", "userPhone",
+}
+
+// The columns every file must have, the others are optional.
+var entityImportRequiredColumns = []string{"entityName", "userEmail"}
+
+// POST /admin/entities/imports
+
+// Parse reads the rows of a CSV file with a header of entityImportColumns. The column names are case-insensitive.
+func (e *entityImport) Parse(r io.Reader) ([]*types.EntityImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("The file is empty.")
+	}
+	if err != nil {
+		return nil, errors.New("The file is not a valid CSV file: " + err.Error())
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range entityImportRequiredColumns {
+		if _, ok := columns[strings.ToLower(name)]; !ok {
+			return nil, errors.New("The file is missing the " + name + " column.")
+		}
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[strings.ToLower(name)]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rows := []*types.EntityImportRow{}
+	// The header is the first line.
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("The file is not a valid CSV file: " + err.Error())
+		}
+		line++
+		rows = append(rows, &types.EntityImportRow{
+			Line:             line,
+			EntityName:       field(record, "entityName"),
+			EntityEmail:      field(record, "entityEmail"),
+			IncType:          field(record, "incType"),
+			CompanyNumber:    field(record, "companyNumber"),
+			EntityPhone:      field(record, "entityPhone"),
+			Website:          field(record, "website"),
+			DeclaredTurnover: field(record, "declaredTurnover"),
+			Description:      field(record, "description"),
+			Address:          field(record, "address"),
+			City:             field(record, "city"),
+			Region:           field(record, "region"),
+			PostalCode:       field(record, "postalCode"),
+			Country:          field(record, "country"),
+			Offers:           field(record, "offers"),
+			Wants:            field(record, "wants"),
+			Categories:       field(record, "categories"),
+			EntityStatus:     field(record, "entityStatus"),
+			MaxPosBal:        field(record, "maxPositiveBalance"),
+			MaxNegBal:        field(record, "maxNegativeBalance"),
+			FirstName:        field(record, "firstName"),
+			LastName:         field(record, "lastName"),
+			UserEmail:        field(record, "userEmail"),
+			UserPhone:        field(record, "userPhone"),
+		})
+		if len(rows) > viper.GetInt("entity_import.max_rows") {
+			return nil, errors.New("The file cannot have more than " + viper.GetString("entity_import.max_rows") + " rows.")
+		}
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("The file does not have any rows.")
+	}
+	return rows, nil
+}
+
+// Create validates the rows and stores the dry-run report. Nothing is created until the import is executed.
+func (e *entityImport) Create(rows []*types.EntityImportRow, fileName string, requestedBy string) (*types.EntityImport, error) {
+	e.validate(rows)
+	return pg.EntityImport.Create(&types.EntityImport{
+		ImportID:    ksuid.New().String(),
+		FileName:    fileName,
+		RequestedBy: requestedBy,
+		Status:      constant.EntityImport.Validated,
+		Rows:        rows,
+	})
+}
+
+// validate checks every row. It returns the requests of the valid rows, the invalid rows are nil.
+func (e *entityImport) validate(rows []*types.EntityImportRow) []*types.EntityImportReq {
+	reqs := make([]*types.EntityImportReq, len(rows))
+	// The first row of every entity, by entity name.
+	firstRows := map[string]int{}
+	// The line of every user email address.
+	emails := map[string]int{}
+	for i, row := range rows {
+		first, ok := firstRows[e.entityKey(row)]
+		if !ok {
+			first = i
+			firstRows[e.entityKey(row)] = i
+		}
+		req, err := e.validateRow(row, first == i, emails)
+		if err == nil && first != i && reqs[first] == nil {
+			err = errors.New("The entity on line " + strconv.Itoa(rows[first].Line) + " is invalid.")
+		}
+		if err != nil {
+			row.Status = constant.EntityImportRow.Invalid
+			row.Error = err.Error()
+			continue
+		}
+		row.Status = constant.EntityImportRow.Valid
+		row.Error = ""
+		reqs[i] = req
+	}
+	return reqs
+}
+
+func (e *entityImport) validateRow(row *types.EntityImportRow, withEntity bool, emails map[string]int) (*types.EntityImportReq, error) {
+	req, errs := types.NewEntityImportReq(row, withEntity)
+	if len(errs) > 0 {
+		messages := make([]string, 0, len(errs))
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		return nil, errors.New(strings.Join(messages, " "))
+	}
+	if line, ok := emails[req.User.Email]; ok {
+		return nil, errors.New("User email address is already used on line " + strconv.Itoa(line) + ".")
+	}
+	emails[req.User.Email] = row.Line
+	if User.EmailExists(req.User.Email) {
+		return nil, errors.New("User email address is already registered.")
+	}
+	return req, nil
+}
+
+// entityKey groups the rows of the same entity, the entity names are compared case-insensitively.
+func (e *entityImport) entityKey(row *types.EntityImportRow) string {
+	return strings.ToLower(row.EntityName)
+}
+
+// GET /admin/entities/imports/{importID}
+
+func (e *entityImport) FindByImportID(importID string) (*types.EntityImport, error) {
+	return pg.EntityImport.FindByImportID(importID)
+}
+
+// POST /admin/entities/imports/{importID}/execution
+
+// Execute validates the rows again, since users may have signed up since the upload, and creates the entities and their users.
+// Every row is created on its own, invalid rows and the rows of an entity that could not be created are skipped.
+func (e *entityImport) Execute(record *types.EntityImport, sendInvitations bool, executedBy string) (*types.EntityImport, error) {
+	// Claiming the import first makes sure it can only be executed once.
+	err := pg.EntityImport.Claim(record.ImportID)
+	if err != nil {
+		return nil, err
+	}
+
+	reqs := e.validate(record.Rows)
+	entities := map[string]*types.Entity{}
+	for i, row := range record.Rows {
+		if reqs[i] == nil {
+			continue
+		}
+		status := constant.EntityImportRow.UserAdded
+		entity, ok := entities[e.entityKey(row)]
+		if reqs[i].Entity != nil {
+			created, err := e.createEntity(reqs[i], record.FileName, executedBy)
+			if created != nil {
+				row.EntityID = created.ID.Hex()
+			}
+			if err != nil {
+				row.Status = constant.EntityImportRow.Failed
+				row.Error = err.Error()
+				continue
+			}
+			entity = created
+			entities[e.entityKey(row)] = created
+			status = constant.EntityImportRow.Created
+		} else if !ok {
+			row.Status = constant.EntityImportRow.Failed
+			row.Error = "The entity of this row could not be created."
+			continue
+		}
+		row.EntityID = entity.ID.Hex()
+
+		user, err := e.createUser(reqs[i].User, entity)
+		if user != nil {
+			row.UserID = user.ID.Hex()
+		}
+		if err != nil {
+			row.Status = constant.EntityImportRow.Failed
+			row.Error = err.Error()
+			continue
+		}
+		row.Status = status
+		if sendInvitations {
+			err := e.invite(user, entity)
+			if err != nil {
+				l.Logger.Error("logic.EntityImport.Execute failed", zap.Error(err))
+				row.Error = "The invitation could not be sent: " + err.Error()
+			} else {
+				row.Invited = true
+			}
+		}
+	}
+
+	now := time.Now()
+	record.Status = constant.EntityImport.Executed
+	record.SendInvitations = sendInvitations
+	record.ExecutedAt = &now
+	err = pg.EntityImport.Update(record)
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// createEntity creates the entity, which is pending after the signup, and moves it to the status of the file.
+// Imported entities are existing members of the community, their status is set without going through the status transitions.
+// The entity is returned along with the error when it has been created but could not be fully updated.
+func (e *entityImport) createEntity(req *types.EntityImportReq, fileName string, adminID string) (*types.Entity, error) {
+	status := req.Entity.Status
+	created, err := Entity.Create(req.Entity)
+	if err != nil {
+		return nil, err
+	}
+	if len(created.Categories) != 0 {
+		err := Category.Create(created.Categories...)
+		if err != nil {
+			return created, err
+		}
+	}
+	if status == constant.Entity.Pending && req.MaxPosBal == nil && req.MaxNegBal == nil {
+		return created, nil
+	}
+
+	balanceLimit, err := BalanceLimit.FindByAccountNumber(created.AccountNumber)
+	if err != nil {
+		return created, err
+	}
+	update := &types.AdminUpdateEntityReq{
+		OriginEntity:       created,
+		OriginBalanceLimit: balanceLimit,
+		MaxPosBal:          req.MaxPosBal,
+		MaxNegBal:          req.MaxNegBal,
+	}
+	if status != constant.Entity.Pending {
+		update.Status = status
+		update.StatusReason = "Imported from " + fileName + "."
+	}
+	updated, err := Entity.AdminFindOneAndUpdate(update)
+	if err != nil {
+		return created, err
+	}
+	if update.Status == "" {
+		return updated, nil
+	}
+
+	_, err = EntityStatus.Record(updated, created.Status, update.Status, update.StatusReason, adminID)
+	if err != nil {
+		return updated, err
+	}
+	if util.IsAcceptedStatus(update.Status) {
+		err := e.updateTags(updated)
+		if err != nil {
+			return updated, err
+		}
+	}
+	if util.IsTradingAccepted(update.Status) {
+		err := Entity.SetMemberStartedAt(updated.ID)
+		if err != nil {
+			return updated, err
+		}
+	}
+	return updated, nil
+}
+
+// updateTags adds the offers and wants of an accepted entity to the tags, as accepting an entity does.
+func (e *entityImport) updateTags(entity *types.Entity) error {
+	err := Entity.UpdateAllTagsCreatedAt(entity.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, name := range types.TagFieldToNames(entity.Offers) {
+		err := Tag.UpdateOffer(name)
+		if err != nil {
+			return err
+		}
+	}
+	for _, name := range types.TagFieldToNames(entity.Wants) {
+		err := Tag.UpdateWant(name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createUser creates the user with a random password and adds it to the entity.
+// The user sets a password with the invitation or the password reset.
+// The user is returned along with the error when it has been created but could not be added to the entity.
+func (e *entityImport) createUser(user *types.User, entity *types.Entity) (*types.User, error) {
+	password, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	user.Password = password.String()
+	created, err := User.Create(user)
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("User email address is already registered.")
+	}
+	err = Entity.AssociateUser(entity.ID, created.ID)
+	if err != nil {
+		return created, err
+	}
+	err = User.AssociateEntity(created.ID, entity.ID)
+	if err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+// invite emails the user a token to set a password. It is valid for longer than a password reset.
+func (e *entityImport) invite(user *types.User, entity *types.Entity) error {
+	token, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	err = Lostpassword.Create(&types.LostPassword{
+		Email:     user.Email,
+		Token:     token.String(),
+		ExpiresAt: time.Now().Add(time.Duration(viper.GetInt64("entity_import.invitation_timeout")) * time.Second),
+	})
+	if err != nil {
+		return err
+	}
+	go mail.UserImportInvitation(&mail.UserImportInvitationEmail{
+		EntityName:    entity.Name,
+		Receiver:      user.FirstName + " " + user.LastName,
+		ReceiverEmail: user.Email,
+		Token:         token.String(),
+	})
+	return nil
+}
+
+// GET /admin/entities/imports/{importID}/results.csv
+
+// WriteResults writes the rows of the import with their status, the created entity and user IDs and the errors.
+func (e *entityImport) WriteResults(record *types.EntityImport, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(append([]string{"line"}, append(entityImportColumns, "status", "entityID", "userID", "invited", "error")...))
+	if err != nil {
+		return err
+	}
+	for _, row := range record.Rows {
+		err := writer.Write([]string{
+			strconv.Itoa(row.Line),
+			row.EntityName,
+			row.EntityEmail,
+			row.IncType,
+			row.CompanyNumber,
+			row.EntityPhone,
+			row.Website,
+			row.DeclaredTurnover,
+			row.Description,
+			row.Address,
+			row.City,
+			row.Region,
+			row.PostalCode,
+			row.Country,
+			row.Offers,
+			row.Wants,
+			row.Categories,
+			row.EntityStatus,
+			row.MaxPosBal,
+			row.MaxNegBal,
+			row.FirstName,
+			row.LastName,
+			row.UserEmail,
+			row.UserPhone,
+			row.Status,
+			row.EntityID,
+			row.UserID,
+			strconv.FormatBool(row.Invited),
+			row.Error,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
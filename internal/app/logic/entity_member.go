@@ -0,0 +1,151 @@
+package logic
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type entityMember struct{}
+
+var EntityMember = &entityMember{}
+
+var (
+	errOnlyOwnersManageOwners = errors.New("Only owners can invite or manage owners.")
+	errLastOwner              = errors.New("An entity must have at least one owner.")
+	errNotMember              = errors.New("The user is not a member of this entity.")
+)
+
+// GET /user/entities/{entityID}/members
+
+func (m *entityMember) FindMembers(entity *types.Entity) ([]*types.User, error) {
+	return mongo.User.FindByIDs(entity.Users)
+}
+
+// POST /user/entities/{entityID}/invitations
+
+func (m *entityMember) Invite(req *types.InviteEntityMemberReq) (*types.EntityInvitation, error) {
+	if req.Role == constant.EntityRole.Owner && !req.Entity.HasRole(req.InvitedBy.ID, constant.EntityRole.Owner) {
+		return nil, errOnlyOwnersManageOwners
+	}
+	user, err := mongo.User.FindByEmail(req.Email)
+	if err == nil && util.ContainID(req.Entity.Users, user.ID.Hex()) {
+		return nil, errors.New("The user is already a member of this entity.")
+	}
+
+	token, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	return mongo.EntityInvitation.Create(&types.EntityInvitation{
+		EntityID:  req.Entity.ID,
+		Email:     req.Email,
+		Role:      req.Role,
+		Token:     token.String(),
+		InvitedBy: req.InvitedBy.ID,
+		ExpiresAt: time.Now().Add(time.Duration(viper.GetInt("entity_invitation_timeout")) * time.Second),
+	})
+}
+
+// GET /user/entities/{entityID}/invitations
+
+func (m *entityMember) FindInvitations(entity *types.Entity) ([]*types.EntityInvitation, error) {
+	return mongo.EntityInvitation.FindPending(entity.ID)
+}
+
+// DELETE /user/entities/{entityID}/invitations/{invitationID}
+
+func (m *entityMember) RevokeInvitation(entity *types.Entity, invitationID string) error {
+	id, err := primitive.ObjectIDFromHex(invitationID)
+	if err != nil {
+		return errors.New("Invitation not found.")
+	}
+	return mongo.EntityInvitation.Delete(entity.ID, id)
+}
+
+// POST /invitations/{token}
+
+// Accept adds the user to the entity with the role of the invitation.
+// The invitation can only be accepted by the user with the email address it was sent to.
+func (m *entityMember) Accept(token string, user *types.User) (*types.Entity, *types.EntityInvitation, error) {
+	invitation, err := mongo.EntityInvitation.FindByToken(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !invitation.IsValid() {
+		return nil, nil, errors.New("The invitation is invalid or has expired.")
+	}
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		return nil, nil, errors.New("This invitation was sent to another email address.")
+	}
+	entity, err := mongo.Entity.FindByID(invitation.EntityID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if util.ContainID(entity.Users, user.ID.Hex()) {
+		return nil, nil, errors.New("You are already a member of this entity.")
+	}
+
+	// Marking the invitation first makes sure it can only be used once.
+	err = mongo.EntityInvitation.SetAccepted(invitation.ID, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = mongo.Entity.AddMember(entity.ID, user.ID, invitation.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = mongo.User.AssociateEntity([]primitive.ObjectID{user.ID}, entity.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entity, invitation, nil
+}
+
+// PATCH /user/entities/{entityID}/members/{userID}
+
+func (m *entityMember) UpdateRole(req *types.UpdateEntityMemberReq) error {
+	current := req.Entity.Role(req.Member.ID)
+	if current == "" {
+		return errNotMember
+	}
+	if (req.Role == constant.EntityRole.Owner || current == constant.EntityRole.Owner) && !req.Entity.HasRole(req.UpdatedBy, constant.EntityRole.Owner) {
+		return errOnlyOwnersManageOwners
+	}
+	if current == constant.EntityRole.Owner && req.Role != constant.EntityRole.Owner && req.Entity.NumberOfOwners() == 1 {
+		return errLastOwner
+	}
+	return mongo.Entity.SetRole(req.Entity.ID, req.Member.ID, req.Role)
+}
+
+// DELETE /user/entities/{entityID}/members/{userID}
+
+// Remove takes the member out of the entity. Members can always leave the entity themselves
+// unless they are its last owner.
+func (m *entityMember) Remove(entity *types.Entity, member *types.User, removedBy primitive.ObjectID) error {
+	current := entity.Role(member.ID)
+	if current == "" {
+		return errNotMember
+	}
+	if current == constant.EntityRole.Owner {
+		if member.ID != removedBy && !entity.HasRole(removedBy, constant.EntityRole.Owner) {
+			return errOnlyOwnersManageOwners
+		}
+		if entity.NumberOfOwners() == 1 {
+			return errLastOwner
+		}
+	}
+	err := mongo.Entity.RemoveUser(entity.ID, member.ID)
+	if err != nil {
+		return err
+	}
+	return mongo.User.RemoveEntity(member.ID, entity.ID)
+}
@@ -0,0 +1,58 @@
+package logic
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	mail "github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+type entityStatus struct{}
+
+var EntityStatus = &entityStatus{}
+
+// PATCH /admin/entities/{entityID}
+
+// Record stores the status change in the history of the entity.
+func (e *entityStatus) Record(entity *types.Entity, from string, to string, reason string, adminID string) (*types.EntityStatusChange, error) {
+	return mongo.EntityStatusChange.Create(&types.EntityStatusChange{
+		EntityID:   entity.ID,
+		EntityName: entity.Name,
+		From:       from,
+		To:         to,
+		Reason:     reason,
+		ChangedBy:  util.ToObjectID(adminID),
+	})
+}
+
+// Notify emails the status change to every user of the entity.
+func (e *entityStatus) Notify(entity *types.Entity, change *types.EntityStatusChange) {
+	users, err := User.FindByIDs(entity.Users)
+	if err != nil {
+		l.Logger.Error("logic.EntityStatus.Notify failed", zap.Error(err))
+		return
+	}
+	for _, user := range users {
+		mail.EntityStatusChange(&mail.EntityStatusChangeEmail{
+			EntityName:    entity.Name,
+			Status:        change.To,
+			Reason:        change.Reason,
+			ReceiverName:  user.FirstName + " " + user.LastName,
+			ReceiverEmail: user.Email,
+		})
+	}
+}
+
+// GET /admin/entities/{entityID}
+
+func (e *entityStatus) FindByEntityID(entity *types.Entity) ([]*types.EntityStatusChange, error) {
+	return mongo.EntityStatusChange.FindByEntityID(entity.ID)
+}
+
+// GET /admin/entities/status-changes
+
+func (e *entityStatus) Search(req *types.AdminSearchEntityStatusChangeReq) (*types.SearchEntityStatusChangeResult, error) {
+	return mongo.EntityStatusChange.Search(req)
+}
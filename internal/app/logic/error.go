@@ -5,5 +5,6 @@ import (
 )
 
 var (
-	ErrLoginLocked = errors.New("Your account has been temporarily locked for 15 minutes. Please try again later.")
+	ErrLoginLocked            = errors.New("Your account has been temporarily locked for 15 minutes. Please try again later.")
+	ErrTransferImportExecuted = errors.New("This import has already been executed.")
 )
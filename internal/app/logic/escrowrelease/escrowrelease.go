@@ -0,0 +1,18 @@
+package escrowrelease
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+// Run will release the escrowed transfers whose release time has passed to their payees.
+func Run() {
+	released, err := logic.Transfer.ReleaseDueEscrows()
+	for _, j := range released {
+		logic.Email.Transfer.EscrowRelease(j)
+	}
+	if err != nil {
+		l.Logger.Error("releasing escrowed transfers failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,334 @@
+package logic
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/spf13/viper"
+)
+
+type feed struct{}
+
+var Feed = &feed{}
+
+var feedContentTypes = map[string]string{
+	constant.FeedFormat.Atom: "application/atom+xml; charset=utf-8",
+	constant.FeedFormat.RSS:  "application/rss+xml; charset=utf-8",
+	constant.FeedFormat.JSON: "application/feed+json; charset=utf-8",
+}
+
+func (f *feed) ContentType(req *types.FeedReq) string {
+	return feedContentTypes[req.Format]
+}
+
+// GET /feeds/members
+
+func (f *feed) Members(req *types.FeedReq, feedURL string) (*types.Feed, error) {
+	entities, err := mongo.Entity.FindNewMembers(req, viper.GetInt("feed.max_items"))
+	if err != nil {
+		return nil, err
+	}
+	result := f.newFeed("New members", "The latest entities accepted as trading members.", feedURL)
+	for _, entity := range entities {
+		result.Items = append(result.Items, types.NewMemberFeedItem(entity, entityURL(entity.ID.Hex())))
+	}
+	if len(result.Items) != 0 {
+		result.Updated = result.Items[0].Published
+	}
+	return result, nil
+}
+
+// GET /feeds/tags
+
+// Tags lists the offers and wants added during the feed's period.
+// The tags added recently are found first, by their offerAddedAt and wantAddedAt, then the entities which added them.
+func (f *feed) Tags(req *types.FeedReq, feedURL string) (*types.Feed, error) {
+	limit := viper.GetInt("feed.max_items")
+	since := time.Now().Add(-time.Duration(viper.GetInt("feed.tag_period")) * time.Second)
+
+	title := "New offers and wants"
+	if req.TagType == constant.FeedTagType.Offer {
+		title = "New offers"
+	} else if req.TagType == constant.FeedTagType.Want {
+		title = "New wants"
+	}
+	result := f.newFeed(title, "The offers and wants added to the directory recently.", feedURL)
+
+	added := []*types.EntityTagAddition{}
+	for _, tagType := range []string{constant.FeedTagType.Offer, constant.FeedTagType.Want} {
+		if req.TagType != "" && req.TagType != tagType {
+			continue
+		}
+		found, err := f.findAddedTags(tagType, since, req, limit)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, found...)
+	}
+	sort.SliceStable(added, func(i, j int) bool {
+		return added[i].Tag.CreatedAt.After(added[j].Tag.CreatedAt)
+	})
+	if len(added) > limit {
+		added = added[:limit]
+	}
+
+	for _, a := range added {
+		result.Items = append(result.Items, types.NewTagFeedItem(a, entityURL(a.EntityID.Hex())))
+	}
+	if len(result.Items) != 0 {
+		result.Updated = result.Items[0].Published
+	}
+	return result, nil
+}
+
+func (f *feed) findAddedTags(tagType string, since time.Time, req *types.FeedReq, limit int) ([]*types.EntityTagAddition, error) {
+	addedAtField, field := "offerAddedAt", "offers"
+	if tagType == constant.FeedTagType.Want {
+		addedAtField, field = "wantAddedAt", "wants"
+	}
+	names, err := mongo.Tag.FindNamesAddedSince(addedAtField, since, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	added, err := mongo.Entity.FindAddedTags(field, names, since, req.Category, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range added {
+		a.Type = tagType
+	}
+	return added, nil
+}
+
+func (f *feed) newFeed(title string, description string, feedURL string) *types.Feed {
+	if name := viper.GetString("branding.community_name"); name != "" {
+		title = name + ": " + title
+	}
+	return &types.Feed{
+		Title:       title,
+		Description: description,
+		Link:        viper.GetString("url"),
+		FeedURL:     feedURL,
+		Items:       []*types.FeedItem{},
+	}
+}
+
+func entityURL(entityID string) string {
+	return viper.GetString("url") + "/entities/" + entityID
+}
+
+// Write writes the feed in the requested format.
+func (f *feed) Write(req *types.FeedReq, result *types.Feed, w io.Writer) error {
+	switch req.Format {
+	case constant.FeedFormat.RSS:
+		return writeRSS(result, w)
+	case constant.FeedFormat.JSON:
+		return writeJSONFeed(result, w)
+	default:
+		return writeAtom(result, w)
+	}
+}
+
+// Atom
+
+type atomFeed struct {
+	XMLName  xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string       `xml:"id"`
+	Title    string       `xml:"title"`
+	Subtitle string       `xml:"subtitle,omitempty"`
+	Updated  string       `xml:"updated"`
+	Author   atomAuthor   `xml:"author"`
+	Links    []atomLink   `xml:"link"`
+	Entries  []*atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Link       atomLink       `xml:"link"`
+	Content    *atomContent   `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func writeAtom(result *types.Feed, w io.Writer) error {
+	// Atom requires the updated time, an empty feed has never been updated.
+	updated := result.Updated
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+	author := viper.GetString("branding.community_name")
+	if author == "" {
+		author = result.Title
+	}
+	feed := &atomFeed{
+		ID:       result.FeedURL,
+		Title:    result.Title,
+		Subtitle: result.Description,
+		Updated:  updated.UTC().Format(time.RFC3339),
+		Author:   atomAuthor{Name: author},
+		Links: []atomLink{
+			{Href: result.FeedURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: result.Link, Rel: "alternate"},
+		},
+	}
+	for _, item := range result.Items {
+		entry := &atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Updated:   item.Published.UTC().Format(time.RFC3339),
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Link:      atomLink{Href: item.URL, Rel: "alternate"},
+		}
+		if item.Content != "" {
+			entry.Content = &atomContent{Type: "text", Body: item.Content}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return writeXML(feed, w)
+}
+
+// RSS
+
+type rssFeed struct {
+	XMLName xml.Name    `xml:"rss"`
+	Version string      `xml:"version,attr"`
+	AtomNS  string      `xml:"xmlns:atom,attr"`
+	Channel *rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	AtomLink      atomLink   `xml:"atom:link"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func writeRSS(result *types.Feed, w io.Writer) error {
+	channel := &rssChannel{
+		Title:       result.Title,
+		Link:        result.Link,
+		Description: result.Description,
+		AtomLink:    atomLink{Href: result.FeedURL, Rel: "self", Type: "application/rss+xml"},
+	}
+	if !result.Updated.IsZero() {
+		channel.LastBuildDate = result.Updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, item := range result.Items {
+		channel.Items = append(channel.Items, &rssItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			Description: item.Content,
+			GUID:        rssGUID{IsPermaLink: false, Value: item.ID},
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+			Categories:  item.Categories,
+		})
+	}
+	return writeXML(&rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: channel,
+	}, w)
+}
+
+func writeXML(v interface{}, w io.Writer) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(v)
+}
+
+// JSON Feed
+
+type jsonFeed struct {
+	Version     string          `json:"version"`
+	Title       string          `json:"title"`
+	HomePageURL string          `json:"home_page_url"`
+	FeedURL     string          `json:"feed_url"`
+	Description string          `json:"description,omitempty"`
+	Items       []*jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentText   string   `json:"content_text"`
+	DatePublished string   `json:"date_published"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+func writeJSONFeed(result *types.Feed, w io.Writer) error {
+	feed := &jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       result.Title,
+		HomePageURL: result.Link,
+		FeedURL:     result.FeedURL,
+		Description: result.Description,
+		Items:       []*jsonFeedItem{},
+	}
+	for _, item := range result.Items {
+		feed.Items = append(feed.Items, &jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.URL,
+			Title:         item.Title,
+			ContentText:   item.Content,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			Tags:          item.Categories,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(feed)
+}
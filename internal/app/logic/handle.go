@@ -0,0 +1,87 @@
+package logic
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type handle struct{}
+
+var Handle = &handle{}
+
+// Resolve returns the account number the input refers to. Inputs starting with "@" are handles
+// and are resolved through the entity claiming them or, during the grace period, the redirect history.
+// Anything else is returned as it is.
+func (h *handle) Resolve(input string) (string, error) {
+	if !util.IsHandle(input) {
+		return input, nil
+	}
+	handle := util.FormatHandle(input)
+
+	entity, err := mongo.Entity.FindByHandle(handle)
+	if err == nil {
+		return entity.AccountNumber, nil
+	}
+	redirect, err := mongo.HandleRedirect.FindActive(handle)
+	if err != nil {
+		return "", err
+	}
+	entity, err = mongo.Entity.FindByID(redirect.EntityID)
+	if err != nil {
+		return "", err
+	}
+	return entity.AccountNumber, nil
+}
+
+// PATCH /user/entities/{entityID}
+// PATCH /admin/entities/{entityID}
+
+// CheckAvailable checks whether the entity can claim the handle.
+// Admins can override reserved words and handles kept by other entities' redirect history.
+func (h *handle) CheckAvailable(handle string, entityID primitive.ObjectID, adminOverride bool) error {
+	owner, err := mongo.Entity.FindByHandle(handle)
+	if err == nil && owner.ID != entityID {
+		return errors.New("Handle @" + handle + " is already taken.")
+	}
+	if adminOverride {
+		return nil
+	}
+
+	for _, reserved := range viper.GetStringSlice("handle.reserved_words") {
+		if handle == reserved {
+			return errors.New("Handle @" + handle + " is reserved.")
+		}
+	}
+	redirect, err := mongo.HandleRedirect.FindActive(handle)
+	if err == nil && redirect.EntityID != entityID {
+		return errors.New("Handle @" + handle + " is already taken.")
+	}
+
+	return nil
+}
+
+// RecordChange keeps the old handle resolving to the entity for the grace period.
+func (h *handle) RecordChange(origin *types.Entity, updated *types.Entity) error {
+	if origin.Handle == updated.Handle {
+		return nil
+	}
+	// The new handle is no longer a redirect of any entity.
+	err := mongo.HandleRedirect.DeleteByHandle(updated.Handle)
+	if err != nil {
+		return err
+	}
+	if origin.Handle == "" {
+		return nil
+	}
+	return mongo.HandleRedirect.Create(&types.HandleRedirect{
+		Handle:    origin.Handle,
+		EntityID:  origin.ID,
+		ExpiresAt: time.Now().AddDate(0, 0, viper.GetInt("handle.redirect_grace_period")),
+	})
+}
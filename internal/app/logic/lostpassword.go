@@ -45,15 +45,22 @@ func (s *lostpassword) SetTokenUsed(token string) error {
 }
 
 func (s *lostpassword) IsTokenValid(l *types.LostPassword) bool {
-	if time.Now().Sub(l.CreatedAt).Seconds() >= viper.GetFloat64("reset_password_timeout") || l.TokenUsed == true {
+	if s.isExpired(l) || l.TokenUsed == true {
 		return false
 	}
 	return true
 }
 
 func (s *lostpassword) IsTokenInvalid(l *types.LostPassword) bool {
-	if time.Now().Sub(l.CreatedAt).Seconds() >= viper.GetFloat64("reset_password_timeout") || l.TokenUsed == true {
+	if s.isExpired(l) || l.TokenUsed == true {
 		return true
 	}
 	return false
 }
+
+func (s *lostpassword) isExpired(l *types.LostPassword) bool {
+	if !l.ExpiresAt.IsZero() {
+		return !time.Now().Before(l.ExpiresAt)
+	}
+	return time.Now().Sub(l.CreatedAt).Seconds() >= viper.GetFloat64("reset_password_timeout")
+}
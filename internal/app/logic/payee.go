@@ -0,0 +1,79 @@
+package logic
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type payee struct{}
+
+var Payee = &payee{}
+
+// POST /user/entities/{entityID}/payees
+
+func (p *payee) Create(req *types.CreatePayeeReq) (*types.Payee, error) {
+	created, err := mongo.Payee.Create(&types.Payee{
+		EntityID:           req.Entity.ID,
+		Nickname:           req.Nickname,
+		AccountNumber:      req.AccountNumber,
+		DefaultDescription: req.DefaultDescription,
+		DefaultAmount:      req.DefaultAmount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GET /user/entities/{entityID}/payees
+
+func (p *payee) FindByEntityID(entityID primitive.ObjectID) ([]*types.Payee, error) {
+	payees, err := mongo.Payee.FindByEntityID(entityID)
+	if err != nil {
+		return nil, err
+	}
+	return payees, nil
+}
+
+func (p *payee) FindByStringID(id string) (*types.Payee, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	payee, err := mongo.Payee.FindByID(objectID)
+	if err != nil {
+		return nil, err
+	}
+	return payee, nil
+}
+
+// PATCH /user/entities/{entityID}/payees/{payeeID}
+
+func (p *payee) FindOneAndUpdate(req *types.UpdatePayeeReq) (*types.Payee, error) {
+	updated, err := mongo.Payee.FindOneAndUpdate(req)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DELETE /user/entities/{entityID}/payees/{payeeID}
+
+func (p *payee) Delete(id primitive.ObjectID) error {
+	err := mongo.Payee.Delete(id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// POST /transfers
+
+func (p *payee) UpdateLastUsedAt(id primitive.ObjectID) error {
+	err := mongo.Payee.UpdateLastUsedAt(id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
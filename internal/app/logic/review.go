@@ -0,0 +1,127 @@
+package logic
+
+import (
+	"math"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/segmentio/ksuid"
+)
+
+type review struct{}
+
+var Review = &review{}
+
+// POST /transfers/{transferID}/reviews
+
+func (r *review) HasReviewed(transferID string, accountNumber string) (bool, error) {
+	return pg.Review.Exists(transferID, accountNumber)
+}
+
+func (r *review) Create(req *types.CreateReviewReq) (*types.Review, error) {
+	created, err := pg.Review.Create(&types.Review{
+		ReviewID:              ksuid.New().String(),
+		TransferID:            req.Journal.TransferID,
+		ReviewerAccountNumber: req.Reviewer.AccountNumber,
+		ReviewerEntityName:    req.Reviewer.Name,
+		RevieweeAccountNumber: req.Reviewee.AccountNumber,
+		RevieweeEntityName:    req.Reviewee.Name,
+		UserID:                req.UserID,
+		Rating:                req.Rating,
+		Body:                  req.Body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = r.updateRating(req.Reviewee)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// POST /reviews/{reviewID}/reply
+
+func (r *review) Reply(req *types.ReplyReviewReq) (*types.Review, error) {
+	now := time.Now()
+	req.Review.Reply = req.Body
+	req.Review.RepliedAt = &now
+	err := pg.Review.Update(req.Review)
+	if err != nil {
+		return nil, err
+	}
+	return req.Review, nil
+}
+
+// PATCH /admin/reviews/{reviewID}
+
+func (r *review) AdminUpdate(req *types.AdminUpdateReviewReq) (*types.Review, error) {
+	req.Review.Hidden = *req.Hidden
+	req.Review.ModerationNote = req.ModerationNote
+	req.Review.ModeratedBy = req.AdminID
+	err := pg.Review.Update(req.Review)
+	if err != nil {
+		return nil, err
+	}
+	err = r.updateRatingByAccountNumber(req.Review.RevieweeAccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	return req.Review, nil
+}
+
+// DELETE /admin/reviews/{reviewID}
+
+func (r *review) AdminDelete(review *types.Review) error {
+	err := pg.Review.Delete(review)
+	if err != nil {
+		return err
+	}
+	return r.updateRatingByAccountNumber(review.RevieweeAccountNumber)
+}
+
+func (r *review) FindByReviewID(reviewID string) (*types.Review, error) {
+	return pg.Review.FindByReviewID(reviewID)
+}
+
+// GET /transfers/{transferID}/reviews
+
+func (r *review) FindByTransferID(transferID string) ([]*types.Review, error) {
+	return pg.Review.FindByTransferID(transferID)
+}
+
+// GET /entities/{searchEntityID}/reviews
+// GET /admin/reviews
+
+func (r *review) Search(req *types.SearchReviewReq) (*types.SearchReviewResult, error) {
+	return pg.Review.Search(req)
+}
+
+func (r *review) updateRatingByAccountNumber(accountNumber string) error {
+	entity, err := Entity.FindByAccountNumber(accountNumber)
+	if err != nil {
+		return err
+	}
+	return r.updateRating(entity)
+}
+
+// updateRating stores the average rating of the entity's visible reviews in MongoDB and Elasticsearch.
+func (r *review) updateRating(entity *types.Entity) error {
+	rating, count, err := pg.Review.Rating(entity.AccountNumber)
+	if err != nil {
+		return err
+	}
+	rating = math.Round(rating*100) / 100
+	err = mongo.Entity.UpdateRating(entity.ID, rating, count)
+	if err != nil {
+		return err
+	}
+	err = es.Entity.UpdateRating(entity.ID, rating, count)
+	if err != nil {
+		return err
+	}
+	return nil
+}
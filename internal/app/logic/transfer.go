@@ -4,10 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
 type transfer struct{}
@@ -26,14 +31,25 @@ func (t *transfer) Search(req *types.SearchTransferReq) (*types.SearchTransferRe
 // POST /admin/transfers
 
 func (t *transfer) CheckBalance(payer, payee string, amount float64) error {
+	err := t.CheckSendingBalance(payer, amount)
+	if err != nil {
+		return err
+	}
+	return t.CheckReceivingBalance(payee, amount)
+}
+
+// CheckSendingBalance makes sure the payer stays within its credit limit.
+// The amount reserved by the payer's vouchers is not available for sending.
+func (t *transfer) CheckSendingBalance(payer string, amount float64) error {
 	from, err := pg.Account.FindByAccountNumber(payer)
 	if err != nil {
 		return err
 	}
-	to, err := pg.Account.FindByAccountNumber(payee)
+	reserved, err := Voucher.ReservedAmount(from.AccountNumber)
 	if err != nil {
 		return err
 	}
+	from.Balance -= reserved
 
 	exceed, err := BalanceLimit.IsExceedLimit(from.AccountNumber, from.Balance-amount)
 	if err != nil {
@@ -46,8 +62,16 @@ func (t *transfer) CheckBalance(payer, payee string, amount float64) error {
 		}
 		return errors.New("Sender will exceed its credit limit." + " The maximum amount that can be sent is: " + fmt.Sprintf("%.2f", amount))
 	}
+	return nil
+}
 
-	exceed, err = BalanceLimit.IsExceedLimit(to.AccountNumber, to.Balance+amount)
+func (t *transfer) CheckReceivingBalance(payee string, amount float64) error {
+	to, err := pg.Account.FindByAccountNumber(payee)
+	if err != nil {
+		return err
+	}
+
+	exceed, err := BalanceLimit.IsExceedLimit(to.AccountNumber, to.Balance+amount)
 	if err != nil {
 		return err
 	}
@@ -58,10 +82,54 @@ func (t *transfer) CheckBalance(payer, payee string, amount float64) error {
 		}
 		return errors.New("Receiver will exceed its maximum balance limit." + " The maximum amount that can be received is: " + fmt.Sprintf("%.2f", amount))
 	}
+	return nil
+}
 
+// POST /transfers
+// PATCH /transfers/{transferID}
+// POST /admin/transfers
+
+func (t *transfer) CheckFreeze(payer, payee string) error {
+	err := t.checkSendingFreeze(payer)
+	if err != nil {
+		return err
+	}
+	return t.checkReceivingFreeze(payee)
+}
+
+func (t *transfer) checkSendingFreeze(payer string) error {
+	from, err := pg.Account.FindByAccountNumber(payer)
+	if err != nil {
+		return err
+	}
+	if from.IsSendingFrozen() {
+		return errors.New("Sender's account has been frozen and cannot send transfers. Reason: " + from.FreezeReason)
+	}
+	return nil
+}
+
+func (t *transfer) checkReceivingFreeze(payee string) error {
+	to, err := pg.Account.FindByAccountNumber(payee)
+	if err != nil {
+		return err
+	}
+	if to.IsReceivingFrozen() {
+		return errors.New("Receiver's account has been frozen and cannot receive transfers. Reason: " + to.FreezeReason)
+	}
 	return nil
 }
 
+// POST /transfers
+
+// ShouldAutoAccept checks whether the receiver accepts the proposed transfer without reviewing it.
+// Only transfers sending money to the receiver can be accepted automatically.
+func (t *transfer) ShouldAutoAccept(req *types.TransferReq) bool {
+	if req.TransferDirection != constant.TransferDirection.Out {
+		return false
+	}
+	return req.ReceiverEntity.AutoAccepts(req.InitiatorEntity, req.Amount)
+}
+
 // PATCH /transfers/{transferID}
 
 func (t *transfer) FindByID(transferID string) (*types.Journal, error) {
@@ -86,6 +154,24 @@ func (t *transfer) Propose(req *types.TransferReq) (*types.Journal, error) {
 	return journal, nil
 }
 
+// Escrow debits the payer straight away but only credits the payee once the escrow is released.
+func (t *transfer) Escrow(req *types.TransferReq) (*types.Journal, error) {
+	releaseAt := time.Now().AddDate(0, 0, viper.GetInt("escrow.release_after_days"))
+	journal, err := pg.Journal.Escrow(req, viper.GetString("escrow.account_number"), releaseAt)
+	if err != nil {
+		return nil, err
+	}
+	err = es.Journal.Create(journal)
+	if err != nil {
+		return nil, err
+	}
+	err = t.updateESEntityBalances(journal)
+	if err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
 func (t *transfer) maxPositiveBalanceCanBeTransferred(a *types.Account) (float64, error) {
 	maxPosBal, err := BalanceLimit.GetMaxPosBalance(a.AccountNumber)
 	if err != nil {
@@ -110,8 +196,8 @@ func (t *transfer) maxNegativeBalanceCanBeTransferred(a *types.Account) (float64
 
 // PATCH /transfers/{transferID}
 
-func (t *transfer) Accept(j *types.Journal) (*types.Journal, error) {
-	updated, err := pg.Journal.Accept(j)
+func (t *transfer) Accept(j *types.Journal, acceptedBy string) (*types.Journal, error) {
+	updated, err := pg.Journal.Accept(j, acceptedBy)
 	if err != nil {
 		return nil, err
 	}
@@ -146,8 +232,9 @@ func (t *transfer) updateESEntityBalances(j *types.Journal) error {
 	return nil
 }
 
-func (t *transfer) Cancel(transferID string, reason string) (*types.Journal, error) {
-	canceled, err := pg.Journal.Cancel(transferID, reason)
+// Cancel cancels the transfer. cancelledBy is empty when the transfer is cancelled by the system.
+func (t *transfer) Cancel(transferID string, reason string, cancelledBy string) (*types.Journal, error) {
+	canceled, err := pg.Journal.Cancel(transferID, reason, cancelledBy)
 	if err != nil {
 		return nil, err
 	}
@@ -158,6 +245,75 @@ func (t *transfer) Cancel(transferID string, reason string) (*types.Journal, err
 	return canceled, nil
 }
 
+// PATCH /transfers/{transferID}
+// PATCH /admin/transfers/{transferID}
+
+func (t *transfer) CheckReleaseEscrow(j *types.Journal) error {
+	to, err := pg.Account.FindByAccountNumber(j.ToAccountNumber)
+	if err != nil {
+		return err
+	}
+	if to.IsReceivingFrozen() {
+		return errors.New("Receiver's account has been frozen and cannot receive transfers. Reason: " + to.FreezeReason)
+	}
+	return t.CheckReceivingBalance(j.ToAccountNumber, j.Amount)
+}
+
+func (t *transfer) ReleaseEscrow(j *types.Journal) (*types.Journal, error) {
+	released, err := pg.Journal.ReleaseEscrow(j, viper.GetString("escrow.account_number"))
+	if err != nil {
+		return nil, err
+	}
+	err = es.Journal.Update(released)
+	if err != nil {
+		return nil, err
+	}
+	err = t.updateESEntityBalances(released)
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+func (t *transfer) RefundEscrow(j *types.Journal, reason string) (*types.Journal, error) {
+	refunded, err := pg.Journal.RefundEscrow(j, viper.GetString("escrow.account_number"), reason)
+	if err != nil {
+		return nil, err
+	}
+	err = es.Journal.Update(refunded)
+	if err != nil {
+		return nil, err
+	}
+	err = t.updateESEntityBalances(refunded)
+	if err != nil {
+		return nil, err
+	}
+	return refunded, nil
+}
+
+// ReleaseDueEscrows releases the escrowed transfers whose release time has passed.
+func (t *transfer) ReleaseDueEscrows() ([]*types.Journal, error) {
+	journals, err := pg.Journal.GetEscrowDue(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	released := make([]*types.Journal, 0, len(journals))
+	for _, j := range journals {
+		err := t.CheckReleaseEscrow(j)
+		if err != nil {
+			// Left in escrow so an admin can release or refund it.
+			l.Logger.Info("[INFO] escrow of transfer "+j.TransferID+" cannot be released:", zap.Error(err))
+			continue
+		}
+		updated, err := t.ReleaseEscrow(j)
+		if err != nil {
+			return released, err
+		}
+		released = append(released, updated)
+	}
+	return released, nil
+}
+
 // GET /user/entities
 
 func (t *transfer) GetPendingTransfers(accountNumber string) ([]*types.TransferRespond, error) {
@@ -168,6 +324,12 @@ func (t *transfer) GetPendingTransfers(accountNumber string) ([]*types.TransferR
 	return types.NewJournalsToTransfersRespond(journals, accountNumber), nil
 }
 
+// GET /transfers/{transferID}
+
+func (t *transfer) FindPostings(j *types.Journal) ([]*types.Posting, error) {
+	return pg.Posting.FindByJournalID(j.ID)
+}
+
 // GET /admin/transfers/{transferID}
 
 func (t *transfer) AdminGetTransfer(transferID string) (*types.Journal, error) {
@@ -178,7 +340,32 @@ func (t *transfer) AdminGetTransfer(transferID string) (*types.Journal, error) {
 	return journal, nil
 }
 
+// POST /admin/accounts/{accountNumber}/opening-balance
+
+// CreateOpeningBalance records the balance migrated from a previous system.
+// Each account can only have one opening balance. Opening balances above the approval threshold are created pending approval.
+func (t *transfer) CreateOpeningBalance(req *types.AdminOpeningBalanceReq, requestedBy string) (*types.Journal, error) {
+	requiresApproval := t.isAboveApprovalThreshold(math.Abs(req.Amount))
+	created, err := pg.Journal.CreateOpeningBalance(req, viper.GetString("opening_balance.account_number"), requiresApproval, requestedBy)
+	if err != nil {
+		return nil, err
+	}
+	err = es.Journal.Create(created)
+	if err != nil {
+		return nil, err
+	}
+	if requiresApproval {
+		return created, nil
+	}
+	err = t.updateESEntityBalances(created)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
 // POST /admin/transfers
+// POST /admin/transfers/corrections
 
 func (t *transfer) Create(req *types.AdminTransferReq) (*types.Journal, error) {
 	created, err := pg.Journal.Create(req)
@@ -196,6 +383,118 @@ func (t *transfer) Create(req *types.AdminTransferReq) (*types.Journal, error) {
 	return created, nil
 }
 
+// RequiresApproval checks whether the admin transfer is above the approval threshold.
+// A threshold of zero disables the approval.
+func (t *transfer) RequiresApproval(req *types.AdminTransferReq) bool {
+	return t.isAboveApprovalThreshold(req.Amount)
+}
+
+func (t *transfer) isAboveApprovalThreshold(amount float64) bool {
+	threshold := viper.GetFloat64("admin_transfer.approval_threshold")
+	return threshold > 0 && amount > threshold
+}
+
+func (t *transfer) CreatePendingApproval(req *types.AdminTransferReq, requestedBy string) (*types.Journal, error) {
+	created, err := pg.Journal.CreatePendingApproval(req, requestedBy)
+	if err != nil {
+		return nil, err
+	}
+	err = es.Journal.Create(created)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// POST /admin/transfers/imports/{importID}/execution
+
+// IndexBatch adds the transfers created in a batch to the search index.
+func (t *transfer) IndexBatch(created []*types.Journal) error {
+	for _, j := range created {
+		err := es.Journal.Create(j)
+		if err != nil {
+			return err
+		}
+		if j.Status != constant.Transfer.Completed {
+			continue
+		}
+		err = t.updateESEntityBalances(j)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GET /admin/transfers/approvals
+
+func (t *transfer) GetPendingApproval() ([]*types.Journal, error) {
+	return pg.Journal.GetPendingApproval()
+}
+
+// PATCH /admin/transfers/{transferID}
+
+// CheckApprove checks the transfer can be approved by the admin.
+// Limits are checked again since the balances may have changed since the request.
+func (t *transfer) CheckApprove(j *types.Journal, adminID string) error {
+	if j.RequestedBy == adminID {
+		return errors.New("A transfer must be approved by a different admin than the one who requested it.")
+	}
+	err := t.checkApproveFreeze(j)
+	if err != nil {
+		return err
+	}
+	if !constant.TransferTypeRules[j.Type].EnforceLimits {
+		return nil
+	}
+	return t.CheckBalance(j.FromAccountNumber, j.ToAccountNumber, j.Amount)
+}
+
+// checkApproveFreeze checks the accounts are not frozen.
+// The account being closed is frozen, so only the other account of its settlement transfer is checked.
+func (t *transfer) checkApproveFreeze(j *types.Journal) error {
+	closure, err := pg.AccountClosure.FindBySettlementTransferID(j.TransferID)
+	if err != nil {
+		return err
+	}
+	if closure == nil {
+		return t.CheckFreeze(j.FromAccountNumber, j.ToAccountNumber)
+	}
+	if j.FromAccountNumber == closure.AccountNumber {
+		return t.checkReceivingFreeze(j.ToAccountNumber)
+	}
+	return t.checkSendingFreeze(j.FromAccountNumber)
+}
+
+func (t *transfer) Approve(j *types.Journal, adminID string) (*types.Journal, error) {
+	approved, err := pg.Journal.Approve(j, adminID)
+	if err != nil {
+		return nil, err
+	}
+	err = es.Journal.Update(approved)
+	if err != nil {
+		return nil, err
+	}
+	err = t.updateESEntityBalances(approved)
+	if err != nil {
+		return nil, err
+	}
+	return approved, nil
+}
+
+// Reject cancels the transfer pending approval. The requesting admin can also reject it to withdraw the request.
+func (t *transfer) Reject(j *types.Journal, adminID string, reason string) (*types.Journal, error) {
+	rejected, err := pg.Journal.Reject(j, adminID, reason)
+	if err != nil {
+		return nil, err
+	}
+	err = es.Journal.Update(rejected)
+	if err != nil {
+		return nil, err
+	}
+	return rejected, nil
+}
+
 // GET /admin/transfers
 
 func (t *transfer) AdminSearch(req *types.AdminSearchTransferReq) (*types.AdminSearchTransferRespond, error) {
@@ -0,0 +1,30 @@
+package logic
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+)
+
+type transferComment struct{}
+
+var TransferComment = &transferComment{}
+
+// POST /transfers/{transferID}/comments
+
+func (t *transferComment) Create(req *types.TransferCommentReq) (*types.TransferComment, error) {
+	return pg.TransferComment.Create(&types.TransferComment{
+		TransferID:    req.Journal.TransferID,
+		AccountNumber: req.Entity.AccountNumber,
+		EntityName:    req.Entity.Name,
+		UserID:        req.UserID,
+		Body:          req.Body,
+	})
+}
+
+// GET /transfers/{transferID}
+// GET /transfers/{transferID}/comments
+// GET /admin/transfers/{transferID}
+
+func (t *transferComment) FindByTransferID(transferID string) ([]*types.TransferComment, error) {
+	return pg.TransferComment.FindByTransferID(transferID)
+}
@@ -0,0 +1,307 @@
+package logic
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/camt053"
+	"github.com/segmentio/ksuid"
+)
+
+type transferExport struct{}
+
+var TransferExport = &transferExport{}
+
+var transferExportContentTypes = map[string]string{
+	constant.TransferExportFormat.CSV:       "text/csv",
+	constant.TransferExportFormat.OFX:       "application/x-ofx",
+	constant.TransferExportFormat.QIF:       "application/qif",
+	constant.TransferExportFormat.Beancount: "text/plain; charset=utf-8",
+	constant.TransferExportFormat.Camt053:   "application/xml",
+}
+
+var transferExportExtensions = map[string]string{
+	constant.TransferExportFormat.CSV:       "csv",
+	constant.TransferExportFormat.OFX:       "ofx",
+	constant.TransferExportFormat.QIF:       "qif",
+	constant.TransferExportFormat.Beancount: "beancount",
+	constant.TransferExportFormat.Camt053:   "xml",
+}
+
+func (t *transferExport) ContentType(req *types.TransferExportReq) string {
+	return transferExportContentTypes[req.Format]
+}
+
+func (t *transferExport) FileName(req *types.TransferExportReq) string {
+	account := "all"
+	if req.Entity != nil {
+		account = req.Entity.AccountNumber
+	}
+	return "transfers-" + account + "." + transferExportExtensions[req.Format]
+}
+
+// GET /transfers/export
+// GET /admin/transfers/export
+
+// Export writes the completed transfers of the period in the requested format.
+// Every transfer is identified by its transfer ID so importing the same period twice doesn't create duplicates.
+func (t *transferExport) Export(req *types.TransferExportReq, w io.Writer) error {
+	if req.Format == constant.TransferExportFormat.Camt053 {
+		return t.writeCamt053(req, w)
+	}
+
+	entries, err := t.findEntries(req)
+	if err != nil {
+		return err
+	}
+
+	switch req.Format {
+	case constant.TransferExportFormat.OFX:
+		balance, err := pg.Posting.BalanceAt(req.Entity.AccountNumber, req.To)
+		if err != nil {
+			return err
+		}
+		return t.writeOFX(req, entries, balance, w)
+	case constant.TransferExportFormat.QIF:
+		return t.writeQIF(entries, w)
+	case constant.TransferExportFormat.Beancount:
+		return t.writeBeancount(req, entries, w)
+	default:
+		return t.writeCSV(entries, w)
+	}
+}
+
+// findEntries returns the postings of the entity's account, or one entry per transfer for the system-wide export.
+func (t *transferExport) findEntries(req *types.TransferExportReq) ([]*types.StatementEntry, error) {
+	if req.Entity != nil {
+		return pg.Posting.FindCompletedEntries(req.Entity.AccountNumber, req.From, req.To)
+	}
+
+	journals, err := pg.Journal.FindCompleted(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*types.StatementEntry, 0, len(journals))
+	for _, j := range journals {
+		entries = append(entries, &types.StatementEntry{
+			TransferID:        j.TransferID,
+			Type:              j.Type,
+			Description:       j.Description,
+			FromAccountNumber: j.FromAccountNumber,
+			FromEntityName:    j.FromEntityName,
+			ToAccountNumber:   j.ToAccountNumber,
+			ToEntityName:      j.ToEntityName,
+			Amount:            j.Amount,
+			CreatedAt:         j.CompletedAt,
+		})
+	}
+	return entries, nil
+}
+
+func (t *transferExport) writeCSV(entries []*types.StatementEntry, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"date", "transferID", "type", "fromAccountNumber", "fromEntityName", "toAccountNumber", "toEntityName", "description", "amount", "unit"})
+	for _, e := range entries {
+		writer.Write([]string{
+			e.CreatedAt.UTC().Format(time.RFC3339),
+			e.TransferID,
+			e.Type,
+			e.FromAccountNumber,
+			e.FromEntityName,
+			e.ToAccountNumber,
+			e.ToEntityName,
+			e.Description,
+			fmt.Sprintf("%.2f", e.Amount),
+			constant.Unit.UK,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeOFX writes an OFX 2.2 bank statement.
+func (t *transferExport) writeOFX(req *types.TransferExportReq, entries []*types.StatementEntry, balance float64, w io.Writer) error {
+	const dateFormat = "20060102150405"
+	b := &strings.Builder{}
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	b.WriteString(`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	b.WriteString("<OFX>\n")
+	b.WriteString("<SIGNONMSGSRSV1><SONRS>")
+	b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>")
+	fmt.Fprintf(b, "<DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE>", time.Now().UTC().Format(dateFormat))
+	b.WriteString("</SONRS></SIGNONMSGSRSV1>\n")
+	b.WriteString("<BANKMSGSRSV1><STMTTRNRS>\n")
+	b.WriteString("<TRNUID>0</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	fmt.Fprintf(b, "<STMTRS><CURDEF>%s</CURDEF>\n", constant.UnitCurrencyCode[constant.Unit.UK])
+	fmt.Fprintf(b, "<BANKACCTFROM><BANKID>MCCS</BANKID><ACCTID>%s</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>\n", escapeXML(req.Entity.AccountNumber))
+	fmt.Fprintf(b, "<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n", req.From.UTC().Format(dateFormat), req.To.UTC().Format(dateFormat))
+	for _, e := range entries {
+		trnType := "CREDIT"
+		if e.Amount < 0 {
+			trnType = "DEBIT"
+		}
+		b.WriteString("<STMTTRN>")
+		fmt.Fprintf(b, "<TRNTYPE>%s</TRNTYPE>", trnType)
+		fmt.Fprintf(b, "<DTPOSTED>%s</DTPOSTED>", e.CreatedAt.UTC().Format(dateFormat))
+		fmt.Fprintf(b, "<TRNAMT>%.2f</TRNAMT>", e.Amount)
+		fmt.Fprintf(b, "<FITID>%s</FITID>", escapeXML(e.TransferID))
+		fmt.Fprintf(b, "<NAME>%s</NAME>", escapeXML(truncate(e.CounterpartyEntityName(), 32)))
+		if e.Description != "" {
+			fmt.Fprintf(b, "<MEMO>%s</MEMO>", escapeXML(truncate(e.Description, 255)))
+		}
+		b.WriteString("</STMTTRN>\n")
+	}
+	b.WriteString("</BANKTRANLIST>\n")
+	fmt.Fprintf(b, "<LEDGERBAL><BALAMT>%.2f</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>\n", balance, req.To.UTC().Format(dateFormat))
+	b.WriteString("</STMTRS></STMTTRNRS></BANKMSGSRSV1>\n")
+	b.WriteString("</OFX>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCamt053 writes an ISO 20022 camt.053 statement. The currency code of the unit is used for the amounts
+// and the unit itself is named in the additional statement information.
+// Every posting of the period is an entry, including the postings of escrowed transfers that are not completed yet
+// and of refunded escrows, so the closing balance is the opening balance plus the entries.
+func (t *transferExport) writeCamt053(req *types.TransferExportReq, w io.Writer) error {
+	openingBalance, err := pg.Posting.BalanceAt(req.Entity.AccountNumber, req.From)
+	if err != nil {
+		return err
+	}
+	entries, err := pg.Posting.FindStatementEntries(req.Entity.AccountNumber, req.From, req.To)
+	if err != nil {
+		return err
+	}
+
+	statement := &camt053.Statement{
+		MsgID:          ksuid.New().String(),
+		AccountNumber:  req.Entity.AccountNumber,
+		AccountName:    req.Entity.Name,
+		Currency:       constant.UnitCurrencyCode[constant.Unit.UK],
+		AdditionalInfo: "Amounts in " + constant.Unit.UK + ".",
+		From:           req.From,
+		To:             req.To,
+		OpeningBalance: openingBalance,
+		Postings:       make([]camt053.Posting, 0, len(entries)),
+	}
+	for _, e := range entries {
+		statement.Postings = append(statement.Postings, camt053.Posting{
+			TransferID:      e.TransferID,
+			Type:            e.Type,
+			Description:     e.Description,
+			DebtorAccount:   e.FromAccountNumber,
+			DebtorName:      e.FromEntityName,
+			CreditorAccount: e.ToAccountNumber,
+			CreditorName:    e.ToEntityName,
+			Amount:          e.Amount,
+			CreatedAt:       e.CreatedAt,
+		})
+	}
+	return camt053.NewDocument(statement, time.Now()).Write(w)
+}
+
+// writeQIF writes a bank QIF file. QIF has no transaction ID field so the transfer ID goes into the check number.
+func (t *transferExport) writeQIF(entries []*types.StatementEntry, w io.Writer) error {
+	b := &strings.Builder{}
+	b.WriteString("!Type:Bank\n")
+	for _, e := range entries {
+		fmt.Fprintf(b, "D%s\n", e.CreatedAt.UTC().Format("01/02/2006"))
+		fmt.Fprintf(b, "T%.2f\n", e.Amount)
+		fmt.Fprintf(b, "N%s\n", e.TransferID)
+		fmt.Fprintf(b, "P%s\n", singleLine(e.CounterpartyEntityName()))
+		if e.Description != "" {
+			fmt.Fprintf(b, "M%s\n", singleLine(e.Description))
+		}
+		b.WriteString("^\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeBeancount writes a Beancount ledger. Every account is opened on the date of the first transfer.
+// For an entity, its own account is an asset and the counterparties are income or expenses.
+// For the system-wide export, every account is an asset.
+func (t *transferExport) writeBeancount(req *types.TransferExportReq, entries []*types.StatementEntry, w io.Writer) error {
+	commodity := strings.ToUpper(constant.Unit.UK)
+	accountName := func(e *types.StatementEntry) (from string, to string) {
+		if req.Entity == nil {
+			return "Assets:MCCS:" + e.FromAccountNumber, "Assets:MCCS:" + e.ToAccountNumber
+		}
+		own := "Assets:MCCS:" + req.Entity.AccountNumber
+		if e.Amount > 0 {
+			return "Income:MCCS:" + e.CounterpartyAccountNumber(), own
+		}
+		return own, "Expenses:MCCS:" + e.CounterpartyAccountNumber()
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "option \"operating_currency\" \"%s\"\n\n", commodity)
+
+	if len(entries) > 0 {
+		opened := map[string]bool{}
+		accounts := []string{}
+		for _, e := range entries {
+			from, to := accountName(e)
+			for _, account := range []string{from, to} {
+				if !opened[account] {
+					opened[account] = true
+					accounts = append(accounts, account)
+				}
+			}
+		}
+		sort.Strings(accounts)
+		openedAt := entries[0].CreatedAt.UTC().Format("2006-01-02")
+		for _, account := range accounts {
+			fmt.Fprintf(b, "%s open %s %s\n", openedAt, account, commodity)
+		}
+		b.WriteString("\n")
+	}
+
+	for _, e := range entries {
+		payee := e.ToEntityName
+		if req.Entity != nil {
+			payee = e.CounterpartyEntityName()
+		}
+		amount := e.Amount
+		if amount < 0 {
+			amount = -amount
+		}
+		from, to := accountName(e)
+		fmt.Fprintf(b, "%s * %s %s\n", e.CreatedAt.UTC().Format("2006-01-02"), quoteBeancount(payee), quoteBeancount(e.Description))
+		fmt.Fprintf(b, "  transfer_id: %s\n", quoteBeancount(e.TransferID))
+		fmt.Fprintf(b, "  %s  %.2f %s\n", from, -amount, commodity)
+		fmt.Fprintf(b, "  %s  %.2f %s\n\n", to, amount, commodity)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeXML(s string) string {
+	b := &strings.Builder{}
+	xml.EscapeText(b, []byte(s))
+	return b.String()
+}
+
+func quoteBeancount(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(singleLine(s)) + `"`
+}
+
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
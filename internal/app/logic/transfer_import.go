@@ -0,0 +1,353 @@
+package logic
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShiraazMoollatjie/goluhn"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/segmentio/ksuid"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type transferImport struct{}
+
+var TransferImport = &transferImport{}
+
+var transferImportColumns = []string{"payer", "payee", "amount", "description"}
+
+// POST /admin/transfers/imports
+
+// Parse reads the rows of a CSV file with a "payer,payee,amount,description" header.
+// The description column is optional.
+func (t *transferImport) Parse(r io.Reader) ([]*types.TransferImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("The file is empty.")
+	}
+	if err != nil {
+		return nil, errors.New("The file is not a valid CSV file: " + err.Error())
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range transferImportColumns[:3] {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.New("The file is missing the " + name + " column.")
+		}
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rows := []*types.TransferImportRow{}
+	// The header is the first line.
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("The file is not a valid CSV file: " + err.Error())
+		}
+		line++
+		// An unparsable amount is left at zero and reported by the validation.
+		amount, _ := strconv.ParseFloat(field(record, "amount"), 64)
+		rows = append(rows, &types.TransferImportRow{
+			Line:        line,
+			Payer:       field(record, "payer"),
+			Payee:       field(record, "payee"),
+			Amount:      amount,
+			Description: field(record, "description"),
+		})
+		if len(rows) > viper.GetInt("transfer_import.max_rows") {
+			return nil, errors.New("The file cannot have more than " + viper.GetString("transfer_import.max_rows") + " rows.")
+		}
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("The file does not have any rows.")
+	}
+	return rows, nil
+}
+
+// Create validates the rows and stores the dry-run report. Nothing is transferred until the import is executed.
+func (t *transferImport) Create(rows []*types.TransferImportRow, fileName string, requestedBy string) (*types.TransferImport, error) {
+	t.validate(rows)
+	return pg.TransferImport.Create(&types.TransferImport{
+		ImportID:    ksuid.New().String(),
+		FileName:    fileName,
+		RequestedBy: requestedBy,
+		Status:      constant.TransferImport.Validated,
+		Rows:        rows,
+	})
+}
+
+// validate checks every row as if the previous rows of the file had already been transferred.
+// It returns the requests of the valid rows, the invalid rows are nil.
+// Rows transferred by an execution that was interrupted and reset keep their result and are not transferred again.
+func (t *transferImport) validate(rows []*types.TransferImportRow) []*types.AdminTransferReq {
+	reqs := make([]*types.AdminTransferReq, len(rows))
+	// The balance changes caused by the previous rows.
+	changes := map[string]float64{}
+	for i, row := range rows {
+		if row.TransferID != "" {
+			continue
+		}
+		req, err := t.validateRow(row, changes)
+		if err != nil {
+			row.Status = constant.TransferImportRow.Invalid
+			row.Error = err.Error()
+			continue
+		}
+		row.Status = constant.TransferImportRow.Valid
+		row.Error = ""
+		reqs[i] = req
+		changes[req.PayerEntity.AccountNumber] -= req.Amount
+		changes[req.PayeeEntity.AccountNumber] += req.Amount
+	}
+	return reqs
+}
+
+func (t *transferImport) validateRow(row *types.TransferImportRow, changes map[string]float64) (*types.AdminTransferReq, error) {
+	payer, err := t.findEntity(row.Payer, "Payer")
+	if err != nil {
+		return nil, err
+	}
+	payee, err := t.findEntity(row.Payee, "Payee")
+	if err != nil {
+		return nil, err
+	}
+	req, errs := types.NewAdminTransferReq(&types.AdminTransferUserReq{
+		Payer:       payer.AccountNumber,
+		Payee:       payee.AccountNumber,
+		Amount:      row.Amount,
+		Description: row.Description,
+	}, payer, payee, constant.TransferType.AdminTransfer)
+	if len(errs) > 0 {
+		messages := make([]string, 0, len(errs))
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		return nil, errors.New(strings.Join(messages, " "))
+	}
+	err = Transfer.CheckFreeze(payer.AccountNumber, payee.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	err = t.checkBalances(req, changes)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (t *transferImport) findEntity(input string, party string) (*types.Entity, error) {
+	if input == "" {
+		return nil, errors.New(party + " is empty.")
+	}
+	if !util.IsHandle(input) && goluhn.Validate(input) != nil {
+		return nil, errors.New(party + " account number is invalid.")
+	}
+	accountNumber, err := Handle.Resolve(input)
+	if err != nil {
+		return nil, err
+	}
+	return Entity.FindByAccountNumber(accountNumber)
+}
+
+func (t *transferImport) checkBalances(req *types.AdminTransferReq, changes map[string]float64) error {
+	from, err := pg.Account.FindByAccountNumber(req.PayerEntity.AccountNumber)
+	if err != nil {
+		return err
+	}
+	reserved, err := Voucher.ReservedAmount(from.AccountNumber)
+	if err != nil {
+		return err
+	}
+	exceed, err := BalanceLimit.IsExceedLimit(from.AccountNumber, from.Balance-reserved+changes[from.AccountNumber]-req.Amount)
+	if err != nil {
+		return err
+	}
+	if exceed {
+		return errors.New("Sender will exceed its credit limit, taking the previous rows of the file into account.")
+	}
+
+	to, err := pg.Account.FindByAccountNumber(req.PayeeEntity.AccountNumber)
+	if err != nil {
+		return err
+	}
+	exceed, err = BalanceLimit.IsExceedLimit(to.AccountNumber, to.Balance+changes[to.AccountNumber]+req.Amount)
+	if err != nil {
+		return err
+	}
+	if exceed {
+		return errors.New("Receiver will exceed its maximum balance limit, taking the previous rows of the file into account.")
+	}
+	return nil
+}
+
+// GET /admin/transfers/imports/{importID}
+
+func (t *transferImport) FindByImportID(importID string) (*types.TransferImport, error) {
+	return pg.TransferImport.FindByImportID(importID)
+}
+
+// POST /admin/transfers/imports/{importID}/execution
+
+// Execute validates the rows again, since the balances may have changed since the upload, and creates the transfers.
+// Rows above the approval threshold are created pending approval.
+// The import is claimed first so concurrent or repeated requests cannot create the transfers twice.
+func (t *transferImport) Execute(record *types.TransferImport, mode string, executedBy string) (*types.TransferImport, error) {
+	claimed, err := pg.TransferImport.Claim(record.ImportID)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrTransferImportExecuted
+	}
+
+	reqs := t.validate(record.Rows)
+	requiresApproval := make([]bool, len(reqs))
+	for i, req := range reqs {
+		requiresApproval[i] = req != nil && Transfer.RequiresApproval(req)
+	}
+
+	if mode == constant.TransferImportMode.Atomic {
+		return t.executeAtomic(record, reqs, requiresApproval, executedBy)
+	}
+
+	for i, row := range record.Rows {
+		if reqs[i] == nil {
+			continue
+		}
+		var journal *types.Journal
+		var err error
+		if requiresApproval[i] {
+			journal, err = Transfer.CreatePendingApproval(reqs[i], executedBy)
+		} else {
+			journal, err = Transfer.Create(reqs[i])
+		}
+		if err != nil {
+			row.Status = constant.TransferImportRow.Failed
+			row.Error = err.Error()
+			continue
+		}
+		row.SetResult(journal)
+		// Saving the transfer ID right away keeps the row from being transferred again if the import is reset.
+		err = pg.TransferImport.SaveRow(record, row)
+		if err != nil {
+			l.Logger.Error("logic.TransferImport.Execute failed", zap.Error(err))
+		}
+	}
+
+	// The import stays claimed when saving the results fails, the transfers have been created.
+	now := time.Now()
+	record.Status = constant.TransferImport.Executed
+	record.Mode = mode
+	record.ExecutedAt = &now
+	err = pg.TransferImport.Update(record)
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// executeAtomic creates every transfer and saves the executed import in one transaction.
+// Nothing is created when a row is invalid, the import is released so it can be executed again.
+func (t *transferImport) executeAtomic(record *types.TransferImport, reqs []*types.AdminTransferReq, requiresApproval []bool, executedBy string) (*types.TransferImport, error) {
+	invalid := record.NumberOfRowsWithStatus(constant.TransferImportRow.Invalid)
+	if invalid > 0 {
+		err := pg.TransferImport.Update(record)
+		if err != nil {
+			return nil, err
+		}
+		return record, errors.New("The import has " + strconv.Itoa(invalid) + " invalid rows and cannot be executed atomically.")
+	}
+
+	now := time.Now()
+	record.Status = constant.TransferImport.Executed
+	record.Mode = constant.TransferImportMode.Atomic
+	record.ExecutedAt = &now
+	journals, err := pg.TransferImport.ExecuteBatch(record, reqs, requiresApproval, executedBy)
+	if err != nil {
+		record.Status = constant.TransferImport.Validated
+		record.Mode = ""
+		record.ExecutedAt = nil
+		for _, row := range record.Rows {
+			row.Status = constant.TransferImportRow.Valid
+			row.TransferID = ""
+		}
+		if err := pg.TransferImport.Update(record); err != nil {
+			l.Logger.Error("logic.TransferImport.executeAtomic failed", zap.Error(err))
+		}
+		return nil, err
+	}
+	// The transfers have been committed, so the import is executed even when they could not be indexed.
+	err = Transfer.IndexBatch(journals)
+	if err != nil {
+		l.Logger.Error("logic.TransferImport.executeAtomic failed", zap.Error(err))
+	}
+	return record, nil
+}
+
+// POST /admin/transfers/imports/{importID}/reset
+
+// Reset releases an import left executing, e.g. by a restart of the server, so it can be executed again.
+// Only an import that has not been updated for transfer_import.execution_timeout seconds can be reset,
+// the rows that have already been transferred are skipped when it is executed again.
+func (t *transferImport) Reset(record *types.TransferImport) (*types.TransferImport, error) {
+	timeout := time.Duration(viper.GetInt64("transfer_import.execution_timeout")) * time.Second
+	err := pg.TransferImport.Release(record.ImportID, time.Now().Add(-timeout))
+	if err != nil {
+		return nil, err
+	}
+	return pg.TransferImport.FindByImportID(record.ImportID)
+}
+
+// GET /admin/transfers/imports/{importID}/results.csv
+
+// WriteResults writes the rows of the import with their status, the created transfer IDs and the errors.
+func (t *transferImport) WriteResults(record *types.TransferImport, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(append([]string{"line"}, append(transferImportColumns, "status", "transferID", "error")...))
+	if err != nil {
+		return err
+	}
+	for _, row := range record.Rows {
+		err := writer.Write([]string{
+			strconv.Itoa(row.Line),
+			row.Payer,
+			row.Payee,
+			fmt.Sprintf("%.2f", row.Amount),
+			row.Description,
+			row.Status,
+			row.TransferID,
+			row.Error,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
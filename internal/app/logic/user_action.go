@@ -2,8 +2,10 @@ package logic
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
@@ -103,6 +105,20 @@ func (u *userAction) ModifyUser(origin *types.User, updated *types.User) {
 	u.create(ua)
 }
 
+// POST /user/entities
+
+func (u *userAction) CreateEntity(user *types.User, entity *types.Entity) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user created an entity",
+		// [EntityName] - [firstName] [lastName] - [email]
+		Detail:   entity.Name + " - " + user.FirstName + " " + user.LastName + " - " + user.Email,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
 // PATCH /user/entities/{entityID}
 
 func (u *userAction) ModifyEntity(userID string, origin *types.Entity, updated *types.Entity) {
@@ -124,6 +140,100 @@ func (u *userAction) ModifyEntity(userID string, origin *types.Entity, updated *
 	u.create(ua)
 }
 
+// POST /user/entities/{entityID}/invitations
+
+func (u *userAction) InviteEntityMember(user *types.User, entity *types.Entity, invitation *types.EntityInvitation) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user invited a member to an entity",
+		// [user] - [entity] - [invited email] - [role]
+		Detail:   user.Email + " - " + entity.Name + " - " + invitation.Email + " - " + invitation.Role,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /invitations/{token}
+
+func (u *userAction) JoinEntity(user *types.User, entity *types.Entity, role string) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user joined an entity",
+		// [user] - [entity] - [role]
+		Detail:   user.Email + " - " + entity.Name + " - " + role,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// PATCH /user/entities/{entityID}/members/{userID}
+// DELETE /user/entities/{entityID}/members/{userID}
+
+// ModifyEntityMember records a role change, or the removal of the member when the role is empty.
+func (u *userAction) ModifyEntityMember(userID string, entity *types.Entity, member *types.User, role string) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	action := "user changed the role of an entity member"
+	if role == "" {
+		action = "user removed a member from an entity"
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: action,
+		// [user] - [entity] - [member] - [role]
+		Detail:   user.Email + " - " + entity.Name + " - " + member.Email + " - " + role,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// PUT /user/entities/{entityID}/logo
+// DELETE /user/entities/{entityID}/logo
+// POST /user/entities/{entityID}/gallery
+// DELETE /user/entities/{entityID}/gallery/{imageID}
+
+func (u *userAction) ModifyEntityImage(userID string, entity *types.Entity, detail string) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user modified entity images",
+		// [user] - [entity] - [detail]
+		Detail:   user.Email + " - " + entity.Name + " - " + detail,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// PUT /user/entities/{entityID}/application/answers
+// POST /user/entities/{entityID}/application/documents
+// DELETE /user/entities/{entityID}/application/documents/{documentID}
+// POST /user/entities/{entityID}/application/submission
+
+func (u *userAction) ModifyEntityApplication(userID string, entity *types.Entity, detail string) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user modified entity application",
+		// [user] - [entity] - [detail]
+		Detail:   user.Email + " - " + entity.Name + " - " + detail,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
 // POST /transfers
 
 func (u *userAction) ProposeTransfer(userID string, req *types.TransferReq) {
@@ -152,6 +262,189 @@ func (u *userAction) AcceptTransfer(userID string, j *types.Journal) {
 	u.create(ua)
 }
 
+// POST /transfers
+
+func (u *userAction) AutoAcceptTransfer(receiver *types.Entity, j *types.Journal) {
+	ua := &types.UserAction{
+		Email:  receiver.Email,
+		Action: "transfer accepted automatically",
+		// [from] - [to] - [amount] - [desc]
+		Detail:   j.FromEntityName + " - " + j.FromAccountNumber + " -> " + j.ToEntityName + " - " + j.ToAccountNumber + " - " + fmt.Sprintf("%.2f", j.Amount) + " - " + j.Description,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// PATCH /transfers/{transferID}
+
+func (u *userAction) SettleEscrow(userID string, j *types.Journal) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user " + escrowAction(j) + " an escrowed transfer",
+		// [from] - [to] - [amount] - [desc]
+		Detail:   j.FromEntityName + " - " + j.FromAccountNumber + " -> " + j.ToEntityName + " - " + j.ToAccountNumber + " - " + fmt.Sprintf("%.2f", j.Amount) + " - " + j.Description,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+func escrowAction(j *types.Journal) string {
+	if j.Status == constant.Transfer.Completed {
+		return "released"
+	}
+	return "refunded"
+}
+
+// POST /vouchers
+
+func (u *userAction) IssueVoucher(userID string, v *types.Voucher) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user issued a voucher",
+		// [payer] - [amount] - [expiry] - [voucher]
+		Detail:   v.PayerEntityName + " - " + v.PayerAccountNumber + " - " + fmt.Sprintf("%.2f", v.Amount) + " - " + v.ExpiresAt.Format("2006-01-02 15:04:05") + " - " + v.Nonce,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /transfers/{transferID}/comments
+
+func (u *userAction) CommentTransfer(userID string, c *types.TransferComment) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user commented on a transfer",
+		// [entity] - [account] - [transfer] - [comment]
+		Detail:   c.EntityName + " - " + c.AccountNumber + " - " + c.TransferID + " - " + c.Body,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /transfers/{transferID}/reviews
+
+func (u *userAction) ReviewEntity(userID string, r *types.Review) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user reviewed an entity",
+		// [reviewer] - [reviewee] - [transfer] - [rating]
+		Detail:   r.ReviewerEntityName + " - " + r.RevieweeEntityName + " - " + r.TransferID + " - " + strconv.Itoa(r.Rating),
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /reviews/{reviewID}/reply
+
+func (u *userAction) ReplyReview(userID string, r *types.Review) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user replied to a review",
+		// [reviewee] - [review] - [reply]
+		Detail:   r.RevieweeEntityName + " - " + r.ReviewID + " - " + r.Reply,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// GET /transfers/{transferID}/receipt.pdf
+// GET /accounts/{accountNumber}/statement.pdf
+
+func (u *userAction) IssueDocument(userID string, d *types.Document) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user downloaded a " + d.Type,
+		// [entity] - [account] - [code]
+		Detail:   d.EntityName + " - " + d.AccountNumber + " - " + d.Code,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// GET /transfers/export
+
+func (u *userAction) ExportTransfers(userID string, req *types.TransferExportReq) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user exported transfers",
+		// [entity] - [account] - [format] - [from] - [to]
+		Detail:   req.Entity.Name + " - " + req.Entity.AccountNumber + " - " + req.Format + " - " + req.From.Format("2006-01-02") + " - " + req.To.Format("2006-01-02"),
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /vouchers/redeem
+
+func (u *userAction) RedeemVoucher(userID string, j *types.Journal) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user redeemed a voucher",
+		// [from] - [to] - [amount] - [desc]
+		Detail:   j.FromEntityName + " - " + j.FromAccountNumber + " -> " + j.ToEntityName + " - " + j.ToAccountNumber + " - " + fmt.Sprintf("%.2f", j.Amount) + " - " + j.Description,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// DELETE /vouchers/{voucherID}
+
+func (u *userAction) CancelVoucher(userID string, v *types.Voucher) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user cancelled a voucher",
+		// [payer] - [amount] - [voucher]
+		Detail:   v.PayerEntityName + " - " + v.PayerAccountNumber + " - " + fmt.Sprintf("%.2f", v.Amount) + " - " + v.Nonce,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
 // POST /admin/login
 
 func (u *userAction) AdminLogin(admin *types.AdminUser, ipAddress string) {
@@ -411,10 +704,17 @@ func (u *userAction) AdminTransfer(userID string, j *types.Journal) {
 	if err != nil {
 		return
 	}
+	action := "admin transfer for user"
+	if j.Type != constant.TransferType.AdminTransfer {
+		action = "admin created a " + j.Type + " journal"
+	}
+	if j.Status == constant.Transfer.PendingApproval {
+		action = "admin requested approval for a " + j.Type + " journal"
+	}
 	ua := &types.UserAction{
 		UserID: admin.ID,
 		Email:  admin.Email,
-		Action: "admin transfer for user",
+		Action: action,
 		// admin - [from] -> [to] - [amount]
 		Detail:   admin.Email + " - " + j.FromAccountNumber + " (" + j.FromEntityName + ") -> " + j.ToAccountNumber + " (" + j.ToEntityName + ") - " + fmt.Sprintf("%.2f", j.Amount) + " - " + j.Description,
 		Category: "admin",
@@ -422,6 +722,277 @@ func (u *userAction) AdminTransfer(userID string, j *types.Journal) {
 	u.create(ua)
 }
 
+// PATCH /admin/transfers/{transferID}
+
+func (u *userAction) AdminSettleEscrow(userID string, j *types.Journal) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin " + escrowAction(j) + " an escrowed transfer",
+		// [from] - [to] - [amount] - [desc]
+		Detail:   j.FromEntityName + " - " + j.FromAccountNumber + " -> " + j.ToEntityName + " - " + j.ToAccountNumber + " - " + fmt.Sprintf("%.2f", j.Amount) + " - " + j.Description,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+func (u *userAction) AdminReviewTransfer(userID string, j *types.Journal) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	action := "admin approved a " + j.Type + " journal"
+	if j.Status == constant.Transfer.Cancelled {
+		action = "admin rejected a " + j.Type + " journal"
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: action,
+		// admin - [transferID] - requested by [adminID] - [from] -> [to] - [amount] - [reason]
+		Detail:   admin.Email + " - " + j.TransferID + " - requested by " + j.RequestedBy + " - " + j.FromAccountNumber + " (" + j.FromEntityName + ") -> " + j.ToAccountNumber + " (" + j.ToEntityName + ") - " + fmt.Sprintf("%.2f", j.Amount) + " - " + j.CancellationReason,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/transfers/imports
+// POST /admin/transfers/imports/{importID}/execution
+
+func (u *userAction) AdminTransferImport(userID string, record *types.TransferImport) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	action := "admin uploaded a transfer import"
+	if record.Status == constant.TransferImport.Executed {
+		action = "admin executed a transfer import"
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: action,
+		// admin - [importID] - [file] - [mode] - rows: [total], invalid: [n], created: [n], pending approval: [n], failed: [n]
+		Detail: admin.Email + " - " + record.ImportID + " - " + record.FileName + " - " + record.Mode +
+			" - rows: " + strconv.Itoa(len(record.Rows)) +
+			", invalid: " + strconv.Itoa(record.NumberOfRowsWithStatus(constant.TransferImportRow.Invalid)) +
+			", created: " + strconv.Itoa(record.NumberOfRowsWithStatus(constant.TransferImportRow.Created)) +
+			", pending approval: " + strconv.Itoa(record.NumberOfRowsWithStatus(constant.TransferImportRow.PendingApproval)) +
+			", failed: " + strconv.Itoa(record.NumberOfRowsWithStatus(constant.TransferImportRow.Failed)),
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/transfers/imports/{importID}/reset
+
+func (u *userAction) AdminResetTransferImport(userID string, record *types.TransferImport) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin reset a transfer import",
+		// admin - [importID] - [file] - transferred rows: [n]
+		Detail:   admin.Email + " - " + record.ImportID + " - " + record.FileName + " - transferred rows: " + strconv.Itoa(record.NumberOfTransferredRows()),
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/entities/imports
+// POST /admin/entities/imports/{importID}/execution
+
+func (u *userAction) AdminEntityImport(userID string, record *types.EntityImport) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	action := "admin uploaded an entity import"
+	if record.Status == constant.EntityImport.Executed {
+		action = "admin executed an entity import"
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: action,
+		// admin - [importID] - [file] - invitations: [bool] - rows: [total], invalid: [n], created: [n], user added: [n], failed: [n]
+		Detail: admin.Email + " - " + record.ImportID + " - " + record.FileName +
+			" - invitations: " + strconv.FormatBool(record.SendInvitations) +
+			" - rows: " + strconv.Itoa(len(record.Rows)) +
+			", invalid: " + strconv.Itoa(record.NumberOfRowsWithStatus(constant.EntityImportRow.Invalid)) +
+			", created: " + strconv.Itoa(record.NumberOfRowsWithStatus(constant.EntityImportRow.Created)) +
+			", user added: " + strconv.Itoa(record.NumberOfRowsWithStatus(constant.EntityImportRow.UserAdded)) +
+			", failed: " + strconv.Itoa(record.NumberOfRowsWithStatus(constant.EntityImportRow.Failed)),
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// GET /admin/entities/export
+
+func (u *userAction) AdminExportEntities(userID string, req *types.EntityExportReq) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin exported entities",
+		// admin - [format]
+		Detail:   admin.Email + " - " + req.Format,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// GET /admin/transfers/export
+
+func (u *userAction) AdminExportTransfers(userID string, req *types.TransferExportReq) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	account := "all accounts"
+	if req.Entity != nil {
+		account = req.Entity.AccountNumber
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin exported transfers",
+		// admin - [account] - [format] - [from] - [to]
+		Detail:   admin.Email + " - " + account + " - " + req.Format + " - " + req.From.Format("2006-01-02") + " - " + req.To.Format("2006-01-02"),
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// PATCH /admin/accounts/{accountNumber}/freeze
+
+func (u *userAction) AdminFreezeAccount(userID string, origin *types.Account, updated *types.Account) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin modified account freeze",
+		// admin - [account] - [before] -> [after] - [reason]
+		Detail:   admin.Email + " - " + updated.AccountNumber + " - " + freezeSummary(origin) + " -> " + freezeSummary(updated) + " - " + updated.FreezeReason,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+func freezeSummary(a *types.Account) string {
+	summary := "sending: " + strconv.FormatBool(a.FrozenSending) + ", receiving: " + strconv.FormatBool(a.FrozenReceiving)
+	if a.FrozenUntil != nil {
+		summary += ", until: " + util.FormatTime(*a.FrozenUntil)
+	}
+	return summary
+}
+
+// POST /admin/accounts/{accountNumber}/closure
+
+func (u *userAction) AdminCloseAccount(userID string, closure *types.AccountClosure) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin processed account closure",
+		// admin - [account] - [step] - [error]
+		Detail:   admin.Email + " - " + closure.AccountNumber + " - " + closure.Step + " - " + closure.LastError,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// PATCH /admin/reviews/{reviewID}
+
+func (u *userAction) AdminModerateReview(userID string, r *types.Review) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin moderated review",
+		// admin - [review] - [reviewee] - [hidden] - [note]
+		Detail:   admin.Email + " - " + r.ReviewID + " - " + r.RevieweeEntityName + " - hidden: " + strconv.FormatBool(r.Hidden) + " - " + r.ModerationNote,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// DELETE /admin/reviews/{reviewID}
+
+func (u *userAction) AdminDeleteReview(userID string, r *types.Review) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin deleted review",
+		// admin - [review] - [reviewer] - [reviewee]
+		Detail:   admin.Email + " - " + r.ReviewID + " - " + r.ReviewerEntityName + " - " + r.RevieweeEntityName,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/application-questions
+// PATCH /admin/application-questions/{questionID}
+// DELETE /admin/application-questions/{questionID}
+
+func (u *userAction) AdminModifyApplicationQuestion(userID string, q *types.ApplicationQuestion, detail string) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin modified application question",
+		// admin - [detail] - [question]
+		Detail:   admin.Email + " - " + detail + " - " + q.Text,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/entities/{entityID}/application/review
+
+func (u *userAction) AdminReviewEntityApplication(userID string, req *types.AdminReviewApplicationReq) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin reviewed entity application",
+		// admin - [entity] - [decision] - [comment]
+		Detail:   admin.Email + " - " + req.Entity.Name + " - " + req.Decision + " - " + req.Comment,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
 // GET /admin/log
 
 func (u *userAction) Search(req *types.AdminSearchLogReq) (*types.ESSearchUserActionResult, error) {
@@ -0,0 +1,268 @@
+package logic
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/secret"
+	"github.com/segmentio/ksuid"
+	"github.com/spf13/viper"
+)
+
+type voucher struct{}
+
+var Voucher = &voucher{}
+
+// ReservedAmount is the total of the account's vouchers that can still be redeemed.
+func (v *voucher) ReservedAmount(accountNumber string) (float64, error) {
+	reserved, err := pg.Voucher.ReservedAmount(accountNumber)
+	if err != nil {
+		return 0, err
+	}
+	return reserved, nil
+}
+
+// POST /vouchers
+
+func (v *voucher) CheckIssue(req *types.IssueVoucherReq) error {
+	account, err := pg.Account.FindByAccountNumber(req.PayerEntity.AccountNumber)
+	if err != nil {
+		return err
+	}
+	if account.IsSendingFrozen() {
+		return errors.New("Sender's account has been frozen and cannot send transfers. Reason: " + account.FreezeReason)
+	}
+	return Transfer.CheckSendingBalance(account.AccountNumber, req.Amount)
+}
+
+// Issue signs a new voucher with the payer's key and reserves the amount against the payer's balance.
+func (v *voucher) Issue(req *types.IssueVoucherReq) (*types.Voucher, *types.VoucherKey, error) {
+	key, err := v.findOrCreateKey(req.PayerEntity.AccountNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err := v.openPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	voucher := &types.Voucher{
+		Nonce:              ksuid.New().String(),
+		PayerAccountNumber: req.PayerEntity.AccountNumber,
+		PayerEntityName:    req.PayerEntity.Name,
+		Amount:             req.Amount,
+		Description:        req.Description,
+		ExpiresAt:          req.ExpiresAt,
+		Status:             constant.Voucher.Issued,
+	}
+	payload, err := json.Marshal(voucher.Payload())
+	if err != nil {
+		return nil, nil, err
+	}
+	signature := ed25519.Sign(ed25519.PrivateKey(privateKey), payload)
+	voucher.Signature = base64.RawURLEncoding.EncodeToString(signature)
+
+	created, err := pg.Voucher.Create(voucher)
+	if err != nil {
+		return nil, nil, err
+	}
+	return created, key, nil
+}
+
+func (v *voucher) findOrCreateKey(accountNumber string) (*types.VoucherKey, error) {
+	key, err := pg.VoucherKey.FindByAccountNumber(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		return key, nil
+	}
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := v.sealPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return pg.VoucherKey.Create(&types.VoucherKey{
+		AccountNumber: accountNumber,
+		PublicKey:     hex.EncodeToString(publicKey),
+		PrivateKey:    sealed,
+	})
+}
+
+// The private keys are stored encrypted with voucher.key_encryption_key.
+func (v *voucher) sealPrivateKey(privateKey ed25519.PrivateKey) (string, error) {
+	encryptionKey, err := secret.ParseKey(viper.GetString("voucher.key_encryption_key"))
+	if err != nil {
+		return "", err
+	}
+	return secret.Seal(encryptionKey, privateKey)
+}
+
+func (v *voucher) openPrivateKey(key *types.VoucherKey) (ed25519.PrivateKey, error) {
+	encryptionKey, err := secret.ParseKey(viper.GetString("voucher.key_encryption_key"))
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := secret.Open(encryptionKey, key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, errors.New("The voucher signing key is invalid.")
+	}
+	return ed25519.PrivateKey(privateKey), nil
+}
+
+// EncryptPrivateKeys encrypts the private keys stored before the keys were encrypted and returns how many it encrypted.
+// An unencrypted key is the hex encoded ed25519 private key, the encrypted keys are longer.
+func (v *voucher) EncryptPrivateKeys() (int, error) {
+	err := pg.VoucherKey.WidenPrivateKey()
+	if err != nil {
+		return 0, err
+	}
+	keys, err := pg.VoucherKey.FindAll()
+	if err != nil {
+		return 0, err
+	}
+	encrypted := 0
+	for _, key := range keys {
+		if len(key.PrivateKey) != hex.EncodedLen(ed25519.PrivateKeySize) {
+			continue
+		}
+		privateKey, err := hex.DecodeString(key.PrivateKey)
+		if err != nil {
+			return encrypted, err
+		}
+		sealed, err := v.sealPrivateKey(privateKey)
+		if err != nil {
+			return encrypted, err
+		}
+		err = pg.VoucherKey.UpdatePrivateKey(key, sealed)
+		if err != nil {
+			return encrypted, err
+		}
+		encrypted++
+	}
+	return encrypted, nil
+}
+
+// PublicKey returns the hex encoded key the account's vouchers can be verified with offline.
+func (v *voucher) PublicKey(accountNumber string) (string, error) {
+	key, err := pg.VoucherKey.FindByAccountNumber(accountNumber)
+	if err != nil {
+		return "", err
+	}
+	if key == nil {
+		return "", nil
+	}
+	return key.PublicKey, nil
+}
+
+// GET /vouchers
+
+func (v *voucher) FindByAccountNumber(accountNumber string) ([]*types.Voucher, error) {
+	vouchers, err := pg.Voucher.FindByAccountNumber(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	return vouchers, nil
+}
+
+// DELETE /vouchers/{voucherID}
+
+func (v *voucher) FindByID(id string) (*types.Voucher, error) {
+	voucher, err := pg.Voucher.FindByNonce(id)
+	if err != nil {
+		return nil, err
+	}
+	return voucher, nil
+}
+
+// Cancel releases the reservation of an unredeemed voucher.
+func (v *voucher) Cancel(id string) (*types.Voucher, error) {
+	cancelled, err := pg.Voucher.Cancel(id)
+	if err != nil {
+		return nil, err
+	}
+	return cancelled, nil
+}
+
+// POST /admin/accounts/{accountNumber}/closure
+
+// CancelByAccountNumber releases the reservations of all the account's unredeemed vouchers.
+func (v *voucher) CancelByAccountNumber(accountNumber string) error {
+	return pg.Voucher.CancelByAccountNumber(accountNumber)
+}
+
+// POST /vouchers/redeem
+
+// Verify checks the signature of the voucher code and returns the voucher it was issued as.
+func (v *voucher) Verify(code string) (*types.Voucher, error) {
+	payload, message, signature, err := types.ParseVoucherCode(code)
+	if err != nil {
+		return nil, err
+	}
+	invalid := errors.New("The voucher signature is invalid.")
+
+	key, err := pg.VoucherKey.FindByAccountNumber(payload.Payer)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, invalid
+	}
+	publicKey, err := hex.DecodeString(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	// The signature is checked over the payload bytes of the code, not over the parsed payload.
+	if !ed25519.Verify(ed25519.PublicKey(publicKey), message, signature) {
+		return nil, invalid
+	}
+
+	voucher, err := pg.Voucher.FindByNonce(payload.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	// The signed payload has to match the voucher on record.
+	if voucher.PayerAccountNumber != payload.Payer || voucher.Amount != payload.Amount || voucher.ExpiresAt.Unix() != payload.ExpiresAt {
+		return nil, invalid
+	}
+	return voucher, nil
+}
+
+func (v *voucher) CheckRedeem(req *types.RedeemVoucherReq) error {
+	err := Transfer.CheckFreeze(req.Voucher.PayerAccountNumber, req.PayeeEntity.AccountNumber)
+	if err != nil {
+		return err
+	}
+	// The payer's side was settled when the voucher was reserved.
+	return Transfer.CheckReceivingBalance(req.PayeeEntity.AccountNumber, req.Voucher.Amount)
+}
+
+func (v *voucher) Redeem(req *types.RedeemVoucherReq) (*types.Journal, error) {
+	journal, err := pg.Voucher.Redeem(req.Voucher, req.PayeeEntity)
+	if err != nil {
+		return nil, err
+	}
+	err = es.Journal.Create(journal)
+	if err != nil {
+		return nil, err
+	}
+	err = Transfer.updateESEntityBalances(journal)
+	if err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"strconv"
 	"time"
 
 	"github.com/ic3network/mccs-alpha-api/global/constant"
@@ -41,9 +43,10 @@ func (es *entity) Create(id primitive.ObjectID, entity *types.Entity) error {
 		Wants:      entity.Wants,
 		Categories: entity.Categories,
 		// Address
-		City:    entity.City,
-		Region:  entity.Region,
-		Country: entity.Country,
+		City:     entity.City,
+		Region:   entity.Region,
+		Country:  entity.Country,
+		Location: types.NewGeoPoint(entity.Latitude, entity.Longitude),
 		// Account
 		AccountNumber: entity.AccountNumber,
 		Balance:       &balance,
@@ -68,9 +71,10 @@ func (es *entity) Update(req *types.UpdateUserEntityReq) error {
 		Name:  req.Name,
 		Email: req.Email,
 		// Address
-		City:    req.City,
-		Region:  req.Region,
-		Country: req.Country,
+		City:     req.City,
+		Region:   req.Region,
+		Country:  req.Country,
+		Location: types.NewGeoPoint(req.Latitude, req.Longitude),
 	}
 
 	script := es.getUpateTagScript(req.AddedOffers, req.AddedWants, req.RemovedOffers, req.RemovedWants)
@@ -93,6 +97,9 @@ func (es *entity) Update(req *types.UpdateUserEntityReq) error {
 	if err != nil {
 		return err
 	}
+	if req.ClearLocation {
+		return es.clearLocation(req.OriginEntity.ID.Hex())
+	}
 	return nil
 }
 
@@ -104,9 +111,10 @@ func (es *entity) AdminUpdate(req *types.AdminUpdateEntityReq) error {
 		Email:  req.Email,
 		Status: req.Status,
 		// Address
-		City:    req.City,
-		Region:  req.Region,
-		Country: req.Country,
+		City:     req.City,
+		Region:   req.Region,
+		Country:  req.Country,
+		Location: types.NewGeoPoint(req.Latitude, req.Longitude),
 		// Account
 		MaxNegBal: req.MaxNegBal,
 		MaxPosBal: req.MaxPosBal,
@@ -132,9 +140,22 @@ func (es *entity) AdminUpdate(req *types.AdminUpdateEntityReq) error {
 	if err != nil {
 		return err
 	}
+	if req.ClearLocation {
+		return es.clearLocation(req.OriginEntity.ID.Hex())
+	}
 	return nil
 }
 
+// clearLocation removes the coordinates of the entity, the partial update of the document cannot remove fields.
+func (es *entity) clearLocation(id string) error {
+	_, err := es.c.Update().
+		Index(es.index).
+		Id(id).
+		Script(elastic.NewScript("ctx._source.remove('location')")).
+		Do(context.Background())
+	return err
+}
+
 // PATCH /user/entities/{entityID}
 // PATCH /admin/entities/{entityID}
 
@@ -192,34 +213,13 @@ func (es *entity) getUpateTagScript(addedOffers []string, addedWants []string, r
 func (es *entity) Search(req *types.SearchEntityReq) (*types.ESSearchEntityResult, error) {
 	var ids []string
 
-	q := elastic.NewBoolQuery()
-
-	if req.FavoritesOnly {
-		idQuery := elastic.NewIdsQuery().Ids(util.ToIDStrings(req.FavoriteEntities)...)
-		q.Must(idQuery)
-	}
-	if req.Category != "" {
-		q.Must(elastic.NewMatchQuery("categories", req.Category))
-	}
-
-	seachByStatus(q, req.Statuses)
-	seachbyNameEmailAndAddress(q, &byNameAndAddress{
-		Name:    req.Name,
-		City:    req.City,
-		Country: req.Country,
-	})
-	seachByTags(q, &byTag{
-		Offers:      req.Offers,
-		Wants:       req.Wants,
-		TaggedSince: req.TaggedSince,
-	})
-
 	from := req.PageSize * (req.Page - 1)
 	res, err := es.c.Search().
 		Index(es.index).
 		From(from).
 		Size(req.PageSize).
-		Query(q).
+		Query(newSearchQuery(req)).
+		SortBy(newSearchSorters(req)...).
 		Do(context.Background())
 	if err != nil {
 		return nil, err
@@ -244,6 +244,60 @@ func (es *entity) Search(req *types.SearchEntityReq) (*types.ESSearchEntityResul
 	}, nil
 }
 
+func newSearchQuery(req *types.SearchEntityReq) *elastic.BoolQuery {
+	q := elastic.NewBoolQuery()
+
+	if req.FavoritesOnly {
+		idQuery := elastic.NewIdsQuery().Ids(util.ToIDStrings(req.FavoriteEntities)...)
+		q.Must(idQuery)
+	}
+	if req.Category != "" {
+		q.Must(elastic.NewMatchQuery("categories", req.Category))
+	}
+
+	seachByStatus(q, req.Statuses)
+	seachbyNameEmailAndAddress(q, &byNameAndAddress{
+		Name:    req.Name,
+		City:    req.City,
+		Country: req.Country,
+	})
+	seachByAccount(q, &byAccount{
+		AccountNumber: req.AccountNumber,
+	})
+	seachByTags(q, &byTag{
+		Offers:      req.Offers,
+		Wants:       req.Wants,
+		TaggedSince: req.TaggedSince,
+	})
+	seachByLocation(q, req.Near, req.Radius)
+	if req.MinRating != nil {
+		q.Filter(elastic.NewRangeQuery("rating").Gte(*req.MinRating))
+	}
+	return q
+}
+
+// newSearchSorters returns the sort of the search, the entities are sorted by relevance when it is empty.
+func newSearchSorters(req *types.SearchEntityReq) []elastic.Sorter {
+	switch req.Sort {
+	case "distance":
+		return []elastic.Sorter{elastic.NewGeoDistanceSort("location").Point(req.Near.Lat, req.Near.Lon).Unit("km").Asc()}
+	case "rating":
+		return []elastic.Sorter{
+			elastic.NewFieldSort("rating").Desc().Missing("_last"),
+			elastic.NewFieldSort("reviewCount").Desc().Missing("_last"),
+		}
+	}
+	return nil
+}
+
+// GET /entities/export
+
+// ScrollSearch reads every entity found by the search and calls fn with the IDs of each page.
+// It scrolls through the results, which are not limited by the max result window of the index like from and size are.
+func (es *entity) ScrollSearch(req *types.SearchEntityReq, pageSize int, fn func(ids []string) error) error {
+	return es.scroll(newSearchQuery(req), newSearchSorters(req), pageSize, fn)
+}
+
 func seachByStatus(q *elastic.BoolQuery, status []string) *elastic.BoolQuery {
 	if len(status) != 0 {
 		qq := elastic.NewBoolQuery()
@@ -315,6 +369,15 @@ func seachByTags(q *elastic.BoolQuery, query *byTag) {
 	}
 }
 
+func seachByLocation(q *elastic.BoolQuery, near *types.GeoPoint, radius *float64) {
+	if near == nil || radius == nil {
+		return
+	}
+	q.Filter(elastic.NewGeoDistanceQuery("location").
+		Point(near.Lat, near.Lon).
+		Distance(strconv.FormatFloat(*radius, 'f', -1, 64) + "km"))
+}
+
 // GET /admin/entities
 
 func seachByAccount(q *elastic.BoolQuery, req *byAccount) {
@@ -343,6 +406,37 @@ type byAccount struct {
 func (es *entity) AdminSearch(req *types.AdminSearchEntityReq) (*types.ESSearchEntityResult, error) {
 	var ids []string
 
+	from := req.PageSize * (req.Page - 1)
+	res, err := es.c.Search().
+		Index(es.index).
+		From(from).
+		Size(req.PageSize).
+		Query(newAdminSearchQuery(req)).
+		Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hit := range res.Hits.Hits {
+		var record types.EntityESRecord
+		err := json.Unmarshal(hit.Source, &record)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, record.ID)
+	}
+
+	numberOfResults := int(res.Hits.TotalHits.Value)
+	totalPages := util.GetNumberOfPages(numberOfResults, req.PageSize)
+
+	return &types.ESSearchEntityResult{
+		IDs:             ids,
+		NumberOfResults: int(numberOfResults),
+		TotalPages:      totalPages,
+	}, nil
+}
+
+func newAdminSearchQuery(req *types.AdminSearchEntityReq) *elastic.BoolQuery {
 	q := elastic.NewBoolQuery()
 
 	if req.Category != "" {
@@ -368,35 +462,46 @@ func (es *entity) AdminSearch(req *types.AdminSearchEntityReq) (*types.ESSearchE
 		MaxPosBal:     req.MaxPosBal,
 		MaxNegBal:     req.MaxNegBal,
 	})
+	return q
+}
 
-	from := req.PageSize * (req.Page - 1)
-	res, err := es.c.Search().
-		Index(es.index).
-		From(from).
-		Size(req.PageSize).
-		Query(q).
-		Do(context.Background())
-	if err != nil {
-		return nil, err
+// GET /admin/entities/export
+
+// AdminScrollSearch reads every entity found by the admin search and calls fn with the IDs of each page.
+func (es *entity) AdminScrollSearch(req *types.AdminSearchEntityReq, pageSize int, fn func(ids []string) error) error {
+	return es.scroll(newAdminSearchQuery(req), nil, pageSize, fn)
+}
+
+func (es *entity) scroll(q elastic.Query, sorters []elastic.Sorter, pageSize int, fn func(ids []string) error) error {
+	scroll := es.c.Scroll(es.index).Query(q).Size(pageSize)
+	if len(sorters) != 0 {
+		scroll.SortBy(sorters...)
 	}
+	defer scroll.Clear(context.Background())
 
-	for _, hit := range res.Hits.Hits {
-		var record types.EntityESRecord
-		err := json.Unmarshal(hit.Source, &record)
+	for {
+		res, err := scroll.Do(context.Background())
+		if err == io.EOF {
+			return nil
+		}
 		if err != nil {
-			return nil, err
+			return err
 		}
-		ids = append(ids, record.ID)
-	}
 
-	numberOfResults := int(res.Hits.TotalHits.Value)
-	totalPages := util.GetNumberOfPages(numberOfResults, req.PageSize)
-
-	return &types.ESSearchEntityResult{
-		IDs:             ids,
-		NumberOfResults: int(numberOfResults),
-		TotalPages:      totalPages,
-	}, nil
+		ids := make([]string, 0, len(res.Hits.Hits))
+		for _, hit := range res.Hits.Hits {
+			var record types.EntityESRecord
+			err := json.Unmarshal(hit.Source, &record)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, record.ID)
+		}
+		err = fn(ids)
+		if err != nil {
+			return err
+		}
+	}
 }
 
 func (es *entity) UpdateAllTagsCreatedAt(id primitive.ObjectID, t time.Time) error {
@@ -557,3 +662,24 @@ func (es *entity) UpdateBalance(accountNumber string, balance float64) error {
 	}
 	return nil
 }
+
+// UpdateRating stores the average rating of the entity's visible reviews so entities can be sorted and filtered by it.
+func (es *entity) UpdateRating(id primitive.ObjectID, rating float64, count int) error {
+	doc := map[string]interface{}{
+		"rating":      rating,
+		"reviewCount": count,
+	}
+	if count == 0 {
+		// Entities without reviews are not rated rather than rated 0.
+		doc["rating"] = nil
+	}
+	_, err := es.c.Update().
+		Index(es.index).
+		Id(id.Hex()).
+		Doc(doc).
+		Do(context.Background())
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ func checkIndex(client *elastic.Client, index string) {
 	}
 
 	if exists {
+		updateMapping(client, index)
 		return
 	}
 
@@ -36,6 +37,35 @@ func checkIndex(client *elastic.Client, index string) {
 	}
 }
 
+// updateMapping adds the fields introduced after the index was created.
+func updateMapping(client *elastic.Client, index string) {
+	mapping, ok := indexMappingUpdates[index]
+	if !ok {
+		return
+	}
+	_, err := client.PutMapping().Index(index).BodyString(mapping).Do(context.Background())
+	if err != nil {
+		panic(err)
+	}
+}
+
+var indexMappingUpdates = map[string]string{
+	"entities": `
+	{
+		"properties": {
+			"location": {
+				"type": "geo_point"
+			},
+			"rating": {
+				"type": "float"
+			},
+			"reviewCount": {
+				"type": "integer"
+			}
+		}
+	}`,
+}
+
 var indexes = []string{"entities", "users", "tags", "journals", "user_actions"}
 
 // Notes:
@@ -156,6 +186,9 @@ var indexMappings = map[string]string{
 						}
 					}
 				},
+				"location": {
+					"type": "geo_point"
+				},
 				"accountNumber": {
 					"type": "keyword"
 				},
@@ -167,6 +200,12 @@ var indexMappings = map[string]string{
 				},
 				"maxPosBal": {
 					"type" : "float"
+				},
+				"rating": {
+					"type" : "float"
+				},
+				"reviewCount": {
+					"type" : "integer"
 				}
 			}
 		}
@@ -267,6 +306,9 @@ var indexMappings = map[string]string{
 				"toAccountNumber": {
 					"type": "keyword"
 				},
+				"type": {
+					"type": "keyword"
+				},
 				"status": {
 					"type": "keyword"
 				},
@@ -31,6 +31,7 @@ func (es *journal) Create(j *types.Journal) error {
 		TransferID:        j.TransferID,
 		FromAccountNumber: j.FromAccountNumber,
 		ToAccountNumber:   j.ToAccountNumber,
+		Type:              j.Type,
 		Status:            j.Status,
 		CreatedAt:         j.CreatedAt,
 	}
@@ -71,6 +72,7 @@ func (es *journal) AdminSearch(req *types.AdminSearchTransferReq) (*types.ESSear
 
 	es.seachByAccountNumber(q, req.AccountNumber)
 	es.seachByStatus(q, req.Status)
+	es.seachByType(q, req.Types)
 	es.seachByTime(q, req.DateFrom, req.DateTo)
 
 	from := req.PageSize * (req.Page - 1)
@@ -122,6 +124,16 @@ func (es *journal) seachByStatus(q *elastic.BoolQuery, status []string) {
 	}
 }
 
+func (es *journal) seachByType(q *elastic.BoolQuery, types []string) {
+	if len(types) != 0 {
+		qq := elastic.NewBoolQuery()
+		for _, t := range types {
+			qq.Should(elastic.NewTermQuery("type", t))
+		}
+		q.Must(qq)
+	}
+}
+
 func (es *journal) seachByTime(q *elastic.BoolQuery, dateFrom time.Time, dateTo time.Time) {
 	if !dateFrom.IsZero() {
 		rangeQ := elastic.NewRangeQuery("createdAt").From(dateFrom)
@@ -0,0 +1,125 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type applicationQuestion struct {
+	c *mongo.Collection
+}
+
+var ApplicationQuestion = &applicationQuestion{}
+
+func (a *applicationQuestion) Register(db *mongo.Database) {
+	a.c = db.Collection("applicationQuestions")
+}
+
+// POST /admin/application-questions
+
+func (a *applicationQuestion) Create(question *types.ApplicationQuestion) (*types.ApplicationQuestion, error) {
+	question.CreatedAt = time.Now()
+	question.UpdatedAt = time.Now()
+	res, err := a.c.InsertOne(context.Background(), question)
+	if err != nil {
+		return nil, err
+	}
+	question.ID = res.InsertedID.(primitive.ObjectID)
+	return question, nil
+}
+
+// GET /application-questions
+// GET /admin/application-questions
+
+func (a *applicationQuestion) FindAll() ([]*types.ApplicationQuestion, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "order", Value: 1}, {Key: "createdAt", Value: 1}})
+	cur, err := a.c.Find(context.Background(), bson.M{}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	questions := []*types.ApplicationQuestion{}
+	for cur.Next(context.Background()) {
+		var elem types.ApplicationQuestion
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+func (a *applicationQuestion) FindByID(id primitive.ObjectID) (*types.ApplicationQuestion, error) {
+	question := types.ApplicationQuestion{}
+	err := a.c.FindOne(context.Background(), bson.M{"_id": id}).Decode(&question)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("Application question not found.")
+		}
+		return nil, err
+	}
+	return &question, nil
+}
+
+// PATCH /admin/application-questions/{questionID}
+
+func (a *applicationQuestion) FindOneAndUpdate(question *types.ApplicationQuestion) (*types.ApplicationQuestion, error) {
+	result := a.c.FindOneAndUpdate(
+		context.Background(),
+		bson.M{"_id": question.ID},
+		bson.M{
+			"$set": bson.M{
+				"text":      question.Text,
+				"required":  question.Required,
+				"order":     question.Order,
+				"updatedAt": time.Now(),
+			},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		if result.Err() == mongo.ErrNoDocuments {
+			return nil, errors.New("Application question not found.")
+		}
+		return nil, result.Err()
+	}
+
+	updated := types.ApplicationQuestion{}
+	err := result.Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DELETE /admin/application-questions/{questionID}
+
+// The answers that were already given keep the text of the deleted question.
+func (a *applicationQuestion) FindOneAndDelete(id primitive.ObjectID) (*types.ApplicationQuestion, error) {
+	result := a.c.FindOneAndDelete(context.Background(), bson.M{"_id": id})
+	if result.Err() != nil {
+		if result.Err() == mongo.ErrNoDocuments {
+			return nil, errors.New("Application question not found.")
+		}
+		return nil, result.Err()
+	}
+
+	question := types.ApplicationQuestion{}
+	err := result.Decode(&question)
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
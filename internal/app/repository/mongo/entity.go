@@ -3,6 +3,9 @@ package mongo
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,6 +26,29 @@ var Entity = &entity{}
 
 func (en *entity) Register(db *mongo.Database) {
 	en.c = db.Collection("entities")
+	en.createIndexes()
+}
+
+// createIndexes makes the handles unique. Entities without a handle are left out of the index.
+func (en *entity) createIndexes() {
+	_, err := en.c.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.M{"handle": 1},
+		Options: options.Index().
+			SetName("handle_unique").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"handle": bson.M{"$gt": ""}}),
+	})
+	if err != nil {
+		log.Fatal("entities: creating the unique index of the handles failed, entities may share a handle: ", err)
+	}
+}
+
+// handleTakenError turns the duplicate key error of the handle index into the error shown to the user.
+func handleTakenError(err error, handle string) error {
+	if handle != "" && isDuplicateKeyError(err) {
+		return errors.New("Handle @" + handle + " is already taken.")
+	}
+	return err
 }
 
 func (e *entity) Create(update *types.Entity) (*types.Entity, error) {
@@ -37,7 +63,7 @@ func (e *entity) Create(update *types.Entity) (*types.Entity, error) {
 		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
 	)
 	if result.Err() != nil {
-		return nil, result.Err()
+		return nil, handleTakenError(result.Err(), update.Handle)
 	}
 
 	created := types.Entity{}
@@ -108,6 +134,22 @@ func (e *entity) FindByAccountNumber(accountNumber string) (*types.Entity, error
 	return &entity, nil
 }
 
+func (e *entity) FindByHandle(handle string) (*types.Entity, error) {
+	entity := types.Entity{}
+	filter := bson.M{
+		"handle":    handle,
+		"deletedAt": bson.M{"$exists": false},
+	}
+	err := e.c.FindOne(context.Background(), filter).Decode(&entity)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("Handle @" + handle + " not found.")
+		}
+		return nil, err
+	}
+	return &entity, nil
+}
+
 func (e *entity) FindByEmail(email string) (*types.Entity, error) {
 	if email == "" {
 		return nil, errors.New("Please specify an email address.")
@@ -170,13 +212,26 @@ func (e *entity) FindOneAndUpdate(req *types.UpdateUserEntityReq) (*types.Entity
 	if req.PostalCode != "" {
 		update["postalCode"] = req.PostalCode
 	}
+	if req.Latitude != nil && req.Longitude != nil {
+		update["latitude"] = *req.Latitude
+		update["longitude"] = *req.Longitude
+	}
 	if req.ReceiveDailyMatchNotificationEmail != nil {
 		update["receiveDailyMatchNotificationEmail"] = *req.ReceiveDailyMatchNotificationEmail
 	}
 	if req.ShowTagsMatchedSinceLastLogin != nil {
 		update["showTagsMatchedSinceLastLogin"] = *req.ShowTagsMatchedSinceLastLogin
 	}
+	if req.AutoAccept != nil {
+		update["autoAccept"] = req.AutoAccept
+	}
+	if req.Handle != "" {
+		update["handle"] = req.Handle
+	}
 	updates = append(updates, bson.M{"$set": update})
+	if req.ClearLocation {
+		updates = append(updates, bson.M{"$unset": bson.M{"latitude": "", "longitude": ""}})
+	}
 
 	push := bson.M{}
 	if len(req.AddedOffers) != 0 {
@@ -209,7 +264,7 @@ func (e *entity) FindOneAndUpdate(req *types.UpdateUserEntityReq) (*types.Entity
 
 	_, err := e.c.BulkWrite(context.Background(), writes)
 	if err != nil {
-		return nil, err
+		return nil, handleTakenError(err, req.Handle)
 	}
 
 	entity, err := e.FindByID(req.OriginEntity.ID)
@@ -265,6 +320,10 @@ func (e *entity) AdminFindOneAndUpdate(req *types.AdminUpdateEntityReq) (*types.
 	if req.PostalCode != "" {
 		update["postalCode"] = req.PostalCode
 	}
+	if req.Latitude != nil && req.Longitude != nil {
+		update["latitude"] = *req.Latitude
+		update["longitude"] = *req.Longitude
+	}
 	if req.Categories != nil {
 		update["categories"] = util.FormatTags(*req.Categories)
 	}
@@ -280,6 +339,9 @@ func (e *entity) AdminFindOneAndUpdate(req *types.AdminUpdateEntityReq) (*types.
 	if req.ShowTagsMatchedSinceLastLogin != nil {
 		update["showTagsMatchedSinceLastLogin"] = *req.ShowTagsMatchedSinceLastLogin
 	}
+	if req.Handle != "" {
+		update["handle"] = req.Handle
+	}
 	// TODO
 	// This is a trick to prevent setting nothing for the entity.
 	// If we don't do this then it will throw this error:
@@ -288,6 +350,9 @@ func (e *entity) AdminFindOneAndUpdate(req *types.AdminUpdateEntityReq) (*types.
 		update["name"] = req.OriginEntity.Name
 	}
 	updates = append(updates, bson.M{"$set": update})
+	if req.ClearLocation {
+		updates = append(updates, bson.M{"$unset": bson.M{"latitude": "", "longitude": ""}})
+	}
 
 	push := bson.M{}
 	if len(req.AddedOffers) != 0 {
@@ -320,7 +385,7 @@ func (e *entity) AdminFindOneAndUpdate(req *types.AdminUpdateEntityReq) (*types.
 
 	_, err := e.c.BulkWrite(context.Background(), writes)
 	if err != nil {
-		return nil, err
+		return nil, handleTakenError(err, req.Handle)
 	}
 
 	err = User.AssociateEntity(req.AddedUsers, req.OriginEntity.ID)
@@ -608,17 +673,19 @@ func (e *entity) updateWants(old string, new string) error {
 
 // PATCH /admin/users/{userID}
 
+// AssociateUser adds the user to the entities as an owner, unless the user already has a role in them.
+// The role is written before the user is added, a role without the membership doesn't grant anything.
 func (en *entity) AssociateUser(entityIDs []primitive.ObjectID, UserID primitive.ObjectID) error {
 	filter := bson.M{"_id": bson.M{"$in": entityIDs}}
-	updates := []bson.M{
-		bson.M{"$addToSet": bson.M{"users": UserID}},
-		bson.M{"$set": bson.M{"updatedAt": time.Now()}},
-	}
+	withoutRole := bson.M{"_id": bson.M{"$in": entityIDs}, "roles.userID": bson.M{"$ne": UserID}}
+	ownerRole := bson.M{"userID": UserID, "role": constant.EntityRole.Owner}
 
-	var writes []mongo.WriteModel
-	for _, update := range updates {
-		model := mongo.NewUpdateManyModel().SetFilter(filter).SetUpdate(update)
-		writes = append(writes, model)
+	writes := []mongo.WriteModel{
+		mongo.NewUpdateManyModel().SetFilter(withoutRole).SetUpdate(bson.M{"$push": bson.M{"roles": ownerRole}}),
+		mongo.NewUpdateManyModel().SetFilter(filter).SetUpdate(bson.M{
+			"$addToSet": bson.M{"users": UserID},
+			"$set":      bson.M{"updatedAt": time.Now()},
+		}),
 	}
 
 	_, err := en.c.BulkWrite(context.Background(), writes)
@@ -634,6 +701,7 @@ func (e *entity) removeAssociatedUser(entityIDs []primitive.ObjectID, userID pri
 	filter := bson.M{"_id": bson.M{"$in": entityIDs}}
 	updates := []bson.M{
 		bson.M{"$pull": bson.M{"users": userID}},
+		bson.M{"$pull": bson.M{"roles": bson.M{"userID": userID}}},
 		bson.M{"$set": bson.M{"updatedAt": time.Now()}},
 	}
 
@@ -650,6 +718,279 @@ func (e *entity) removeAssociatedUser(entityIDs []primitive.ObjectID, userID pri
 	return nil
 }
 
+// PATCH /user/entities/{entityID}/members/{userID}
+
+// SetRole replaces the role of the user in the entity.
+// The role is changed in place by a single update, so the member never goes without a role.
+func (e *entity) SetRole(entityID primitive.ObjectID, userID primitive.ObjectID, role string) error {
+	filter := bson.M{"_id": entityID, "roles.userID": userID}
+	update := bson.M{"$set": bson.M{"roles.$.role": role, "updatedAt": time.Now()}}
+	res, err := e.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("The member doesn't have a role in this entity.")
+	}
+	return nil
+}
+
+// AddMissingOwnerRoles gives the owner role to the members who were added before entities had roles.
+func (e *entity) AddMissingOwnerRoles() (int, error) {
+	filter := bson.M{"users.0": bson.M{"$exists": true}}
+	cur, err := e.c.Find(context.Background(), filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cur.Close(context.Background())
+
+	updated := 0
+	for cur.Next(context.Background()) {
+		var entity types.Entity
+		err := cur.Decode(&entity)
+		if err != nil {
+			return updated, err
+		}
+		missing := []bson.M{}
+		for _, userID := range entity.Users {
+			if !entity.HasRoleEntry(userID) {
+				missing = append(missing, bson.M{"userID": userID, "role": constant.EntityRole.Owner})
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		_, err = e.c.UpdateOne(context.Background(), bson.M{"_id": entity.ID}, bson.M{
+			"$push": bson.M{"roles": bson.M{"$each": missing}},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		})
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, cur.Err()
+}
+
+// POST /invitations/{token}
+
+// AddMember adds the user to the entity with the role.
+// The user and their role are written by the same update so the user is never a member without a role.
+func (e *entity) AddMember(entityID primitive.ObjectID, userID primitive.ObjectID, role string) error {
+	filter := bson.M{"_id": entityID}
+	updates := []bson.M{
+		bson.M{"$pull": bson.M{"roles": bson.M{"userID": userID}}},
+		bson.M{
+			"$addToSet": bson.M{"users": userID},
+			"$push":     bson.M{"roles": bson.M{"userID": userID, "role": role}},
+			"$set":      bson.M{"updatedAt": time.Now()},
+		},
+	}
+
+	var writes []mongo.WriteModel
+	for _, update := range updates {
+		model := mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update)
+		writes = append(writes, model)
+	}
+
+	_, err := e.c.BulkWrite(context.Background(), writes)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DELETE /user/entities/{entityID}/members/{userID}
+
+// RemoveUser removes the user and their role from the entity.
+func (e *entity) RemoveUser(entityID primitive.ObjectID, userID primitive.ObjectID) error {
+	filter := bson.M{"_id": entityID}
+	update := bson.M{
+		"$pull": bson.M{"users": userID, "roles": bson.M{"userID": userID}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	_, err := e.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// PUT /user/entities/{entityID}/logo
+// DELETE /user/entities/{entityID}/logo
+
+// SetLogo replaces the logo of the entity. A nil image removes it.
+func (e *entity) SetLogo(entityID primitive.ObjectID, image *types.EntityImage) error {
+	filter := bson.M{"_id": entityID}
+	update := bson.M{"$set": bson.M{"logo": image, "updatedAt": time.Now()}}
+	if image == nil {
+		update = bson.M{"$unset": bson.M{"logo": ""}, "$set": bson.M{"updatedAt": time.Now()}}
+	}
+	_, err := e.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// POST /user/entities/{entityID}/gallery
+
+// AddGalleryImage adds the image unless the gallery already has the maximum number of images.
+func (e *entity) AddGalleryImage(entityID primitive.ObjectID, image *types.EntityImage, max int) error {
+	filter := bson.M{
+		"_id":                            entityID,
+		"gallery." + strconv.Itoa(max-1): bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$push": bson.M{"gallery": image},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	result, err := e.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("An entity can have up to %d gallery images.", max)
+	}
+	return nil
+}
+
+// DELETE /user/entities/{entityID}/gallery/{imageID}
+
+func (e *entity) RemoveGalleryImage(entityID primitive.ObjectID, imageID string) error {
+	filter := bson.M{"_id": entityID}
+	update := bson.M{
+		"$pull": bson.M{"gallery": bson.M{"id": imageID}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	result, err := e.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("Image not found.")
+	}
+	return nil
+}
+
+// UpdateRating stores the average rating of the entity's visible reviews.
+func (e *entity) UpdateRating(entityID primitive.ObjectID, rating float64, count int) error {
+	filter := bson.M{"_id": entityID}
+	update := bson.M{"$set": bson.M{"rating": rating, "reviewCount": count, "updatedAt": time.Now()}}
+	if count == 0 {
+		update = bson.M{"$unset": bson.M{"rating": "", "reviewCount": ""}, "$set": bson.M{"updatedAt": time.Now()}}
+	}
+	_, err := e.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GET /feeds/members
+
+// FindNewMembers finds the latest entities accepted as trading members.
+func (e *entity) FindNewMembers(req *types.FeedReq, limit int) ([]*types.Entity, error) {
+	filter := bson.M{
+		"status":          constant.Trading.Accepted,
+		"memberStartedAt": bson.M{"$exists": true},
+		"deletedAt":       bson.M{"$exists": false},
+	}
+	if req.Category != "" {
+		filter["categories"] = req.Category
+	}
+	if len(req.Tags) != 0 {
+		filter["$or"] = bson.A{
+			bson.M{"offers.name": bson.M{"$in": req.Tags}},
+			bson.M{"wants.name": bson.M{"$in": req.Tags}},
+		}
+	}
+	findOptions := options.Find()
+	findOptions.SetSort(bson.M{"memberStartedAt": -1})
+	findOptions.SetLimit(int64(limit))
+
+	cur, err := e.c.Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []*types.Entity
+	for cur.Next(context.TODO()) {
+		var elem types.Entity
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.TODO())
+
+	return entities, nil
+}
+
+// GET /feeds/tags
+
+// FindAddedTags finds the latest offers or wants ("offers" or "wants" field) with one of the names added since the time.
+// Only the tags of the accepted entities are listed, like in the directory.
+func (e *entity) FindAddedTags(field string, names []string, since time.Time, category string, limit int) ([]*types.EntityTagAddition, error) {
+	match := bson.M{
+		"status": bson.M{"$in": bson.A{
+			constant.Entity.Accepted,
+			constant.Trading.Pending,
+			constant.Trading.Accepted,
+			constant.Trading.Rejected,
+		}},
+		field + ".name": bson.M{"$in": names},
+		"deletedAt":     bson.M{"$exists": false},
+	}
+	if category != "" {
+		match["categories"] = category
+	}
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$unwind": "$" + field},
+		{
+			"$match": bson.M{
+				field + ".name":      bson.M{"$in": names},
+				field + ".createdAt": bson.M{"$gte": since},
+			},
+		},
+		{"$sort": bson.M{field + ".createdAt": -1}},
+		{"$limit": limit},
+		{
+			"$project": bson.M{
+				"name":        1,
+				"description": 1,
+				"categories":  1,
+				"tag":         "$" + field,
+			},
+		},
+	}
+	cur, err := e.c.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*types.EntityTagAddition
+	for cur.Next(context.TODO()) {
+		var elem types.EntityTagAddition
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.TODO())
+
+	return results, nil
+}
+
 // daily_email_schedule
 
 func (e *entity) FindByDailyNotification() ([]*types.Entity, error) {
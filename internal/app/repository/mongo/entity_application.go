@@ -0,0 +1,218 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type entityApplication struct {
+	c *mongo.Collection
+}
+
+var EntityApplication = &entityApplication{}
+
+func (e *entityApplication) Register(db *mongo.Database) {
+	e.c = db.Collection("entityApplications")
+}
+
+// GET /user/entities/{entityID}/application
+
+// FindOrCreate returns the application of the entity and starts a draft if it doesn't have one yet.
+func (e *entityApplication) FindOrCreate(entity *types.Entity) (*types.EntityApplication, error) {
+	result := e.c.FindOneAndUpdate(
+		context.Background(),
+		bson.M{"entityID": entity.ID},
+		bson.M{"$setOnInsert": bson.M{
+			"entityID":   entity.ID,
+			"entityName": entity.Name,
+			"status":     constant.EntityApplication.Draft,
+			"createdAt":  time.Now(),
+			"updatedAt":  time.Now(),
+		}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	application := types.EntityApplication{}
+	err := result.Decode(&application)
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// GET /admin/entities/{entityID}/application
+
+func (e *entityApplication) FindByEntityID(entityID primitive.ObjectID) (*types.EntityApplication, error) {
+	application := types.EntityApplication{}
+	err := e.c.FindOne(context.Background(), bson.M{"entityID": entityID}).Decode(&application)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("The entity has not started an application.")
+		}
+		return nil, err
+	}
+	return &application, nil
+}
+
+// PUT /user/entities/{entityID}/application/answers
+
+// The updates below only apply while the application is in one of the given statuses
+// so an application cannot change while an admin is reviewing it.
+
+func (e *entityApplication) SetAnswers(entityID primitive.ObjectID, answers []*types.ApplicationAnswer, statuses []string) (*types.EntityApplication, error) {
+	filter := bson.M{
+		"entityID": entityID,
+		"status":   bson.M{"$in": statuses},
+	}
+	update := bson.M{"$set": bson.M{
+		"answers":   answers,
+		"updatedAt": time.Now(),
+	}}
+	return e.findOneAndUpdate(filter, update, errors.New("The application cannot be changed in its current status."))
+}
+
+// POST /user/entities/{entityID}/application/documents
+
+func (e *entityApplication) AddDocument(entityID primitive.ObjectID, document *types.ApplicationDocument, max int, statuses []string) (*types.EntityApplication, error) {
+	filter := bson.M{
+		"entityID":                         entityID,
+		"status":                           bson.M{"$in": statuses},
+		"documents." + strconv.Itoa(max-1): bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$push": bson.M{"documents": document},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	return e.findOneAndUpdate(filter, update, fmt.Errorf("An application can have up to %d documents and cannot be changed while it is being reviewed.", max))
+}
+
+// DELETE /user/entities/{entityID}/application/documents/{documentID}
+
+func (e *entityApplication) RemoveDocument(entityID primitive.ObjectID, documentID string, statuses []string) (*types.EntityApplication, error) {
+	filter := bson.M{
+		"entityID": entityID,
+		"status":   bson.M{"$in": statuses},
+	}
+	update := bson.M{
+		"$pull": bson.M{"documents": bson.M{"id": documentID}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	return e.findOneAndUpdate(filter, update, errors.New("The application cannot be changed in its current status."))
+}
+
+// POST /user/entities/{entityID}/application/submission
+
+func (e *entityApplication) Submit(entityID primitive.ObjectID, comment *types.ApplicationComment, statuses []string) (*types.EntityApplication, error) {
+	filter := bson.M{
+		"entityID": entityID,
+		"status":   bson.M{"$in": statuses},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":      constant.EntityApplication.Submitted,
+		"submittedAt": time.Now(),
+		"updatedAt":   time.Now(),
+	}}
+	if comment != nil {
+		update["$push"] = bson.M{"comments": comment}
+	}
+	return e.findOneAndUpdate(filter, update, errors.New("The application has already been submitted."))
+}
+
+// POST /admin/entities/{entityID}/application/review
+
+func (e *entityApplication) Review(entityID primitive.ObjectID, status string, comment *types.ApplicationComment) (*types.EntityApplication, error) {
+	filter := bson.M{
+		"entityID": entityID,
+		"status":   constant.EntityApplication.Submitted,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     status,
+			"reviewedAt": time.Now(),
+			"reviewedBy": comment.AuthorID,
+			"updatedAt":  time.Now(),
+		},
+		"$push": bson.M{"comments": comment},
+	}
+	return e.findOneAndUpdate(filter, update, errors.New("Only submitted applications can be reviewed."))
+}
+
+// GET /admin/applications
+
+// Search returns the applications that have waited the longest first.
+func (e *entityApplication) Search(req *types.AdminSearchEntityApplicationReq) (*types.SearchEntityApplicationResult, error) {
+	filter := bson.M{}
+	if req.Status != "" {
+		filter["status"] = req.Status
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "submittedAt", Value: 1}, {Key: "createdAt", Value: 1}})
+	findOptions.SetSkip(int64(req.PageSize * (req.Page - 1)))
+	findOptions.SetLimit(int64(req.PageSize))
+
+	cur, err := e.c.Find(context.Background(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	applications := []*types.EntityApplication{}
+	for cur.Next(context.Background()) {
+		var elem types.EntityApplication
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	totalCount, err := e.c.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SearchEntityApplicationResult{
+		Applications:    applications,
+		NumberOfResults: int(totalCount),
+		TotalPages:      util.GetNumberOfPages(int(totalCount), req.PageSize),
+	}, nil
+}
+
+// findOneAndUpdate returns the updated application or notFound when no application matches the filter.
+func (e *entityApplication) findOneAndUpdate(filter bson.M, update bson.M, notFound error) (*types.EntityApplication, error) {
+	result := e.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		if result.Err() == mongo.ErrNoDocuments {
+			return nil, notFound
+		}
+		return nil, result.Err()
+	}
+	application := types.EntityApplication{}
+	err := result.Decode(&application)
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
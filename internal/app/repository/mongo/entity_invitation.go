@@ -0,0 +1,108 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type entityInvitation struct {
+	c *mongo.Collection
+}
+
+var EntityInvitation = &entityInvitation{}
+
+func (e *entityInvitation) Register(db *mongo.Database) {
+	e.c = db.Collection("entityInvitations")
+}
+
+// POST /user/entities/{entityID}/invitations
+
+func (e *entityInvitation) Create(invitation *types.EntityInvitation) (*types.EntityInvitation, error) {
+	invitation.CreatedAt = time.Now()
+	res, err := e.c.InsertOne(context.Background(), invitation)
+	if err != nil {
+		return nil, err
+	}
+	invitation.ID = res.InsertedID.(primitive.ObjectID)
+	return invitation, nil
+}
+
+// POST /invitations/{token}
+
+func (e *entityInvitation) FindByToken(token string) (*types.EntityInvitation, error) {
+	if token == "" {
+		return nil, errors.New("Invalid token.")
+	}
+	invitation := types.EntityInvitation{}
+	err := e.c.FindOne(context.Background(), bson.M{"token": token}).Decode(&invitation)
+	if err != nil {
+		return nil, errors.New("Invalid token.")
+	}
+	return &invitation, nil
+}
+
+// GET /user/entities/{entityID}/invitations
+
+// FindPending returns the invitations of the entity that were not accepted and have not expired.
+func (e *entityInvitation) FindPending(entityID primitive.ObjectID) ([]*types.EntityInvitation, error) {
+	filter := bson.M{
+		"entityID":   entityID,
+		"acceptedAt": bson.M{"$exists": false},
+		"expiresAt":  bson.M{"$gt": time.Now()},
+	}
+	cur, err := e.c.Find(context.Background(), filter, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	invitations := []*types.EntityInvitation{}
+	for cur.Next(context.Background()) {
+		var elem types.EntityInvitation
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// POST /invitations/{token}
+
+// SetAccepted marks the invitation as accepted. It fails if the invitation was accepted in the meantime.
+func (e *entityInvitation) SetAccepted(id primitive.ObjectID, userID primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "acceptedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"acceptedBy": userID, "acceptedAt": time.Now()}}
+	res, err := e.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount == 0 {
+		return errors.New("The invitation has already been accepted.")
+	}
+	return nil
+}
+
+// DELETE /user/entities/{entityID}/invitations/{invitationID}
+
+func (e *entityInvitation) Delete(entityID primitive.ObjectID, id primitive.ObjectID) error {
+	res, err := e.c.DeleteOne(context.Background(), bson.M{"_id": id, "entityID": entityID, "acceptedAt": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return errors.New("Invitation not found.")
+	}
+	return nil
+}
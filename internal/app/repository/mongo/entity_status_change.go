@@ -0,0 +1,120 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type entityStatusChange struct {
+	c *mongo.Collection
+}
+
+var EntityStatusChange = &entityStatusChange{}
+
+func (e *entityStatusChange) Register(db *mongo.Database) {
+	e.c = db.Collection("entityStatusChanges")
+}
+
+// PATCH /admin/entities/{entityID}
+
+func (e *entityStatusChange) Create(change *types.EntityStatusChange) (*types.EntityStatusChange, error) {
+	change.CreatedAt = time.Now()
+	res, err := e.c.InsertOne(context.Background(), change)
+	if err != nil {
+		return nil, err
+	}
+	change.ID = res.InsertedID.(primitive.ObjectID)
+	return change, nil
+}
+
+// GET /admin/entities/{entityID}
+
+// FindByEntityID returns the status changes of the entity, newest first.
+func (e *entityStatusChange) FindByEntityID(entityID primitive.ObjectID) ([]*types.EntityStatusChange, error) {
+	cur, err := e.c.Find(context.Background(), bson.M{"entityID": entityID}, options.Find().SetSort(bson.M{"createdAt": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	changes := []*types.EntityStatusChange{}
+	for cur.Next(context.Background()) {
+		var elem types.EntityStatusChange
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GET /admin/entities/status-changes
+
+func (e *entityStatusChange) Search(req *types.AdminSearchEntityStatusChangeReq) (*types.SearchEntityStatusChangeResult, error) {
+	filter := bson.M{}
+	if !req.EntityID.IsZero() {
+		filter["entityID"] = req.EntityID
+	}
+	if req.From != "" {
+		filter["from"] = req.From
+	}
+	if req.To != "" {
+		filter["to"] = req.To
+	}
+	createdAt := bson.M{}
+	if !req.DateFrom.IsZero() {
+		createdAt["$gte"] = req.DateFrom
+	}
+	if !req.DateTo.IsZero() {
+		createdAt["$lte"] = req.DateTo
+	}
+	if len(createdAt) != 0 {
+		filter["createdAt"] = createdAt
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.M{"createdAt": -1})
+	findOptions.SetSkip(int64(req.PageSize * (req.Page - 1)))
+	findOptions.SetLimit(int64(req.PageSize))
+
+	cur, err := e.c.Find(context.Background(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	changes := []*types.EntityStatusChange{}
+	for cur.Next(context.Background()) {
+		var elem types.EntityStatusChange
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	totalCount, err := e.c.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SearchEntityStatusChangeResult{
+		Changes:         changes,
+		NumberOfResults: int(totalCount),
+		TotalPages:      util.GetNumberOfPages(int(totalCount), req.PageSize),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type handleRedirect struct {
+	c *mongo.Collection
+}
+
+var HandleRedirect = &handleRedirect{}
+
+func (h *handleRedirect) Register(db *mongo.Database) {
+	h.c = db.Collection("handleRedirects")
+}
+
+func (h *handleRedirect) Create(redirect *types.HandleRedirect) error {
+	redirect.CreatedAt = time.Now()
+	_, err := h.c.InsertOne(context.Background(), redirect)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindActive returns the redirect of the handle which hasn't expired yet.
+func (h *handleRedirect) FindActive(handle string) (*types.HandleRedirect, error) {
+	redirect := types.HandleRedirect{}
+	filter := bson.M{
+		"handle":    handle,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+	err := h.c.FindOne(context.Background(), filter).Decode(&redirect)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("Handle @" + handle + " not found.")
+		}
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// DeleteByHandle removes the redirects of the handle once it has been claimed again.
+func (h *handleRedirect) DeleteByHandle(handle string) error {
+	_, err := h.c.DeleteMany(context.Background(), bson.M{"handle": handle})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (h *handleRedirect) FindByEntityID(entityID primitive.ObjectID) ([]*types.HandleRedirect, error) {
+	results := []*types.HandleRedirect{}
+	cur, err := h.c.Find(context.TODO(), bson.M{"entityID": entityID})
+	if err != nil {
+		return nil, err
+	}
+	for cur.Next(context.TODO()) {
+		var elem types.HandleRedirect
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.TODO())
+	return results, nil
+}
@@ -30,6 +30,11 @@ func (l *lostPassword) Create(lostPassword *types.LostPassword) error {
 		"tokenUsed": false,
 		"createdAt": time.Now(),
 	}}
+	if lostPassword.ExpiresAt.IsZero() {
+		update["$unset"] = bson.M{"expiresAt": ""}
+	} else {
+		update["$set"].(bson.M)["expiresAt"] = lostPassword.ExpiresAt
+	}
 	_, err := l.c.UpdateOne(
 		context.Background(),
 		filter,
@@ -31,6 +31,12 @@ func registerCollections(db *mongo.Database) {
 	Tag.Register(db)
 	Category.Register(db)
 	LostPassword.Register(db)
+	Payee.Register(db)
+	HandleRedirect.Register(db)
+	EntityInvitation.Register(db)
+	EntityStatusChange.Register(db)
+	ApplicationQuestion.Register(db)
+	EntityApplication.Register(db)
 }
 
 // New returns an initialized JWT instance.
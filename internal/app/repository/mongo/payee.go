@@ -0,0 +1,143 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type payee struct {
+	c *mongo.Collection
+}
+
+var Payee = &payee{}
+
+func (p *payee) Register(db *mongo.Database) {
+	p.c = db.Collection("payees")
+}
+
+// POST /user/entities/{entityID}/payees
+
+func (p *payee) Create(payee *types.Payee) (*types.Payee, error) {
+	payee.CreatedAt = time.Now()
+	res, err := p.c.InsertOne(context.Background(), payee)
+	if err != nil {
+		return nil, err
+	}
+	payee.ID = res.InsertedID.(primitive.ObjectID)
+	return payee, nil
+}
+
+// GET /user/entities/{entityID}/payees
+
+func (p *payee) FindByEntityID(entityID primitive.ObjectID) ([]*types.Payee, error) {
+	results := []*types.Payee{}
+
+	filter := bson.M{
+		"entityID":  entityID,
+		"deletedAt": bson.M{"$exists": false},
+	}
+	// Recently used payees come first.
+	findOptions := options.Find().SetSort(bson.D{{Key: "lastUsedAt", Value: -1}, {Key: "nickname", Value: 1}})
+	cur, err := p.c.Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	for cur.Next(context.TODO()) {
+		var elem types.Payee
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.TODO())
+
+	return results, nil
+}
+
+func (p *payee) FindByID(id primitive.ObjectID) (*types.Payee, error) {
+	payee := types.Payee{}
+	filter := bson.M{
+		"_id":       id,
+		"deletedAt": bson.M{"$exists": false},
+	}
+	err := p.c.FindOne(context.Background(), filter).Decode(&payee)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("Payee not found.")
+		}
+		return nil, err
+	}
+	return &payee, nil
+}
+
+// PATCH /user/entities/{entityID}/payees/{payeeID}
+
+func (p *payee) FindOneAndUpdate(req *types.UpdatePayeeReq) (*types.Payee, error) {
+	update := bson.M{"updatedAt": time.Now()}
+	if req.Nickname != "" {
+		update["nickname"] = req.Nickname
+	}
+	if req.AccountNumber != "" {
+		update["accountNumber"] = req.AccountNumber
+	}
+	if req.DefaultDescription != nil {
+		update["defaultDescription"] = *req.DefaultDescription
+	}
+	if req.DefaultAmount != nil {
+		update["defaultAmount"] = *req.DefaultAmount
+	}
+
+	result := p.c.FindOneAndUpdate(
+		context.Background(),
+		bson.M{"_id": req.OriginPayee.ID, "deletedAt": bson.M{"$exists": false}},
+		bson.M{"$set": update},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	payee := types.Payee{}
+	err := result.Decode(&payee)
+	if err != nil {
+		return nil, err
+	}
+	return &payee, nil
+}
+
+// DELETE /user/entities/{entityID}/payees/{payeeID}
+
+func (p *payee) Delete(id primitive.ObjectID) error {
+	_, err := p.c.UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"deletedAt": time.Now(),
+			"updatedAt": time.Now(),
+		}},
+	)
+	return err
+}
+
+// POST /transfers
+
+func (p *payee) UpdateLastUsedAt(id primitive.ObjectID) error {
+	_, err := p.c.UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"lastUsedAt": time.Now()}},
+	)
+	return err
+}
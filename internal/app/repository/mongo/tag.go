@@ -110,6 +110,42 @@ func (t *tag) FindByName(name string) (*types.Tag, error) {
 	return &tag, nil
 }
 
+// GET /feeds/tags
+
+// FindNamesAddedSince finds the tags added as offers or wants ("offerAddedAt" or "wantAddedAt" field) since the time.
+// When names is not empty, only these tags are returned.
+func (t *tag) FindNamesAddedSince(field string, since time.Time, names []string) ([]string, error) {
+	filter := bson.M{
+		field:       bson.M{"$gte": since},
+		"deletedAt": bson.M{"$exists": false},
+	}
+	if len(names) != 0 {
+		filter["name"] = bson.M{"$in": names}
+	}
+	findOptions := options.Find()
+	findOptions.SetProjection(bson.M{"name": 1})
+	cur, err := t.c.Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []string
+	for cur.Next(context.TODO()) {
+		var elem types.Tag
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, elem.Name)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.TODO())
+
+	return results, nil
+}
+
 func (t *tag) UpdateOffer(name string) (primitive.ObjectID, error) {
 	filter := bson.M{"name": name}
 	update := bson.M{
@@ -356,3 +356,9 @@ func (u *user) removeAssociatedEntity(userIDs []primitive.ObjectID, entityID pri
 	}
 	return nil
 }
+
+// DELETE /user/entities/{entityID}/members/{userID}
+
+func (u *user) RemoveEntity(userID primitive.ObjectID, entityID primitive.ObjectID) error {
+	return u.removeAssociatedEntity([]primitive.ObjectID{userID}, entityID)
+}
@@ -15,7 +15,7 @@ var Account = &account{}
 func (a *account) FindByID(accountID uint) (*types.Account, error) {
 	var result types.Account
 	err := db.Raw(`
-		SELECT id, account_number, balance
+		SELECT id, account_number, balance, frozen_sending, frozen_receiving, freeze_reason, frozen_until
 		FROM accounts
 		WHERE deleted_at IS NULL AND id = ?
 		LIMIT 1
@@ -29,7 +29,7 @@ func (a *account) FindByID(accountID uint) (*types.Account, error) {
 func (a *account) FindByAccountNumber(accountNumber string) (*types.Account, error) {
 	var result types.Account
 	err := db.Raw(`
-		SELECT id, account_number, balance
+		SELECT id, account_number, balance, frozen_sending, frozen_receiving, freeze_reason, frozen_until
 		FROM accounts
 		WHERE deleted_at IS NULL AND account_number = ?
 		LIMIT 1
@@ -58,6 +58,20 @@ func (a *account) generateAccountNumber(db *gorm.DB) string {
 	return accountNumber
 }
 
+// createSystemAccount creates an account that doesn't belong to any entity, e.g. the escrow account.
+func (a *account) createSystemAccount(tx *gorm.DB, accountNumber string) error {
+	if a.ifAccountExisted(tx, accountNumber) {
+		return nil
+	}
+	return tx.Create(&types.Account{AccountNumber: accountNumber, Balance: 0}).Error
+}
+
+// lock locks the row of the account until the transaction ends, so that checks made in the transaction
+// cannot be raced by another transaction of the same account.
+func (a *account) lock(tx *gorm.DB, accountNumber string) error {
+	return tx.Exec(`SELECT id FROM accounts WHERE deleted_at IS NULL AND account_number = ? FOR UPDATE`, accountNumber).Error
+}
+
 func (a *account) Create() (*types.Account, error) {
 	tx := db.Begin()
 
@@ -79,6 +93,20 @@ func (a *account) Create() (*types.Account, error) {
 	return &result, tx.Commit().Error
 }
 
+// PATCH /admin/accounts/{accountNumber}/freeze
+
+func (a *account) UpdateFreeze(req *types.AdminFreezeAccountReq) (*types.Account, error) {
+	err := db.Exec(`
+		UPDATE accounts
+		SET frozen_sending = ?, frozen_receiving = ?, freeze_reason = ?, frozen_until = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND account_number = ?
+	`, req.FrozenSending, req.FrozenReceiving, req.Reason, req.FrozenUntil, time.Now(), req.OriginAccount.AccountNumber).Error
+	if err != nil {
+		return nil, err
+	}
+	return a.FindByAccountNumber(req.OriginAccount.AccountNumber)
+}
+
 // DELETE /admin/entities/{entityID}
 
 func (a *account) Delete(accountNumber string) error {
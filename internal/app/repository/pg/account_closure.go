@@ -0,0 +1,53 @@
+package pg
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+)
+
+type accountClosure struct{}
+
+var AccountClosure = &accountClosure{}
+
+// POST /admin/accounts/{accountNumber}/closure
+
+func (a *accountClosure) Create(closure *types.AccountClosure) (*types.AccountClosure, error) {
+	err := db.Create(closure).Error
+	if err != nil {
+		return nil, err
+	}
+	return closure, nil
+}
+
+func (a *accountClosure) Update(closure *types.AccountClosure) error {
+	return db.Save(closure).Error
+}
+
+// PATCH /admin/transfers/{transferID}
+
+// FindBySettlementTransferID returns the closure settled by the transfer or nil if the transfer isn't a settlement.
+func (a *accountClosure) FindBySettlementTransferID(transferID string) (*types.AccountClosure, error) {
+	var result types.AccountClosure
+	query := db.Where("settlement_transfer_id = ?", transferID).First(&result)
+	if query.RecordNotFound() {
+		return nil, nil
+	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	return &result, nil
+}
+
+// GET /admin/accounts/{accountNumber}/closure
+
+// FindLatest returns the most recent closure of the account or nil if closure has never been requested.
+func (a *accountClosure) FindLatest(accountNumber string) (*types.AccountClosure, error) {
+	var result types.AccountClosure
+	query := db.Where("account_number = ?", accountNumber).Order("created_at DESC").First(&result)
+	if query.RecordNotFound() {
+		return nil, nil
+	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	return &result, nil
+}
@@ -0,0 +1,36 @@
+package pg
+
+import (
+	"errors"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+)
+
+type document struct{}
+
+var Document = &document{}
+
+// GET /transfers/{transferID}/receipt.pdf
+// GET /accounts/{accountNumber}/statement.pdf
+
+func (d *document) Create(doc *types.Document) (*types.Document, error) {
+	err := db.Create(doc).Error
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// GET /admin/documents/{code}
+
+func (d *document) FindByCode(code string) (*types.Document, error) {
+	var result types.Document
+	query := db.Where("code = ?", code).First(&result)
+	if query.RecordNotFound() {
+		return nil, errors.New("Document not found.")
+	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	return &result, nil
+}
@@ -0,0 +1,75 @@
+package pg
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/jinzhu/gorm"
+)
+
+type entityImport struct{}
+
+var EntityImport = &entityImport{}
+
+// POST /admin/entities/imports
+
+func (e *entityImport) Create(record *types.EntityImport) (*types.EntityImport, error) {
+	err := db.Create(record).Error
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// POST /admin/entities/imports/{importID}/execution
+
+// Claim moves a validated import to executing, so that only one request can execute it.
+func (e *entityImport) Claim(importID string) error {
+	query := db.Exec(`
+		UPDATE entity_imports
+		SET status = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND import_id = ? AND status = ?
+	`, constant.EntityImport.Executing, time.Now(), importID, constant.EntityImport.Validated)
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return errors.New("This import has already been executed.")
+	}
+	return nil
+}
+
+func (e *entityImport) Update(record *types.EntityImport) error {
+	tx := db.Begin()
+	err := tx.Save(record).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, row := range record.Rows {
+		err := tx.Save(row).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+// GET /admin/entities/imports/{importID}
+
+func (e *entityImport) FindByImportID(importID string) (*types.EntityImport, error) {
+	var result types.EntityImport
+	query := db.Preload("Rows", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line")
+	}).Where("import_id = ?", importID).First(&result)
+	if query.RecordNotFound() {
+		return nil, errors.New("Import not found.")
+	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	return &result, nil
+}
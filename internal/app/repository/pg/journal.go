@@ -1,6 +1,8 @@
 package pg
 
 import (
+	"errors"
+	"math"
 	"time"
 
 	"github.com/ic3network/mccs-alpha-api/global/constant"
@@ -114,12 +116,12 @@ func (t *journal) FindByID(transferID string) (*types.Journal, error) {
 
 // PATCH /transfers
 
-func (t *journal) Cancel(transferID string, reason string) (*types.Journal, error) {
+func (t *journal) Cancel(transferID string, reason string, cancelledBy string) (*types.Journal, error) {
 	err := db.Exec(`
 		UPDATE journals
-		SET status = ?, cancellation_reason = ?, updated_at = ?
+		SET status = ?, cancellation_reason = ?, cancelled_by = ?, cancelled_at = ?, updated_at = ?
 		WHERE deleted_at IS NULL AND transfer_id = ?
-	`, constant.Transfer.Cancelled, reason, time.Now(), transferID).Error
+	`, constant.Transfer.Cancelled, reason, cancelledBy, time.Now(), time.Now(), transferID).Error
 	if err != nil {
 		return nil, err
 	}
@@ -139,8 +141,17 @@ func (t *journal) Cancel(transferID string, reason string) (*types.Journal, erro
 
 // PATCH /transfers
 
-func (t *journal) Accept(j *types.Journal) (*types.Journal, error) {
+func (t *journal) Accept(j *types.Journal, acceptedBy string) (*types.Journal, error) {
 	tx := db.Begin()
+	err := tx.Exec(`
+		UPDATE journals
+		SET accepted_by = ?
+		WHERE deleted_at IS NULL AND transfer_id = ?
+	`, acceptedBy, j.TransferID).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 	journal, err := t.accept(tx, j)
 	if err != nil {
 		tx.Rollback()
@@ -150,81 +161,410 @@ func (t *journal) Accept(j *types.Journal) (*types.Journal, error) {
 }
 
 func (t *journal) accept(tx *gorm.DB, j *types.Journal) (*types.Journal, error) {
+	err := t.post(tx, j, j.FromAccountNumber, j.ToAccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the transaction status.
+	err = tx.Exec(`
+		UPDATE journals
+		SET status = ?, completed_at = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND transfer_id = ?
+		RETURNING *
+	`, constant.Transfer.Completed, time.Now(), time.Now(), j.TransferID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return t.findByID(tx, j.TransferID)
+}
+
+// post creates the postings of the journal and moves the amount between the accounts.
+func (t *journal) post(tx *gorm.DB, j *types.Journal, from string, to string) error {
 	// Create postings.
 	err := tx.Create(&types.Posting{
-		AccountNumber: j.FromAccountNumber,
+		AccountNumber: from,
 		JournalID:     j.ID,
 		Amount:        -j.Amount,
 	}).Error
 	if err != nil {
-		return nil, err
+		return err
 	}
 	err = tx.Create(&types.Posting{
-		AccountNumber: j.ToAccountNumber,
+		AccountNumber: to,
 		JournalID:     j.ID,
 		Amount:        j.Amount,
 	}).Error
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Update accounts' balance.
-	err = tx.Model(&types.Account{}).Where("account_number = ?", j.FromAccountNumber).Update("balance", gorm.Expr("balance - ?", j.Amount)).Error
+	err = tx.Model(&types.Account{}).Where("account_number = ?", from).Update("balance", gorm.Expr("balance - ?", j.Amount)).Error
 	if err != nil {
-		return nil, err
+		return err
+	}
+	err = tx.Model(&types.Account{}).Where("account_number = ?", to).Update("balance", gorm.Expr("balance + ?", j.Amount)).Error
+	if err != nil {
+		return err
 	}
-	err = tx.Model(&types.Account{}).Where("account_number = ?", j.ToAccountNumber).Update("balance", gorm.Expr("balance + ?", j.Amount)).Error
+	return nil
+}
+
+func (t *journal) findByID(tx *gorm.DB, transferID string) (*types.Journal, error) {
+	var updated types.Journal
+	err := tx.Raw(`
+		SELECT *
+		FROM journals
+		WHERE transfer_id=?
+	`, transferID).Scan(&updated).Error
 	if err != nil {
 		return nil, err
 	}
+	return &updated, nil
+}
 
-	// Update the transaction status.
+// POST /transfers
+
+// Escrow moves the amount from the payer to the escrow account.
+// The payee is only credited once the escrow is released.
+func (t *journal) Escrow(req *types.TransferReq, escrowAccountNumber string, releaseAt time.Time) (*types.Journal, error) {
+	tx := db.Begin()
+
+	err := Account.createSystemAccount(tx, escrowAccountNumber)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	journal, err := t.propose(tx, req)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = t.post(tx, journal, journal.FromAccountNumber, escrowAccountNumber)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 	err = tx.Exec(`
 		UPDATE journals
-		SET status = ?, completed_at = ?, updated_at = ?
+		SET status = ?, escrow_release_at = ?, updated_at = ?
 		WHERE deleted_at IS NULL AND transfer_id = ?
-		RETURNING *
-	`, constant.Transfer.Completed, time.Now(), time.Now(), j.TransferID).Error
+	`, constant.Transfer.Escrowed, releaseAt, time.Now(), journal.TransferID).Error
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
-	var updated types.Journal
-	err = tx.Raw(`
-		SELECT *
-		FROM journals
-		WHERE transfer_id=?
-	`, j.TransferID).Scan(&updated).Error
+	escrowed, err := t.findByID(tx, journal.TransferID)
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
+	return escrowed, tx.Commit().Error
+}
 
-	return &updated, nil
+// PATCH /transfers/{transferID}
+// PATCH /admin/transfers/{transferID}
+
+// ReleaseEscrow credits the payee with the escrowed amount.
+func (t *journal) ReleaseEscrow(j *types.Journal, escrowAccountNumber string) (*types.Journal, error) {
+	tx := db.Begin()
+	// Changing the status first makes sure the escrow can only be settled once.
+	err := t.settleEscrow(tx, `
+		UPDATE journals
+		SET status = ?, completed_at = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND transfer_id = ? AND status = ?
+	`, constant.Transfer.Completed, time.Now(), time.Now(), j.TransferID, constant.Transfer.Escrowed)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = t.post(tx, j, escrowAccountNumber, j.ToAccountNumber)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	released, err := t.findByID(tx, j.TransferID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return released, tx.Commit().Error
+}
+
+// RefundEscrow returns the escrowed amount to the payer.
+func (t *journal) RefundEscrow(j *types.Journal, escrowAccountNumber string, reason string) (*types.Journal, error) {
+	tx := db.Begin()
+	err := t.settleEscrow(tx, `
+		UPDATE journals
+		SET status = ?, cancellation_reason = ?, cancelled_at = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND transfer_id = ? AND status = ?
+	`, constant.Transfer.Cancelled, reason, time.Now(), time.Now(), j.TransferID, constant.Transfer.Escrowed)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = t.post(tx, j, escrowAccountNumber, j.FromAccountNumber)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	refunded, err := t.findByID(tx, j.TransferID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return refunded, tx.Commit().Error
+}
+
+func (t *journal) settleEscrow(tx *gorm.DB, sql string, values ...interface{}) error {
+	query := tx.Exec(sql, values...)
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return errors.New("The escrow of this transfer has already been released or refunded.")
+	}
+	return nil
 }
 
 // POST /admin/transfers
 
 func (t *journal) Create(req *types.AdminTransferReq) (*types.Journal, error) {
 	tx := db.Begin()
-	journal, err := t.propose(tx, &types.TransferReq{
+	journal, err := t.propose(tx, newAdminTransferReq(req))
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	updated, err := t.accept(tx, journal)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return updated, tx.Commit().Error
+}
+
+// CreatePendingApproval records the admin transfer without writing any postings.
+func (t *journal) CreatePendingApproval(req *types.AdminTransferReq, requestedBy string) (*types.Journal, error) {
+	tx := db.Begin()
+	created, err := t.createPendingApproval(tx, req, requestedBy)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return created, tx.Commit().Error
+}
+
+func (t *journal) createPendingApproval(tx *gorm.DB, req *types.AdminTransferReq, requestedBy string) (*types.Journal, error) {
+	journal, err := t.propose(tx, newAdminTransferReq(req))
+	if err != nil {
+		return nil, err
+	}
+	return t.setPendingApproval(tx, journal, requestedBy)
+}
+
+func (t *journal) setPendingApproval(tx *gorm.DB, journal *types.Journal, requestedBy string) (*types.Journal, error) {
+	err := tx.Exec(`
+		UPDATE journals
+		SET status = ?, requested_by = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND transfer_id = ?
+	`, constant.Transfer.PendingApproval, requestedBy, time.Now(), journal.TransferID).Error
+	if err != nil {
+		return nil, err
+	}
+	return t.findByID(tx, journal.TransferID)
+}
+
+// POST /admin/transfers/imports/{importID}/execution
+
+// createBatch creates all the admin transfers in the transaction.
+// Transfers flagged in requiresApproval are created pending approval.
+func (t *journal) createBatch(tx *gorm.DB, reqs []*types.AdminTransferReq, requiresApproval []bool, requestedBy string) ([]*types.Journal, error) {
+	journals := make([]*types.Journal, 0, len(reqs))
+	for i, req := range reqs {
+		if requiresApproval[i] {
+			created, err := t.createPendingApproval(tx, req, requestedBy)
+			if err != nil {
+				return nil, err
+			}
+			journals = append(journals, created)
+			continue
+		}
+		journal, err := t.propose(tx, newAdminTransferReq(req))
+		if err != nil {
+			return nil, err
+		}
+		created, err := t.accept(tx, journal)
+		if err != nil {
+			return nil, err
+		}
+		journals = append(journals, created)
+	}
+	return journals, nil
+}
+
+func newAdminTransferReq(req *types.AdminTransferReq) *types.TransferReq {
+	return &types.TransferReq{
 		FromAccountNumber: req.PayerEntity.AccountNumber,
 		FromEntityName:    req.PayerEntity.Name,
 		ToAccountNumber:   req.PayeeEntity.AccountNumber,
 		ToEntityName:      req.PayeeEntity.Name,
 		Amount:            req.Amount,
 		Description:       req.Description,
-		TransferType:      constant.TransferType.AdminTransfer,
-	})
+		TransferType:      req.TransferType,
+	}
+}
+
+// PATCH /admin/transfers/{transferID}
+
+// Approve writes the postings of a transfer pending approval.
+func (t *journal) Approve(j *types.Journal, reviewedBy string) (*types.Journal, error) {
+	tx := db.Begin()
+	// Changing the status first makes sure the transfer can only be reviewed once.
+	err := t.review(tx, `
+		UPDATE journals
+		SET status = ?, reviewed_by = ?, completed_at = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND transfer_id = ? AND status = ?
+	`, constant.Transfer.Completed, reviewedBy, time.Now(), time.Now(), j.TransferID, constant.Transfer.PendingApproval)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
 	}
-	updated, err := t.accept(tx, journal)
+	err = t.post(tx, j, j.FromAccountNumber, j.ToAccountNumber)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
 	}
-	return updated, tx.Commit().Error
+	approved, err := t.findByID(tx, j.TransferID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return approved, tx.Commit().Error
+}
+
+// Reject cancels a transfer pending approval.
+func (t *journal) Reject(j *types.Journal, reviewedBy string, reason string) (*types.Journal, error) {
+	tx := db.Begin()
+	err := t.review(tx, `
+		UPDATE journals
+		SET status = ?, reviewed_by = ?, cancellation_reason = ?, cancelled_at = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND transfer_id = ? AND status = ?
+	`, constant.Transfer.Cancelled, reviewedBy, reason, time.Now(), time.Now(), j.TransferID, constant.Transfer.PendingApproval)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	rejected, err := t.findByID(tx, j.TransferID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return rejected, tx.Commit().Error
+}
+
+func (t *journal) review(tx *gorm.DB, sql string, values ...interface{}) error {
+	query := tx.Exec(sql, values...)
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return errors.New("This transfer has already been approved or rejected.")
+	}
+	return nil
+}
+
+// GET /admin/transfers/approvals
+
+func (t *journal) GetPendingApproval() ([]*types.Journal, error) {
+	var journals []*types.Journal
+	err := db.Raw(`
+		SELECT *
+		FROM journals
+		WHERE deleted_at IS NULL AND status = ? ORDER BY created_at
+	`, constant.Transfer.PendingApproval).Scan(&journals).Error
+	if err != nil {
+		return nil, err
+	}
+	return journals, nil
+}
+
+// POST /admin/accounts/{accountNumber}/opening-balance
+
+// CreateOpeningBalance moves the balance migrated from a previous system between the
+// opening balance account and the entity's account.
+// When requiresApproval is true, the journal is created pending approval without any postings.
+// The entity's account is locked while its existing opening balance is checked, so an account never gets two.
+func (t *journal) CreateOpeningBalance(req *types.AdminOpeningBalanceReq, openingBalanceAccountNumber string, requiresApproval bool, requestedBy string) (*types.Journal, error) {
+	tx := db.Begin()
+
+	err := Account.lock(tx, req.Entity.AccountNumber)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	exists, err := t.hasType(tx, req.Entity.AccountNumber, constant.TransferType.OpeningBalance)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if exists {
+		tx.Rollback()
+		return nil, errors.New("An opening balance has already been recorded for this account.")
+	}
+
+	err = Account.createSystemAccount(tx, openingBalanceAccountNumber)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	transferReq := &types.TransferReq{
+		FromAccountNumber: openingBalanceAccountNumber,
+		FromEntityName:    "Opening balance",
+		ToAccountNumber:   req.Entity.AccountNumber,
+		ToEntityName:      req.Entity.Name,
+		Amount:            math.Abs(req.Amount),
+		Description:       req.Description,
+		TransferType:      constant.TransferType.OpeningBalance,
+	}
+	// A negative opening balance is a debt the entity owes.
+	if req.Amount < 0 {
+		transferReq.FromAccountNumber, transferReq.ToAccountNumber = transferReq.ToAccountNumber, transferReq.FromAccountNumber
+		transferReq.FromEntityName, transferReq.ToEntityName = transferReq.ToEntityName, transferReq.FromEntityName
+	}
+	journal, err := t.propose(tx, transferReq)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	var created *types.Journal
+	if requiresApproval {
+		created, err = t.setPendingApproval(tx, journal, requestedBy)
+	} else {
+		created, err = t.accept(tx, journal)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return created, tx.Commit().Error
+}
+
+// hasType checks whether the account has a journal of the type.
+func (t *journal) hasType(tx *gorm.DB, accountNumber string, transferType string) (bool, error) {
+	var count int
+	err := tx.Raw(`
+		SELECT COUNT(*)
+		FROM journals
+		WHERE deleted_at IS NULL AND (from_account_number = ? OR to_account_number = ?) AND type = ? AND status != ?
+	`, accountNumber, accountNumber, transferType, constant.Transfer.Cancelled).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
 
 // GET /admin/transfers
@@ -261,3 +601,48 @@ func (t *journal) GetPending(accountNumber string) ([]*types.Journal, error) {
 
 	return journals, nil
 }
+
+// GET /admin/accounts/{accountNumber}/closure
+
+func (t *journal) GetEscrowed(accountNumber string) ([]*types.Journal, error) {
+	var journals []*types.Journal
+	err := db.Raw(`
+		SELECT *
+		FROM journals
+		WHERE deleted_at IS NULL AND (from_account_number = ? OR to_account_number = ?) AND status = ? ORDER BY created_at
+	`, accountNumber, accountNumber, constant.Transfer.Escrowed).Scan(&journals).Error
+	if err != nil {
+		return nil, err
+	}
+	return journals, nil
+}
+
+// GetEscrowDue returns the escrowed transfers whose release time has passed.
+func (t *journal) GetEscrowDue(now time.Time) ([]*types.Journal, error) {
+	var journals []*types.Journal
+	err := db.Raw(`
+		SELECT *
+		FROM journals
+		WHERE deleted_at IS NULL AND status = ? AND escrow_release_at <= ? ORDER BY escrow_release_at
+	`, constant.Transfer.Escrowed, now).Scan(&journals).Error
+	if err != nil {
+		return nil, err
+	}
+	return journals, nil
+}
+
+// GET /admin/transfers/export
+
+// FindCompleted returns the transfers completed in [from, to) in chronological order.
+func (t *journal) FindCompleted(from time.Time, to time.Time) ([]*types.Journal, error) {
+	var journals []*types.Journal
+	err := db.Raw(`
+		SELECT *
+		FROM journals
+		WHERE deleted_at IS NULL AND status = ? AND completed_at >= ? AND completed_at < ? ORDER BY completed_at, id
+	`, constant.Transfer.Completed, from, to).Scan(&journals).Error
+	if err != nil {
+		return nil, err
+	}
+	return journals, nil
+}
@@ -68,6 +68,16 @@ func autoMigrate(db *gorm.DB) {
 		&types.BalanceLimit{},
 		&types.Journal{},
 		&types.Posting{},
+		&types.AccountClosure{},
+		&types.VoucherKey{},
+		&types.Voucher{},
+		&types.TransferImport{},
+		&types.TransferImportRow{},
+		&types.TransferComment{},
+		&types.Document{},
+		&types.Review{},
+		&types.EntityImport{},
+		&types.EntityImportRow{},
 	).Error
 	if err != nil {
 		panic(err)
@@ -3,6 +3,7 @@ package pg
 import (
 	"time"
 
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 )
 
@@ -23,3 +24,70 @@ func (t *posting) FindInRange(from time.Time, to time.Time) ([]*types.Posting, e
 	}
 	return result, nil
 }
+
+// GET /transfers/{transferID}
+
+func (t *posting) FindByJournalID(journalID uint) ([]*types.Posting, error) {
+	var result []*types.Posting
+	err := db.Where("journal_id = ?", journalID).Order("created_at, id").Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+const statementEntrySelect = `
+	SELECT J.transfer_id, J.type, J.description,
+		J.from_account_number, J.from_entity_name, J.to_account_number, J.to_entity_name,
+		P.amount, P.created_at
+	FROM postings AS P
+	INNER JOIN journals AS J ON J.id = P.journal_id
+`
+
+// GET /accounts/{accountNumber}/statement.pdf
+
+// FindStatementEntries returns the postings of the account created in [from, to) in chronological order.
+func (t *posting) FindStatementEntries(accountNumber string, from time.Time, to time.Time) ([]*types.StatementEntry, error) {
+	var result []*types.StatementEntry
+	err := db.Raw(statementEntrySelect+`
+	WHERE P.account_number = ? AND P.created_at >= ? AND P.created_at < ? AND P.deleted_at IS NULL
+	ORDER BY P.created_at, P.id
+	`, accountNumber, from, to).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GET /transfers/export
+// GET /admin/transfers/export
+
+// FindCompletedEntries is like FindStatementEntries but skips the postings of transfers that were not completed,
+// e.g. escrowed transfers that were refunded, so every transfer appears at most once.
+func (t *posting) FindCompletedEntries(accountNumber string, from time.Time, to time.Time) ([]*types.StatementEntry, error) {
+	var result []*types.StatementEntry
+	err := db.Raw(statementEntrySelect+`
+	WHERE P.account_number = ? AND P.created_at >= ? AND P.created_at < ? AND P.deleted_at IS NULL AND J.status = ?
+	ORDER BY P.created_at, P.id
+	`, accountNumber, from, to, constant.Transfer.Completed).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BalanceAt returns the balance of the account just before the time.
+func (t *posting) BalanceAt(accountNumber string, at time.Time) (float64, error) {
+	var result struct {
+		Balance float64
+	}
+	err := db.Raw(`
+	SELECT COALESCE(SUM(P.amount), 0) AS balance
+	FROM postings AS P
+	WHERE P.account_number = ? AND P.created_at < ? AND P.deleted_at IS NULL
+	`, accountNumber, at).Scan(&result).Error
+	if err != nil {
+		return 0, err
+	}
+	return result.Balance, nil
+}
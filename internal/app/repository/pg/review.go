@@ -0,0 +1,129 @@
+package pg
+
+import (
+	"errors"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+)
+
+type review struct{}
+
+var Review = &review{}
+
+// POST /transfers/{transferID}/reviews
+
+func (r *review) Create(review *types.Review) (*types.Review, error) {
+	err := db.Create(review).Error
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// Exists includes the reviews deleted by admins so they cannot be written again.
+func (r *review) Exists(transferID string, reviewerAccountNumber string) (bool, error) {
+	var count int
+	err := db.Unscoped().Model(&types.Review{}).
+		Where("transfer_id = ? AND reviewer_account_number = ?", transferID, reviewerAccountNumber).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// POST /reviews/{reviewID}/reply
+// PATCH /admin/reviews/{reviewID}
+
+func (r *review) Update(review *types.Review) error {
+	err := db.Save(review).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DELETE /admin/reviews/{reviewID}
+
+func (r *review) Delete(review *types.Review) error {
+	err := db.Delete(review).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *review) FindByReviewID(reviewID string) (*types.Review, error) {
+	var result types.Review
+	query := db.Where("review_id = ?", reviewID).First(&result)
+	if query.RecordNotFound() {
+		return nil, errors.New("Review not found.")
+	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	return &result, nil
+}
+
+// GET /transfers/{transferID}/reviews
+
+func (r *review) FindByTransferID(transferID string) ([]*types.Review, error) {
+	var reviews []*types.Review
+	err := db.Where("transfer_id = ?", transferID).Order("created_at").Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// GET /entities/{searchEntityID}/reviews
+// GET /admin/reviews
+
+func (r *review) Search(req *types.SearchReviewReq) (*types.SearchReviewResult, error) {
+	var reviews []*types.Review
+	var count int
+
+	query := db.Model(&types.Review{})
+	if req.RevieweeAccountNumber != "" {
+		query = query.Where("reviewee_account_number = ?", req.RevieweeAccountNumber)
+	}
+	if req.Hidden != nil {
+		query = query.Where("hidden = ?", *req.Hidden)
+	}
+
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	err = query.Order("created_at DESC").
+		Offset(req.PageSize * (req.Page - 1)).
+		Limit(req.PageSize).
+		Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SearchReviewResult{
+		Reviews:         reviews,
+		NumberOfResults: count,
+		TotalPages:      util.GetNumberOfPages(count, req.PageSize),
+	}, nil
+}
+
+// Rating returns the average rating and the number of the visible reviews of the entity.
+func (r *review) Rating(accountNumber string) (float64, int, error) {
+	var result struct {
+		Rating float64
+		Count  int
+	}
+	err := db.Raw(`
+		SELECT COALESCE(AVG(rating), 0) AS rating, COUNT(*) AS count
+		FROM reviews
+		WHERE deleted_at IS NULL AND reviewee_account_number = ? AND hidden = false
+	`, accountNumber).Scan(&result).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return result.Rating, result.Count, nil
+}
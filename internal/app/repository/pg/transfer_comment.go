@@ -0,0 +1,30 @@
+package pg
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+)
+
+type transferComment struct{}
+
+var TransferComment = &transferComment{}
+
+// POST /transfers/{transferID}/comments
+
+func (t *transferComment) Create(comment *types.TransferComment) (*types.TransferComment, error) {
+	err := db.Create(comment).Error
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// GET /transfers/{transferID}/comments
+
+func (t *transferComment) FindByTransferID(transferID string) ([]*types.TransferComment, error) {
+	var comments []*types.TransferComment
+	err := db.Where("transfer_id = ?", transferID).Order("created_at").Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
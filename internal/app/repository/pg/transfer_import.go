@@ -0,0 +1,137 @@
+package pg
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/jinzhu/gorm"
+)
+
+type transferImport struct{}
+
+var TransferImport = &transferImport{}
+
+// POST /admin/transfers/imports
+
+func (t *transferImport) Create(record *types.TransferImport) (*types.TransferImport, error) {
+	err := db.Create(record).Error
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// POST /admin/transfers/imports/{importID}/execution
+
+// Claim moves the validated import to executing and returns false when it is not validated anymore.
+// Only one request can claim the import, so its transfers are never created twice.
+func (t *transferImport) Claim(importID string) (bool, error) {
+	query := db.Exec(`
+		UPDATE transfer_imports
+		SET status = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND import_id = ? AND status = ?
+	`, constant.TransferImport.Executing, time.Now(), importID, constant.TransferImport.Validated)
+	if query.Error != nil {
+		return false, query.Error
+	}
+	return query.RowsAffected == 1, nil
+}
+
+// SaveRow saves the result of a row while the import is executing.
+// It also updates the import so that it is not considered stale while its rows are being created.
+func (t *transferImport) SaveRow(record *types.TransferImport, row *types.TransferImportRow) error {
+	tx := db.Begin()
+	err := tx.Save(row).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Model(record).UpdateColumn("updated_at", time.Now()).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// POST /admin/transfers/imports/{importID}/reset
+
+// Release moves an import that has been executing since before staleBefore back to validated,
+// e.g. when the server stopped while the import was executed.
+func (t *transferImport) Release(importID string, staleBefore time.Time) error {
+	query := db.Exec(`
+		UPDATE transfer_imports
+		SET status = ?, mode = '', updated_at = ?
+		WHERE deleted_at IS NULL AND import_id = ? AND status = ? AND updated_at < ?
+	`, constant.TransferImport.Validated, time.Now(), importID, constant.TransferImport.Executing, staleBefore)
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return errors.New("Only an import that has been executing for longer than the execution timeout can be reset.")
+	}
+	return nil
+}
+
+func (t *transferImport) Update(record *types.TransferImport) error {
+	tx := db.Begin()
+	err := t.update(tx, record)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+func (t *transferImport) update(tx *gorm.DB, record *types.TransferImport) error {
+	err := tx.Save(record).Error
+	if err != nil {
+		return err
+	}
+	for _, row := range record.Rows {
+		err := tx.Save(row).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExecuteBatch creates the transfers of every row and saves the executed import in one transaction,
+// so either the import is executed with all its transfers or nothing is changed.
+// Transfers flagged in requiresApproval are created pending approval.
+func (t *transferImport) ExecuteBatch(record *types.TransferImport, reqs []*types.AdminTransferReq, requiresApproval []bool, requestedBy string) ([]*types.Journal, error) {
+	tx := db.Begin()
+	journals, err := Journal.createBatch(tx, reqs, requiresApproval, requestedBy)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for i, row := range record.Rows {
+		row.SetResult(journals[i])
+	}
+	err = t.update(tx, record)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return journals, tx.Commit().Error
+}
+
+// GET /admin/transfers/imports/{importID}
+
+func (t *transferImport) FindByImportID(importID string) (*types.TransferImport, error) {
+	var result types.TransferImport
+	query := db.Preload("Rows", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line")
+	}).Where("import_id = ?", importID).First(&result)
+	if query.RecordNotFound() {
+		return nil, errors.New("Import not found.")
+	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	return &result, nil
+}
@@ -0,0 +1,187 @@
+package pg
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+)
+
+type voucherKey struct{}
+
+var VoucherKey = &voucherKey{}
+
+// POST /vouchers
+
+func (v *voucherKey) Create(key *types.VoucherKey) (*types.VoucherKey, error) {
+	err := db.Create(key).Error
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// FindByAccountNumber returns the key of the account or nil if it has not been issued yet.
+func (v *voucherKey) FindByAccountNumber(accountNumber string) (*types.VoucherKey, error) {
+	var result types.VoucherKey
+	query := db.Where("account_number = ?", accountNumber).First(&result)
+	if query.RecordNotFound() {
+		return nil, nil
+	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	return &result, nil
+}
+
+// FindAll returns the keys of every account.
+func (v *voucherKey) FindAll() ([]*types.VoucherKey, error) {
+	var keys []*types.VoucherKey
+	err := db.Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (v *voucherKey) UpdatePrivateKey(key *types.VoucherKey, privateKey string) error {
+	return db.Model(key).Update("private_key", privateKey).Error
+}
+
+// WidenPrivateKey makes room for the encrypted private keys in tables created when the keys were stored as they are.
+func (v *voucherKey) WidenPrivateKey() error {
+	return db.Exec(`ALTER TABLE voucher_keys ALTER COLUMN private_key TYPE varchar(255)`).Error
+}
+
+type voucher struct{}
+
+var Voucher = &voucher{}
+
+// POST /vouchers
+
+func (v *voucher) Create(voucher *types.Voucher) (*types.Voucher, error) {
+	err := db.Create(voucher).Error
+	if err != nil {
+		return nil, err
+	}
+	return voucher, nil
+}
+
+// ReservedAmount sums up the vouchers of the account that can still be redeemed.
+func (v *voucher) ReservedAmount(accountNumber string) (float64, error) {
+	var result struct {
+		Total float64
+	}
+	err := db.Raw(`
+		SELECT COALESCE(SUM(amount), 0) AS total
+		FROM vouchers
+		WHERE deleted_at IS NULL AND payer_account_number = ? AND status = ? AND expires_at > ?
+	`, accountNumber, constant.Voucher.Issued, time.Now()).Scan(&result).Error
+	if err != nil {
+		return 0, err
+	}
+	return result.Total, nil
+}
+
+// GET /vouchers
+
+func (v *voucher) FindByAccountNumber(accountNumber string) ([]*types.Voucher, error) {
+	var vouchers []*types.Voucher
+	err := db.Where("payer_account_number = ?", accountNumber).Order("created_at DESC").Find(&vouchers).Error
+	if err != nil {
+		return nil, err
+	}
+	return vouchers, nil
+}
+
+func (v *voucher) FindByNonce(nonce string) (*types.Voucher, error) {
+	var result types.Voucher
+	query := db.Where("nonce = ?", nonce).First(&result)
+	if query.RecordNotFound() {
+		return nil, errors.New("Voucher not found.")
+	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	return &result, nil
+}
+
+// DELETE /vouchers/{voucherID}
+
+func (v *voucher) Cancel(nonce string) (*types.Voucher, error) {
+	query := db.Exec(`
+		UPDATE vouchers
+		SET status = ?, cancelled_at = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND nonce = ? AND status = ?
+	`, constant.Voucher.Cancelled, time.Now(), time.Now(), nonce, constant.Voucher.Issued)
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	if query.RowsAffected == 0 {
+		return nil, errors.New("Only unredeemed vouchers can be cancelled.")
+	}
+	return v.FindByNonce(nonce)
+}
+
+// POST /admin/accounts/{accountNumber}/closure
+
+// CancelByAccountNumber cancels every unredeemed voucher of the account.
+func (v *voucher) CancelByAccountNumber(accountNumber string) error {
+	return db.Exec(`
+		UPDATE vouchers
+		SET status = ?, cancelled_at = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND payer_account_number = ? AND status = ?
+	`, constant.Voucher.Cancelled, time.Now(), time.Now(), accountNumber, constant.Voucher.Issued).Error
+}
+
+// POST /vouchers/redeem
+
+// Redeem marks the voucher as redeemed and moves the money in one transaction.
+// The voucher can only be redeemed once, even when the same voucher is submitted concurrently.
+func (v *voucher) Redeem(voucher *types.Voucher, payee *types.Entity) (*types.Journal, error) {
+	tx := db.Begin()
+
+	now := time.Now()
+	query := tx.Exec(`
+		UPDATE vouchers
+		SET status = ?, redeemed_by = ?, redeemed_at = ?, updated_at = ?
+		WHERE deleted_at IS NULL AND nonce = ? AND status = ? AND expires_at > ?
+	`, constant.Voucher.Redeemed, payee.AccountNumber, now, now, voucher.Nonce, constant.Voucher.Issued, now)
+	if query.Error != nil {
+		tx.Rollback()
+		return nil, query.Error
+	}
+	if query.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, errors.New("The voucher has already been redeemed, cancelled or has expired.")
+	}
+
+	journal, err := Journal.propose(tx, &types.TransferReq{
+		InitiatorAccountNumber: payee.AccountNumber,
+		FromAccountNumber:      voucher.PayerAccountNumber,
+		FromEntityName:         voucher.PayerEntityName,
+		ToAccountNumber:        payee.AccountNumber,
+		ToEntityName:           payee.Name,
+		Amount:                 voucher.Amount,
+		Description:            voucher.Description,
+		TransferType:           constant.TransferType.Voucher,
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	completed, err := Journal.accept(tx, journal)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Model(&types.Voucher{}).Where("nonce = ?", voucher.Nonce).Update("transfer_id", completed.TransferID).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return completed, tx.Commit().Error
+}
@@ -57,6 +57,8 @@ type SignupReq struct {
 	Region           string   `json:"region"`
 	PostalCode       string   `json:"postalCode"`
 	Country          string   `json:"country"`
+	Latitude         *float64 `json:"latitude"`
+	Longitude        *float64 `json:"longitude"`
 	Offers           []string `json:"offers"`
 	Wants            []string `json:"wants"`
 	// flags
@@ -90,6 +92,8 @@ func (req *SignupReq) validate() []error {
 		Address:          req.Address,
 		Region:           req.Region,
 		PostalCode:       req.PostalCode,
+		Latitude:         req.Latitude,
+		Longitude:        req.Longitude,
 	}
 
 	errs = append(errs, user.Validate()...)
@@ -188,6 +192,91 @@ func (req *UpdateUserReq) validate() []error {
 	return errs
 }
 
+// POST /user/entities
+
+func NewCreateUserEntityReq(r *http.Request, user *User) (*CreateUserEntityReq, []error) {
+	var req CreateUserEntityReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	// Use the user's email address if an email address for the entity is not provided.
+	if req.Email == "" {
+		req.Email = user.Email
+	}
+	req.User = user
+	return &req, req.validate()
+}
+
+type CreateUserEntityReq struct {
+	Name             string   `json:"name"`
+	Email            string   `json:"email"`
+	IncType          string   `json:"incType"`
+	CompanyNumber    string   `json:"companyNumber"`
+	Telephone        string   `json:"telephone"`
+	Website          string   `json:"website"`
+	DeclaredTurnover *int     `json:"declaredTurnover"`
+	Description      string   `json:"description"`
+	Address          string   `json:"address"`
+	City             string   `json:"city"`
+	Region           string   `json:"region"`
+	PostalCode       string   `json:"postalCode"`
+	Country          string   `json:"country"`
+	Latitude         *float64 `json:"latitude"`
+	Longitude        *float64 `json:"longitude"`
+	Offers           []string `json:"offers"`
+	Wants            []string `json:"wants"`
+	// flags
+	ShowTagsMatchedSinceLastLogin      *bool `json:"showTagsMatchedSinceLastLogin"`
+	ReceiveDailyMatchNotificationEmail *bool `json:"receiveDailyMatchNotificationEmail"`
+
+	User *User
+}
+
+func (req *CreateUserEntityReq) validate() []error {
+	errs := []error{}
+
+	if strings.TrimSpace(req.Name) == "" {
+		errs = append(errs, errors.New("Entity name is missing."))
+	}
+	errs = append(errs, req.Entity().Validate()...)
+	errs = append(errs, validateTags(req.Offers)...)
+	errs = append(errs, validateTags(req.Wants)...)
+
+	return errs
+}
+
+// Entity returns the entity to be created.
+func (req *CreateUserEntityReq) Entity() *Entity {
+	return &Entity{
+		Name:                               req.Name,
+		Email:                              req.Email,
+		IncType:                            req.IncType,
+		CompanyNumber:                      req.CompanyNumber,
+		Telephone:                          req.Telephone,
+		Website:                            req.Website,
+		DeclaredTurnover:                   req.DeclaredTurnover,
+		Description:                        req.Description,
+		Address:                            req.Address,
+		City:                               req.City,
+		Region:                             req.Region,
+		PostalCode:                         req.PostalCode,
+		Country:                            req.Country,
+		Latitude:                           req.Latitude,
+		Longitude:                          req.Longitude,
+		Offers:                             ToTagFields(req.Offers),
+		Wants:                              ToTagFields(req.Wants),
+		ShowTagsMatchedSinceLastLogin:      req.ShowTagsMatchedSinceLastLogin,
+		ReceiveDailyMatchNotificationEmail: req.ReceiveDailyMatchNotificationEmail,
+	}
+}
+
+// PATCH /user/entities/{entityID}
+
 func NewUpdateUserEntityReq(j UpdateUserEntityJSON, originEntity *Entity) (*UpdateUserEntityReq, []error) {
 	errs := j.validate()
 	if len(errs) != 0 {
@@ -228,9 +317,13 @@ func NewUpdateUserEntityReq(j UpdateUserEntityJSON, originEntity *Entity) (*Upda
 		Region:     j.Region,
 		PostalCode: j.PostalCode,
 		Country:    j.Country,
+		Latitude:   j.Latitude,
+		Longitude:  j.Longitude,
 		// flags
 		ShowTagsMatchedSinceLastLogin:      j.ShowTagsMatchedSinceLastLogin,
 		ReceiveDailyMatchNotificationEmail: j.ReceiveDailyMatchNotificationEmail,
+		AutoAccept:                         j.AutoAccept,
+		Handle:                             util.FormatHandle(j.Handle),
 	}
 
 	return &req, nil
@@ -252,6 +345,10 @@ type UpdateUserEntityReq struct {
 	Region     string
 	PostalCode string
 	Country    string
+	Latitude   *float64
+	Longitude  *float64
+	// ClearLocation removes the stored coordinates, e.g. when the new postal code could not be geocoded.
+	ClearLocation bool
 	// Tags
 	Offers        *[]string
 	AddedOffers   []string
@@ -262,28 +359,34 @@ type UpdateUserEntityReq struct {
 	// flags
 	ShowTagsMatchedSinceLastLogin      *bool `json:"showTagsMatchedSinceLastLogin"`
 	ReceiveDailyMatchNotificationEmail *bool `json:"receiveDailyMatchNotificationEmail"`
+	AutoAccept                         *AutoAcceptRules
+	Handle                             string
 }
 
 type UpdateUserEntityJSON struct {
-	Name             string `json:"name"`
-	Email            string `json:"email"`
-	Telephone        string `json:"telephone"`
-	IncType          string `json:"incType"`
-	CompanyNumber    string `json:"companyNumber"`
-	Website          string `json:"website"`
-	DeclaredTurnover *int   `json:"declaredTurnover"`
-	Description      string `json:"description"`
-	Address          string `json:"address"`
-	City             string `json:"city"`
-	Region           string `json:"region"`
-	PostalCode       string `json:"postalCode"`
-	Country          string `json:"country"`
+	Name             string   `json:"name"`
+	Email            string   `json:"email"`
+	Telephone        string   `json:"telephone"`
+	IncType          string   `json:"incType"`
+	CompanyNumber    string   `json:"companyNumber"`
+	Website          string   `json:"website"`
+	DeclaredTurnover *int     `json:"declaredTurnover"`
+	Description      string   `json:"description"`
+	Address          string   `json:"address"`
+	City             string   `json:"city"`
+	Region           string   `json:"region"`
+	PostalCode       string   `json:"postalCode"`
+	Country          string   `json:"country"`
+	Latitude         *float64 `json:"latitude"`
+	Longitude        *float64 `json:"longitude"`
 	// Tags
 	Offers *[]string `json:"offers"`
 	Wants  *[]string `json:"wants"`
 	// flags
-	ShowTagsMatchedSinceLastLogin      *bool `json:"showTagsMatchedSinceLastLogin"`
-	ReceiveDailyMatchNotificationEmail *bool `json:"receiveDailyMatchNotificationEmail"`
+	ShowTagsMatchedSinceLastLogin      *bool            `json:"showTagsMatchedSinceLastLogin"`
+	ReceiveDailyMatchNotificationEmail *bool            `json:"receiveDailyMatchNotificationEmail"`
+	AutoAccept                         *AutoAcceptRules `json:"autoAccept"`
+	Handle                             string           `json:"handle"`
 	// Not allow to change
 	ID     string `json:"id"`
 	Status string `json:"status"`
@@ -313,6 +416,8 @@ func (req *UpdateUserEntityJSON) validate() []error {
 		Address:          req.Address,
 		Region:           req.Region,
 		PostalCode:       req.PostalCode,
+		Latitude:         req.Latitude,
+		Longitude:        req.Longitude,
 	}
 	errs = append(errs, entity.Validate()...)
 	if req.Offers != nil {
@@ -321,6 +426,10 @@ func (req *UpdateUserEntityJSON) validate() []error {
 	if req.Wants != nil {
 		errs = append(errs, validateTags(*req.Wants)...)
 	}
+	if req.AutoAccept != nil {
+		errs = append(errs, req.AutoAccept.Validate()...)
+	}
+	errs = append(errs, validateHandle(req.Handle)...)
 
 	return errs
 }
@@ -373,6 +482,14 @@ func validateTags(tags []string) []error {
 	return errs
 }
 
+func validateHandle(handle string) []error {
+	errs := []error{}
+	if handle != "" && !util.IsValidHandle(util.FormatHandle(handle)) {
+		errs = append(errs, errors.New("Handle should be 3-30 characters long and only contain letters, numbers, dashes and underscores."))
+	}
+	return errs
+}
+
 func validatePassword(password string) []error {
 	minLen, hasLetter, hasNumber, hasSpecial := viper.GetInt("validate.password.minLen"), false, false, false
 
@@ -445,7 +562,17 @@ func (req *EmailReq) validate() []error {
 
 // POST /transfers
 
-func NewTransferReq(userReq *TransferUserReq, initiatorEntity *Entity, receiverEntity *Entity) (*TransferReq, []error) {
+func NewTransferReq(userReq *TransferUserReq, initiatorEntity *Entity, receiverEntity *Entity, payee *Payee) (*TransferReq, []error) {
+	// The payee's defaults are used when the user leaves the fields empty.
+	if payee != nil {
+		if userReq.Amount == 0 {
+			userReq.Amount = payee.DefaultAmount
+		}
+		if userReq.Description == "" {
+			userReq.Description = payee.DefaultDescription
+		}
+	}
+
 	req := &TransferReq{
 		TransferDirection:      userReq.TransferDirection,
 		TransferType:           constant.TransferType.Transfer,
@@ -459,6 +586,11 @@ func NewTransferReq(userReq *TransferUserReq, initiatorEntity *Entity, receiverE
 		ReceiverEntityName:     receiverEntity.Name,
 		InitiatorEntity:        initiatorEntity,
 		ReceiverEntity:         receiverEntity,
+		Payee:                  payee,
+		Escrow:                 userReq.Escrow,
+	}
+	if req.Escrow {
+		req.TransferType = constant.TransferType.Escrow
 	}
 
 	if req.TransferDirection == constant.TransferDirection.Out {
@@ -492,8 +624,10 @@ type TransferUserReq struct {
 	TransferDirection      string  `json:"transfer"`
 	InitiatorAccountNumber string  `json:"initiator"`
 	ReceiverAccountNumber  string  `json:"receiver"`
+	PayeeID                string  `json:"payeeID"`
 	Amount                 float64 `json:"amount"`
 	Description            string  `json:"description"`
+	Escrow                 bool    `json:"escrow"`
 }
 
 type TransferReq struct {
@@ -523,6 +657,10 @@ type TransferReq struct {
 
 	InitiatorEntity *Entity
 	ReceiverEntity  *Entity
+	// Optional, set when the receiver is picked from the initiator's payees.
+	Payee *Payee
+	// The amount is held in escrow until the initiator confirms the delivery.
+	Escrow bool
 }
 
 func (req *TransferReq) Validate() []error {
@@ -531,6 +669,12 @@ func (req *TransferReq) Validate() []error {
 	if req.TransferDirection != constant.TransferDirection.In && req.TransferDirection != constant.TransferDirection.Out {
 		errs = append(errs, errors.New("Transfer can be only 'in' or 'out'."))
 	}
+	if req.Escrow && req.TransferDirection != constant.TransferDirection.Out {
+		errs = append(errs, errors.New("Only outgoing transfers can be held in escrow."))
+	}
+	if constant.TransferTypeRules[req.TransferType].AdminOnly {
+		errs = append(errs, errors.New("Only admins can create "+req.TransferType+" journals."))
+	}
 
 	if req.InitiatorAccountNumber == "" {
 		errs = append(errs, errors.New("Initiator is empty."))
@@ -570,6 +714,210 @@ func (req *TransferReq) Validate() []error {
 	return errs
 }
 
+// POST /user/entities/{entityID}/payees
+
+func NewCreatePayeeReq(r *http.Request, entity *Entity) (*CreatePayeeReq, []error) {
+	var req CreatePayeeReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req.Entity = entity
+	req.Nickname = strings.TrimSpace(req.Nickname)
+	return &req, req.validate()
+}
+
+type CreatePayeeReq struct {
+	Entity             *Entity `json:"-"`
+	Nickname           string  `json:"nickname"`
+	AccountNumber      string  `json:"accountNumber"`
+	DefaultDescription string  `json:"defaultDescription"`
+	DefaultAmount      float64 `json:"defaultAmount"`
+}
+
+func (req *CreatePayeeReq) validate() []error {
+	errs := []error{}
+	if req.Nickname == "" {
+		errs = append(errs, errors.New("Please enter a nickname."))
+	}
+	if req.AccountNumber == "" {
+		errs = append(errs, errors.New("Please enter an account number."))
+	}
+	errs = append(errs, validatePayee(req.Entity, req.Nickname, req.AccountNumber, req.DefaultDescription, req.DefaultAmount)...)
+	return errs
+}
+
+func validatePayee(entity *Entity, nickname, accountNumber, defaultDescription string, defaultAmount float64) []error {
+	errs := []error{}
+	if len(nickname) > 50 {
+		errs = append(errs, errors.New("Nickname length cannot exceed 50 characters."))
+	}
+	if accountNumber != "" && !util.IsHandle(accountNumber) {
+		if goluhn.Validate(accountNumber) != nil {
+			errs = append(errs, errors.New("Account number is invalid."))
+		} else if accountNumber == entity.AccountNumber {
+			errs = append(errs, errors.New("You cannot add yourself as a payee."))
+		}
+	}
+	if len(defaultDescription) > 510 {
+		errs = append(errs, errors.New("Default description length cannot exceed 510 characters."))
+	}
+	if defaultAmount < 0 || !util.IsDecimalValid(defaultAmount) {
+		errs = append(errs, errors.New("Please enter a valid default amount with up to two decimal places."))
+	}
+	return errs
+}
+
+// PATCH /user/entities/{entityID}/payees/{payeeID}
+
+func NewUpdatePayeeReq(r *http.Request, entity *Entity, originPayee *Payee) (*UpdatePayeeReq, []error) {
+	var req UpdatePayeeReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req.Entity = entity
+	req.OriginPayee = originPayee
+	req.Nickname = strings.TrimSpace(req.Nickname)
+	return &req, req.validate()
+}
+
+type UpdatePayeeReq struct {
+	Entity             *Entity  `json:"-"`
+	OriginPayee        *Payee   `json:"-"`
+	Nickname           string   `json:"nickname"`
+	AccountNumber      string   `json:"accountNumber"`
+	DefaultDescription *string  `json:"defaultDescription"`
+	DefaultAmount      *float64 `json:"defaultAmount"`
+}
+
+func (req *UpdatePayeeReq) validate() []error {
+	var defaultDescription string
+	if req.DefaultDescription != nil {
+		defaultDescription = *req.DefaultDescription
+	}
+	var defaultAmount float64
+	if req.DefaultAmount != nil {
+		defaultAmount = *req.DefaultAmount
+	}
+	return validatePayee(req.Entity, req.Nickname, req.AccountNumber, defaultDescription, defaultAmount)
+}
+
+// POST /vouchers
+
+func NewIssueVoucherReq(userReq *IssueVoucherUserReq, payerEntity *Entity) (*IssueVoucherReq, []error) {
+	req := &IssueVoucherReq{
+		PayerEntity: payerEntity,
+		Amount:      userReq.Amount,
+		Description: strings.TrimSpace(userReq.Description),
+		ExpiresAt:   userReq.ExpiresAt,
+	}
+	if req.ExpiresAt.IsZero() {
+		req.ExpiresAt = time.Now().AddDate(0, 0, viper.GetInt("voucher.default_validity_days"))
+	}
+	return req, req.validate()
+}
+
+type IssueVoucherUserReq struct {
+	Payer       string    `json:"payer"`
+	Amount      float64   `json:"amount"`
+	Description string    `json:"description"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+type IssueVoucherReq struct {
+	PayerEntity *Entity
+	Amount      float64
+	Description string
+	ExpiresAt   time.Time
+}
+
+func (req *IssueVoucherReq) validate() []error {
+	errs := []error{}
+	if req.Amount <= 0 || !util.IsDecimalValid(req.Amount) {
+		errs = append(errs, errors.New("Please enter a valid numeric amount with up to two decimal places."))
+	}
+	if len(req.Description) > 510 {
+		errs = append(errs, errors.New("Description length cannot exceed 510 characters."))
+	}
+	if !req.ExpiresAt.After(time.Now()) {
+		errs = append(errs, errors.New("The expiry date must be in the future."))
+	} else if req.ExpiresAt.After(time.Now().AddDate(0, 0, viper.GetInt("voucher.max_validity_days"))) {
+		errs = append(errs, errors.New("Vouchers can be valid for at most "+viper.GetString("voucher.max_validity_days")+" days."))
+	}
+	if req.PayerEntity.Status != constant.Trading.Accepted {
+		errs = append(errs, errors.New("Sender is not a trading member. Vouchers can only be issued by trading members."))
+	}
+	return errs
+}
+
+// GET /vouchers
+
+func NewSearchVoucherReq(r *http.Request) (*SearchVoucherReq, []error) {
+	req := &SearchVoucherReq{
+		QueryingEntityID: r.URL.Query().Get("querying_entity_id"),
+	}
+	return req, req.validate()
+}
+
+type SearchVoucherReq struct {
+	QueryingEntityID string
+}
+
+func (req *SearchVoucherReq) validate() []error {
+	errs := []error{}
+	if req.QueryingEntityID == "" {
+		errs = append(errs, errors.New("Please specify the querying_entity_id."))
+	}
+	return errs
+}
+
+// POST /vouchers/redeem
+
+func NewRedeemVoucherReq(voucher *Voucher, payeeEntity *Entity) (*RedeemVoucherReq, []error) {
+	req := &RedeemVoucherReq{
+		Voucher:     voucher,
+		PayeeEntity: payeeEntity,
+	}
+	return req, req.validate()
+}
+
+type RedeemVoucherUserReq struct {
+	Voucher string `json:"voucher"`
+	Payee   string `json:"payee"`
+}
+
+type RedeemVoucherReq struct {
+	Voucher     *Voucher
+	PayeeEntity *Entity
+}
+
+func (req *RedeemVoucherReq) validate() []error {
+	errs := []error{}
+	if req.Voucher.Status == constant.Voucher.Redeemed {
+		errs = append(errs, errors.New("The voucher has already been redeemed."))
+	} else if req.Voucher.Status == constant.Voucher.Cancelled {
+		errs = append(errs, errors.New("The voucher has been cancelled by the payer."))
+	} else if req.Voucher.IsExpired() {
+		errs = append(errs, errors.New("The voucher has expired."))
+	}
+	if req.PayeeEntity.Status != constant.Trading.Accepted {
+		errs = append(errs, errors.New("Recipient is not a trading member. Vouchers can only be redeemed by trading members."))
+	}
+	if req.PayeeEntity.AccountNumber == req.Voucher.PayerAccountNumber {
+		errs = append(errs, errors.New("You cannot redeem your own voucher."))
+	}
+	return errs
+}
+
 // GET /transfers
 
 func NewSearchTransferQuery(r *http.Request, entity *Entity) (*SearchTransferReq, []error) {
@@ -609,7 +957,7 @@ func (req *SearchTransferReq) validate() []error {
 	if req.QueryingEntityID == "" {
 		errs = append(errs, errors.New("Please specify the querying_entity_id."))
 	}
-	if req.Status != "all" && req.Status != "initiated" && req.Status != "completed" && req.Status != "cancelled" {
+	if req.Status != "all" && req.Status != "initiated" && req.Status != "completed" && req.Status != "cancelled" && req.Status != "escrowed" {
 		errs = append(errs, errors.New("Please specify valid status."))
 	}
 
@@ -668,10 +1016,18 @@ type UpdateTransferReq struct {
 func (req *UpdateTransferReq) Validate() []error {
 	errs := []error{}
 
-	if req.Action != "accept" && req.Action != "reject" && req.Action != "cancel" {
+	if req.Action != "accept" && req.Action != "reject" && req.Action != "cancel" && req.Action != "release" && req.Action != "refund" {
 		errs = append(errs, errors.New("Please enter a valid action."))
 	}
-	if req.Journal.Status == constant.Transfer.Completed {
+	isEscrowAction := req.Action == "release" || req.Action == "refund"
+	if req.Journal.Status == constant.Transfer.Escrowed && !isEscrowAction {
+		errs = append(errs, errors.New("The transfer is held in escrow. It can only be released or refunded."))
+	} else if req.Journal.Status == constant.Transfer.Initiated && isEscrowAction {
+		errs = append(errs, errors.New("Only transfers held in escrow can be released or refunded."))
+	}
+	if req.Journal.Status == constant.Transfer.PendingApproval {
+		errs = append(errs, errors.New("The transfer is waiting for an admin's approval."))
+	} else if req.Journal.Status == constant.Transfer.Completed {
 		errs = append(errs, errors.New("The transaction has already been completed by the counterparty."))
 	} else if req.Journal.Status == constant.Transfer.Cancelled {
 		errs = append(errs, errors.New("The transaction has already been cancelled by the counterparty."))
@@ -680,104 +1036,342 @@ func (req *UpdateTransferReq) Validate() []error {
 	return errs
 }
 
-// GET /entities
+// POST /transfers/{transferID}/comments
 
-func NewSearchEntityReq(q url.Values) (*SearchEntityReq, error) {
-	page, err := util.ToInt(q.Get("page"), 1)
+func NewTransferCommentReq(r *http.Request, journal *Journal, entity *Entity) (*TransferCommentReq, []error) {
+	var body TransferCommentUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
 	if err != nil {
-		return nil, err
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
 	}
-	pageSize, err := util.ToInt(q.Get("page_size"), viper.GetInt("page_size"))
-	if err != nil {
-		return nil, err
+	req := &TransferCommentReq{
+		Journal: journal,
+		Entity:  entity,
+		UserID:  r.Header.Get("userID"),
+		Body:    strings.TrimSpace(body.Body),
 	}
-	return &SearchEntityReq{
-		QueryingEntityID: q.Get("querying_entity_id"),
-		Page:             page,
-		PageSize:         pageSize,
-		Name:             q.Get("name"),
-		Category:         q.Get("category"),
-		Offers:           util.ToSearchTags(q.Get("offers")),
-		Wants:            util.ToSearchTags(q.Get("wants")),
-		TaggedSince:      util.ParseTime(q.Get("tagged_since")),
-		FavoritesOnly:    q.Get("favorites_only") == "true",
-		Statuses: []string{
-			constant.Entity.Accepted,
-			constant.Trading.Pending,
-			constant.Trading.Accepted,
-			constant.Trading.Rejected,
-		},
-	}, nil
+	return req, req.validate()
 }
 
-type SearchEntityReq struct {
-	QueryingEntityID string
-	Page             int
-	PageSize         int
-	Name             string
-	Wants            []string
-	Offers           []string
-	Category         string
-	FavoriteEntities []primitive.ObjectID
-	FavoritesOnly    bool
-	TaggedSince      time.Time
-	Statuses         []string
+type TransferCommentUserReq struct {
+	Body string `json:"body"`
+}
 
-	Country string
-	City    string
+type TransferCommentReq struct {
+	Journal *Journal
+	// The party of the transfer the comment is posted as.
+	Entity *Entity
+	UserID string
+	Body   string
 }
 
-func (query *SearchEntityReq) Validate() []error {
+func (req *TransferCommentReq) validate() []error {
 	errs := []error{}
-
-	if query.FavoritesOnly == true && query.QueryingEntityID == "" {
-		errs = append(errs, errors.New("Please specify the querying_entity_id."))
+	if req.Body == "" {
+		errs = append(errs, errors.New("Please enter a comment."))
+	} else if len(req.Body) > 1000 {
+		errs = append(errs, errors.New("Comment length cannot exceed 1000 characters."))
 	}
-
-	if !query.TaggedSince.IsZero() && len(query.Wants) == 0 && len(query.Offers) == 0 {
-		errs = append(errs, errors.New("Please specify an offer or want tag."))
-	}
-
 	return errs
 }
 
-// GET /entities/{entityID}
+// POST /transfers/{transferID}/reviews
 
-func NewGetEntityReq(r *http.Request) (*GetEntity, []error) {
-	req := &GetEntity{
-		SearchEntityID:   mux.Vars(r)["searchEntityID"],
-		QueryingEntityID: r.URL.Query().Get("querying_entity_id"),
+func NewCreateReviewReq(r *http.Request, journal *Journal, reviewer *Entity, reviewee *Entity) (*CreateReviewReq, []error) {
+	var body CreateReviewUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req := &CreateReviewReq{
+		Journal:  journal,
+		Reviewer: reviewer,
+		Reviewee: reviewee,
+		UserID:   r.Header.Get("userID"),
+		Rating:   body.Rating,
+		Body:     strings.TrimSpace(body.Body),
 	}
 	return req, req.validate()
 }
 
-type GetEntity struct {
-	SearchEntityID   string
-	QueryingEntityID string
+type CreateReviewUserReq struct {
+	Rating int    `json:"rating"`
+	Body   string `json:"body"`
 }
 
-func (q *GetEntity) validate() []error {
-	errs := []error{}
-	return errs
+type CreateReviewReq struct {
+	Journal *Journal
+	// The party of the transfer who writes the review and the party who is reviewed.
+	Reviewer *Entity
+	Reviewee *Entity
+	UserID   string
+	Rating   int
+	Body     string
 }
 
-func NewSearchTagReq(q url.Values) (*SearchTagReq, error) {
-	page, err := util.ToInt(q.Get("page"), 1)
-	if err != nil {
-		return nil, err
+func (req *CreateReviewReq) validate() []error {
+	errs := []error{}
+	if req.Journal.Status != constant.Transfer.Completed {
+		errs = append(errs, errors.New("Only completed transfers can be reviewed."))
 	}
-	pageSize, err := util.ToInt(q.Get("page_size"), viper.GetInt("page_size"))
-	if err != nil {
-		return nil, err
+	if req.Rating < 1 || req.Rating > 5 {
+		errs = append(errs, errors.New("Rating should be between 1 and 5."))
 	}
-	return &SearchTagReq{
-		Fragment: q.Get("fragment"),
-		Page:     page,
-		PageSize: pageSize,
-	}, nil
+	if len(req.Body) > 1000 {
+		errs = append(errs, errors.New("Review length cannot exceed 1000 characters."))
+	}
+	return errs
 }
 
-type SearchTagReq struct {
+// POST /reviews/{reviewID}/reply
+
+func NewReplyReviewReq(r *http.Request, review *Review) (*ReplyReviewReq, []error) {
+	var body struct {
+		Body string `json:"body"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req := &ReplyReviewReq{
+		Review: review,
+		Body:   strings.TrimSpace(body.Body),
+	}
+	return req, req.validate()
+}
+
+type ReplyReviewReq struct {
+	Review *Review
+	Body   string
+}
+
+func (req *ReplyReviewReq) validate() []error {
+	errs := []error{}
+	if req.Body == "" {
+		errs = append(errs, errors.New("Please enter a reply."))
+	} else if len(req.Body) > 1000 {
+		errs = append(errs, errors.New("Reply length cannot exceed 1000 characters."))
+	}
+	return errs
+}
+
+// GET /entities/{searchEntityID}/reviews
+// GET /admin/reviews
+
+func NewSearchReviewReq(q url.Values) (*SearchReviewReq, []error) {
+	page, err := util.ToInt(q.Get("page"), 1)
+	if err != nil {
+		return nil, []error{err}
+	}
+	pageSize, err := util.ToInt(q.Get("page_size"), viper.GetInt("page_size"))
+	if err != nil {
+		return nil, []error{err}
+	}
+	req := &SearchReviewReq{
+		Page:                  page,
+		PageSize:              pageSize,
+		RevieweeAccountNumber: q.Get("account_number"),
+	}
+	if q.Get("hidden") != "" {
+		hidden := q.Get("hidden") == "true"
+		req.Hidden = &hidden
+	}
+	return req, req.validate()
+}
+
+type SearchReviewReq struct {
+	Page                  int
+	PageSize              int
+	RevieweeAccountNumber string
+	Hidden                *bool
+}
+
+func (req *SearchReviewReq) validate() []error {
+	errs := []error{}
+	if req.Page < 1 || req.PageSize < 1 {
+		errs = append(errs, errors.New("Please specify a valid page."))
+	}
+	return errs
+}
+
+// GET /entities
+
+func NewSearchEntityReq(q url.Values) (*SearchEntityReq, error) {
+	page, err := util.ToInt(q.Get("page"), 1)
+	if err != nil {
+		return nil, err
+	}
+	pageSize, err := util.ToInt(q.Get("page_size"), viper.GetInt("page_size"))
+	if err != nil {
+		return nil, err
+	}
+	near, err := parseNear(q.Get("near"))
+	if err != nil {
+		return nil, err
+	}
+	radius, err := util.ToFloat64(q.Get("radius"))
+	if err != nil {
+		return nil, errors.New("Please specify the radius in kilometers.")
+	}
+	if radius == nil && near != nil {
+		defaultRadius := viper.GetFloat64("geocode.default_radius")
+		radius = &defaultRadius
+	}
+	minRating, err := util.ToFloat64(q.Get("min_rating"))
+	if err != nil {
+		return nil, errors.New("Please specify the minimum rating as a number.")
+	}
+	return &SearchEntityReq{
+		QueryingEntityID: q.Get("querying_entity_id"),
+		Page:             page,
+		PageSize:         pageSize,
+		Name:             q.Get("name"),
+		Category:         q.Get("category"),
+		Offers:           util.ToSearchTags(q.Get("offers")),
+		Wants:            util.ToSearchTags(q.Get("wants")),
+		TaggedSince:      util.ParseTime(q.Get("tagged_since")),
+		FavoritesOnly:    q.Get("favorites_only") == "true",
+		AccountNumber:    q.Get("account_number"),
+		Near:             near,
+		Radius:           radius,
+		MinRating:        minRating,
+		Sort:             q.Get("sort"),
+		Statuses: []string{
+			constant.Entity.Accepted,
+			constant.Trading.Pending,
+			constant.Trading.Accepted,
+			constant.Trading.Rejected,
+		},
+	}, nil
+}
+
+type SearchEntityReq struct {
+	QueryingEntityID string
+	Page             int
+	PageSize         int
+	Name             string
+	Wants            []string
+	Offers           []string
+	Category         string
+	FavoriteEntities []primitive.ObjectID
+	FavoritesOnly    bool
+	TaggedSince      time.Time
+	Statuses         []string
+	// Account number or handle
+	AccountNumber string
+
+	Country string
+	City    string
+
+	// Only entities within the radius (km) of the point are returned.
+	Near   *GeoPoint
+	Radius *float64
+
+	// Only entities with at least this average rating are returned.
+	MinRating *float64
+	// "distance" or "rating"
+	Sort string
+}
+
+func (query *SearchEntityReq) Validate() []error {
+	errs := []error{}
+
+	if query.Radius != nil && query.Near == nil {
+		errs = append(errs, errors.New("Please specify near when searching by radius."))
+	}
+	if query.Radius != nil && *query.Radius <= 0 {
+		errs = append(errs, errors.New("The radius should be a positive number."))
+	}
+	if query.MinRating != nil && (*query.MinRating < 1 || *query.MinRating > 5) {
+		errs = append(errs, errors.New("The minimum rating should be between 1 and 5."))
+	}
+	if query.Sort != "" && query.Sort != "distance" && query.Sort != "rating" {
+		errs = append(errs, errors.New("Please specify a valid sort."))
+	}
+	if query.Sort == "distance" && query.Near == nil {
+		errs = append(errs, errors.New("Please specify near when sorting by distance."))
+	}
+
+	if query.FavoritesOnly == true && query.QueryingEntityID == "" {
+		errs = append(errs, errors.New("Please specify the querying_entity_id."))
+	}
+
+	if !query.TaggedSince.IsZero() && len(query.Wants) == 0 && len(query.Offers) == 0 {
+		errs = append(errs, errors.New("Please specify an offer or want tag."))
+	}
+
+	return errs
+}
+
+// parseNear parses a "latitude,longitude" pair.
+func parseNear(near string) (*GeoPoint, error) {
+	if near == "" {
+		return nil, nil
+	}
+	parts := strings.Split(near, ",")
+	if len(parts) != 2 {
+		return nil, errors.New("Please specify near as latitude,longitude.")
+	}
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return nil, errors.New("Please specify near as latitude,longitude.")
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return nil, errors.New("Please specify near as latitude,longitude.")
+	}
+	return &GeoPoint{Lat: latitude, Lon: longitude}, nil
+}
+
+// GET /entities/{entityID}
+
+func NewGetEntityReq(r *http.Request) (*GetEntity, []error) {
+	req := &GetEntity{
+		SearchEntityID:   mux.Vars(r)["searchEntityID"],
+		QueryingEntityID: r.URL.Query().Get("querying_entity_id"),
+	}
+	return req, req.validate()
+}
+
+type GetEntity struct {
+	SearchEntityID   string
+	QueryingEntityID string
+}
+
+func (q *GetEntity) validate() []error {
+	errs := []error{}
+	return errs
+}
+
+func NewSearchTagReq(q url.Values) (*SearchTagReq, error) {
+	page, err := util.ToInt(q.Get("page"), 1)
+	if err != nil {
+		return nil, err
+	}
+	pageSize, err := util.ToInt(q.Get("page_size"), viper.GetInt("page_size"))
+	if err != nil {
+		return nil, err
+	}
+	return &SearchTagReq{
+		Fragment: q.Get("fragment"),
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+type SearchTagReq struct {
 	Fragment string `json:"fragment"`
 	Page     int    `json:"page"`
 	PageSize int    `json:"pageSize"`
@@ -840,6 +1434,384 @@ func (query *BalanceReq) Validate() []error {
 	return errs
 }
 
+// POST /user/entities/{entityID}/invitations
+
+func NewInviteEntityMemberReq(r *http.Request, entity *Entity, invitedBy *User) (*InviteEntityMemberReq, []error) {
+	var req InviteEntityMemberReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	req.Entity = entity
+	req.InvitedBy = invitedBy
+	return &req, req.validate()
+}
+
+type InviteEntityMemberReq struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+
+	Entity    *Entity
+	InvitedBy *User
+}
+
+func (req *InviteEntityMemberReq) validate() []error {
+	errs := util.ValidateEmail(req.Email)
+	if _, ok := constant.EntityRoleRank[req.Role]; !ok {
+		errs = append(errs, errors.New("Please specify a valid role."))
+	}
+	return errs
+}
+
+// PATCH /user/entities/{entityID}/members/{userID}
+
+func NewUpdateEntityMemberReq(r *http.Request, entity *Entity, member *User, updatedBy primitive.ObjectID) (*UpdateEntityMemberReq, []error) {
+	var req UpdateEntityMemberReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req.Entity = entity
+	req.Member = member
+	req.UpdatedBy = updatedBy
+	return &req, req.validate()
+}
+
+type UpdateEntityMemberReq struct {
+	Role string `json:"role"`
+
+	Entity    *Entity
+	Member    *User
+	UpdatedBy primitive.ObjectID
+}
+
+func (req *UpdateEntityMemberReq) validate() []error {
+	errs := []error{}
+	if _, ok := constant.EntityRoleRank[req.Role]; !ok {
+		errs = append(errs, errors.New("Please specify a valid role."))
+	}
+	return errs
+}
+
+// PUT /user/entities/{entityID}/application/answers
+
+func NewUpdateApplicationAnswersReq(r *http.Request, entity *Entity, questions []*ApplicationQuestion) (*UpdateApplicationAnswersReq, []error) {
+	var body struct {
+		Answers []struct {
+			QuestionID string `json:"questionID"`
+			Answer     string `json:"answer"`
+		} `json:"answers"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+
+	byID := map[string]*ApplicationQuestion{}
+	for _, q := range questions {
+		byID[q.ID.Hex()] = q
+	}
+
+	errs := []error{}
+	answered := map[string]bool{}
+	req := &UpdateApplicationAnswersReq{
+		Entity:  entity,
+		Answers: []*ApplicationAnswer{},
+	}
+	for _, a := range body.Answers {
+		question, ok := byID[a.QuestionID]
+		if !ok {
+			errs = append(errs, errors.New("Application question "+a.QuestionID+" does not exist."))
+			continue
+		}
+		if answered[a.QuestionID] {
+			errs = append(errs, errors.New("Question \""+question.Text+"\" is answered more than once."))
+			continue
+		}
+		answered[a.QuestionID] = true
+		answer := strings.TrimSpace(a.Answer)
+		if len(answer) > 2000 {
+			errs = append(errs, errors.New("The answer to \""+question.Text+"\" cannot exceed 2000 characters."))
+			continue
+		}
+		// Empty answers are left out so they count as unanswered.
+		if answer == "" {
+			continue
+		}
+		req.Answers = append(req.Answers, &ApplicationAnswer{
+			QuestionID: question.ID,
+			Question:   question.Text,
+			Answer:     answer,
+		})
+	}
+	return req, errs
+}
+
+type UpdateApplicationAnswersReq struct {
+	Entity  *Entity
+	Answers []*ApplicationAnswer
+}
+
+// POST /user/entities/{entityID}/application/documents
+
+// The content type is detected from the file itself rather than trusted from the upload.
+var applicationDocumentContentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+func NewUploadApplicationDocumentReq(entity *Entity, documentType string, fileName string, data []byte) (*UploadApplicationDocumentReq, []error) {
+	req := &UploadApplicationDocumentReq{
+		Entity:      entity,
+		Type:        documentType,
+		FileName:    strings.TrimSpace(fileName),
+		ContentType: http.DetectContentType(data),
+		Data:        data,
+	}
+	return req, req.validate()
+}
+
+type UploadApplicationDocumentReq struct {
+	Entity      *Entity
+	Type        string
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// Extension returns the file extension the document is stored with.
+func (req *UploadApplicationDocumentReq) Extension() string {
+	return applicationDocumentContentTypes[req.ContentType]
+}
+
+func (req *UploadApplicationDocumentReq) validate() []error {
+	errs := []error{}
+	if !IsValidApplicationDocumentType(req.Type) {
+		errs = append(errs, errors.New("Please specify a valid document type."))
+	}
+	if _, ok := applicationDocumentContentTypes[req.ContentType]; !ok {
+		errs = append(errs, errors.New("Only PDF, JPEG and PNG documents can be uploaded."))
+	}
+	if len(req.FileName) > 255 {
+		errs = append(errs, errors.New("File name length cannot exceed 255 characters."))
+	}
+	return errs
+}
+
+func IsValidApplicationDocumentType(documentType string) bool {
+	return documentType == constant.ApplicationDocument.IncorporationCertificate ||
+		documentType == constant.ApplicationDocument.Identity ||
+		documentType == constant.ApplicationDocument.ProofOfAddress ||
+		documentType == constant.ApplicationDocument.Other
+}
+
+// POST /user/entities/{entityID}/application/submission
+
+func NewSubmitApplicationReq(r *http.Request, entity *Entity) (*SubmitApplicationReq, []error) {
+	var body struct {
+		Comment string `json:"comment"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	// The comment is optional so the body can be empty.
+	if err != nil && err != io.EOF {
+		return nil, []error{err}
+	}
+	req := &SubmitApplicationReq{
+		Entity:  entity,
+		UserID:  r.Header.Get("userID"),
+		Comment: strings.TrimSpace(body.Comment),
+	}
+	return req, req.validate()
+}
+
+type SubmitApplicationReq struct {
+	Entity  *Entity
+	UserID  string
+	Comment string
+}
+
+func (req *SubmitApplicationReq) validate() []error {
+	errs := []error{}
+	if len(req.Comment) > 1000 {
+		errs = append(errs, errors.New("Comment length cannot exceed 1000 characters."))
+	}
+	return errs
+}
+
+// GET /accounts/{accountNumber}/statement.pdf
+
+// NewStatementQuery defaults to the current month. Both dates are inclusive.
+func NewStatementQuery(r *http.Request, entity *Entity) (*StatementReq, []error) {
+	now := time.Now().UTC()
+	from, to, errs := parsePeriod(
+		r.URL.Query(),
+		time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC),
+	)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return &StatementReq{
+		Entity: entity,
+		From:   from,
+		To:     to,
+	}, nil
+}
+
+type StatementReq struct {
+	Entity *Entity
+	From   time.Time
+	// Exclusive.
+	To time.Time
+}
+
+// parsePeriod reads the inclusive "from" and "to" dates and returns the period as [from, to).
+func parsePeriod(q url.Values, defaultFrom time.Time, defaultTo time.Time) (time.Time, time.Time, []error) {
+	from, to := defaultFrom, defaultTo
+	errs := []error{}
+	if q.Get("from") != "" {
+		from = util.ParseTime(q.Get("from"))
+		if from.IsZero() {
+			errs = append(errs, errors.New("Please specify a valid from date."))
+		}
+	}
+	if q.Get("to") != "" {
+		to = util.ParseTime(q.Get("to"))
+		if to.IsZero() {
+			errs = append(errs, errors.New("Please specify a valid to date."))
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if len(errs) == 0 && !from.Before(to) {
+		errs = append(errs, errors.New("The from date should be before the to date."))
+	}
+	return from, to, errs
+}
+
+// GET /transfers/export
+// GET /admin/transfers/export
+
+// NewTransferExportQuery exports the whole history by default. The entity is nil for the system-wide export.
+func NewTransferExportQuery(r *http.Request, entity *Entity) (*TransferExportReq, []error) {
+	from, to, errs := parsePeriod(r.URL.Query(), constant.Date.DefaultFrom, constant.Date.DefaultTo)
+	req := &TransferExportReq{
+		Format: strings.ToLower(r.URL.Query().Get("format")),
+		Entity: entity,
+		From:   from,
+		To:     to,
+	}
+	return req, append(errs, req.validate()...)
+}
+
+type TransferExportReq struct {
+	Format string
+	Entity *Entity
+	From   time.Time
+	// Exclusive.
+	To time.Time
+}
+
+func (req *TransferExportReq) validate() []error {
+	errs := []error{}
+	switch req.Format {
+	case constant.TransferExportFormat.CSV, constant.TransferExportFormat.Beancount:
+	case constant.TransferExportFormat.OFX, constant.TransferExportFormat.QIF, constant.TransferExportFormat.Camt053:
+		// These formats describe the statement of a single account.
+		if req.Entity == nil {
+			errs = append(errs, errors.New("Please specify the account_number for the ofx, qif and camt053 formats."))
+		}
+	default:
+		errs = append(errs, errors.New("Please specify a valid format."))
+	}
+	return errs
+}
+
+// GET /entities/export
+// GET /admin/entities/export
+
+// NewEntityExportReq exports the entities found by the directory search, or by the admin search when adminSearch is not nil.
+// Every page of the search is exported, the page parameters are ignored.
+func NewEntityExportReq(q url.Values, search *SearchEntityReq, adminSearch *AdminSearchEntityReq) (*EntityExportReq, []error) {
+	req := &EntityExportReq{
+		Format:      strings.ToLower(q.Get("format")),
+		Search:      search,
+		AdminSearch: adminSearch,
+	}
+	return req, req.validate()
+}
+
+type EntityExportReq struct {
+	Format      string
+	Search      *SearchEntityReq
+	AdminSearch *AdminSearchEntityReq
+	// The status of the entity searching the directory, its email addresses are only shown to trading members.
+	QueryingEntityStatus string
+}
+
+func (req *EntityExportReq) validate() []error {
+	errs := []error{}
+	switch req.Format {
+	case constant.EntityExportFormat.CSV, constant.EntityExportFormat.VCard, constant.EntityExportFormat.JSON:
+	default:
+		errs = append(errs, errors.New("Please specify a valid format."))
+	}
+	return errs
+}
+
+// GET /feeds/members
+// GET /feeds/tags
+
+func NewFeedReq(q url.Values) (*FeedReq, []error) {
+	format := strings.ToLower(q.Get("format"))
+	if format == "" {
+		format = constant.FeedFormat.Atom
+	}
+	req := &FeedReq{
+		Format:   format,
+		Category: q.Get("category"),
+		Tags:     util.ToSearchTags(q.Get("tags")),
+		TagType:  strings.ToLower(q.Get("type")),
+	}
+	return req, req.validate()
+}
+
+type FeedReq struct {
+	Format   string
+	Category string
+	// Only the entities with one of the offers or wants, or only these tags in the new tags feed.
+	Tags []string
+	// "offer" or "want", both are listed when it is empty.
+	TagType string
+}
+
+func (req *FeedReq) validate() []error {
+	errs := []error{}
+	switch req.Format {
+	case constant.FeedFormat.Atom, constant.FeedFormat.RSS, constant.FeedFormat.JSON:
+	default:
+		errs = append(errs, errors.New("Please specify a valid format."))
+	}
+	if req.TagType != "" && req.TagType != constant.FeedTagType.Offer && req.TagType != constant.FeedTagType.Want {
+		errs = append(errs, errors.New("Please specify a valid type."))
+	}
+	return errs
+}
+
 // Admin
 
 type AdminUpdateCategoryReq struct {
@@ -1219,6 +2191,7 @@ func NewAdminGetEntityReq(r *http.Request) (*AdminGetEntity, []error) {
 
 func NewAdminUpdateEntityReq(j AdminUpdateEntityJSON, originEntity *Entity, originBalanceLimit *BalanceLimit) (*AdminUpdateEntityReq, []error) {
 	errs := j.validate()
+	errs = append(errs, validateStatusTransition(originEntity.Status, j.Status, strings.TrimSpace(j.StatusReason))...)
 	if len(errs) != 0 {
 		return nil, errs
 	}
@@ -1270,10 +2243,19 @@ func NewAdminUpdateEntityReq(j AdminUpdateEntityJSON, originEntity *Entity, orig
 		Region:     j.Region,
 		PostalCode: j.PostalCode,
 		Country:    j.Country,
+		Latitude:   j.Latitude,
+		Longitude:  j.Longitude,
 		// Account
 		MaxPosBal: j.MaxPosBal,
 		MaxNegBal: j.MaxNegBal,
 		Status:    j.Status,
+		Handle:    util.FormatHandle(j.Handle),
+	}
+	if j.Status != "" && j.Status != originEntity.Status {
+		req.StatusReason = strings.TrimSpace(j.StatusReason)
+	} else {
+		// Setting the current status again is not a transition.
+		req.Status = ""
 	}
 
 	return &req, nil
@@ -1283,6 +2265,7 @@ type AdminUpdateEntityReq struct {
 	OriginEntity                       *Entity
 	OriginBalanceLimit                 *BalanceLimit
 	Status                             string
+	StatusReason                       string
 	Name                               string
 	Email                              string
 	Telephone                          string
@@ -1311,89 +2294,498 @@ type AdminUpdateEntityReq struct {
 	Region     string
 	PostalCode string
 	Country    string
+	Latitude   *float64
+	Longitude  *float64
+	// ClearLocation removes the stored coordinates, e.g. when the new postal code could not be geocoded.
+	ClearLocation bool
 	// Account
 	MaxPosBal *float64
 	MaxNegBal *float64
+	Handle    string
+}
+
+type AdminUpdateEntityJSON struct {
+	Status           string    `json:"status"`
+	StatusReason     string    `json:"statusReason"`
+	Name             string    `json:"name"`
+	Email            string    `json:"email"`
+	Telephone        string    `json:"telephone"`
+	IncType          string    `json:"incType"`
+	CompanyNumber    string    `json:"companyNumber"`
+	Website          string    `json:"website"`
+	DeclaredTurnover *int      `json:"declaredTurnover"`
+	Description      string    `json:"description"`
+	Users            *[]string `json:"users"`
+	// Tags
+	Offers     *[]string `json:"offers"`
+	Wants      *[]string `json:"wants"`
+	Categories *[]string `json:"categories"`
+	// Address
+	Address    string   `json:"address"`
+	City       string   `json:"city"`
+	Region     string   `json:"region"`
+	PostalCode string   `json:"postalCode"`
+	Country    string   `json:"country"`
+	Latitude   *float64 `json:"latitude"`
+	Longitude  *float64 `json:"longitude"`
+	// flags
+	ReceiveDailyMatchNotificationEmail *bool `json:"receiveDailyMatchNotificationEmail"`
+	ShowTagsMatchedSinceLastLogin      *bool `json:"showTagsMatchedSinceLastLogin"`
+	// Account
+	MaxPosBal *float64 `json:"maxPositiveBalance"`
+	MaxNegBal *float64 `json:"maxNegativeBalance"`
+	Handle    string   `json:"handle"`
+	// Useless (Do not use it)
+	ID            string `json:"id"`
+	AccountNumber string `json:"accountNumber"`
+}
+
+func (req *AdminUpdateEntityJSON) validate() []error {
+	errs := []error{}
+
+	if req.ID != "" {
+		errs = append(errs, errors.New("The entity ID cannot be changed."))
+	}
+	if req.AccountNumber != "" {
+		errs = append(errs, errors.New("The account number cannot be changed."))
+	}
+	if req.MaxPosBal != nil && *req.MaxPosBal < 0 {
+		errs = append(errs, errors.New("The max positive balance should be positive."))
+	}
+	if req.MaxNegBal != nil && *req.MaxNegBal < 0 {
+		errs = append(errs, errors.New("The max negative balance should be positive."))
+	}
+
+	categories := []string{}
+	if req.Categories != nil {
+		categories = *req.Categories
+	}
+
+	entity := Entity{
+		Email:            req.Email,
+		Name:             req.Name,
+		Telephone:        req.Telephone,
+		IncType:          req.IncType,
+		CompanyNumber:    req.CompanyNumber,
+		Website:          req.Website,
+		DeclaredTurnover: req.DeclaredTurnover,
+		Description:      req.Description,
+		City:             req.City,
+		Country:          req.Country,
+		Address:          req.Address,
+		Region:           req.Region,
+		PostalCode:       req.PostalCode,
+		Latitude:         req.Latitude,
+		Longitude:        req.Longitude,
+		Categories:       categories,
+		Status:           req.Status,
+	}
+	errs = append(errs, entity.Validate()...)
+	if req.Offers != nil {
+		errs = append(errs, validateTags(*req.Offers)...)
+	}
+	if req.Wants != nil {
+		errs = append(errs, validateTags(*req.Wants)...)
+	}
+	errs = append(errs, validateHandle(req.Handle)...)
+
+	return errs
+}
+
+// validateStatusTransition checks the status change requested by an admin. Every change needs a reason.
+func validateStatusTransition(from string, to string, reason string) []error {
+	errs := []error{}
+	if to == "" || to == from {
+		return errs
+	}
+	if util.IsValidStatus(to) && !util.IsAllowedStatusTransition(from, to) {
+		errs = append(errs, errors.New("An entity cannot be moved from "+from+" to "+to+". Allowed statuses: "+strings.Join(util.AllowedStatusTransitions(from), ", ")+"."))
+	}
+	if reason == "" {
+		errs = append(errs, errors.New("Please enter a reason for the status change."))
+	} else if len(reason) > 510 {
+		errs = append(errs, errors.New("Reason length cannot exceed 510 characters."))
+	}
+	return errs
+}
+
+// GET /admin/entities/status-changes
+
+func NewAdminSearchEntityStatusChangeReq(r *http.Request) (*AdminSearchEntityStatusChangeReq, []error) {
+	q := r.URL.Query()
+	page, err := util.ToInt(q.Get("page"), 1)
+	if err != nil {
+		return nil, []error{err}
+	}
+	pageSize, err := util.ToInt(q.Get("page_size"), viper.GetInt("page_size"))
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	req := &AdminSearchEntityStatusChangeReq{
+		Page:     page,
+		PageSize: pageSize,
+		From:     q.Get("from"),
+		To:       q.Get("to"),
+		DateFrom: util.ParseTime(q.Get("date_from")),
+		DateTo:   util.ParseTime(q.Get("date_to")),
+	}
+	if q.Get("entity_id") != "" {
+		req.EntityID, err = primitive.ObjectIDFromHex(q.Get("entity_id"))
+		if err != nil {
+			return nil, []error{errors.New("Please specify a valid entity_id.")}
+		}
+	}
+	return req, req.validate()
+}
+
+type AdminSearchEntityStatusChangeReq struct {
+	Page     int
+	PageSize int
+	EntityID primitive.ObjectID
+	// The status the entities were moved from and to.
+	From     string
+	To       string
+	DateFrom time.Time
+	DateTo   time.Time
+}
+
+func (req *AdminSearchEntityStatusChangeReq) validate() []error {
+	errs := []error{}
+	if req.From != "" && !util.IsValidStatus(req.From) {
+		errs = append(errs, errors.New("Please specify a valid from status."))
+	}
+	if req.To != "" && !util.IsValidStatus(req.To) {
+		errs = append(errs, errors.New("Please specify a valid to status."))
+	}
+	return errs
+}
+
+// POST /admin/application-questions
+// PATCH /admin/application-questions/{questionID}
+
+// NewAdminUpdateApplicationQuestionReq applies the request body to the question.
+// A new question is created from an empty one.
+func NewAdminUpdateApplicationQuestionReq(r *http.Request, question *ApplicationQuestion) (*AdminUpdateApplicationQuestionReq, []error) {
+	var body struct {
+		Text     *string `json:"text"`
+		Required *bool   `json:"required"`
+		Order    *int    `json:"order"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	updated := *question
+	if body.Text != nil {
+		updated.Text = strings.TrimSpace(*body.Text)
+	}
+	if body.Required != nil {
+		updated.Required = *body.Required
+	}
+	if body.Order != nil {
+		updated.Order = *body.Order
+	}
+	req := &AdminUpdateApplicationQuestionReq{
+		Question: &updated,
+	}
+	return req, req.validate()
+}
+
+type AdminUpdateApplicationQuestionReq struct {
+	Question *ApplicationQuestion
+}
+
+func (req *AdminUpdateApplicationQuestionReq) validate() []error {
+	errs := []error{}
+	if req.Question.Text == "" {
+		errs = append(errs, errors.New("Please enter the question."))
+	} else if len(req.Question.Text) > 510 {
+		errs = append(errs, errors.New("Question length cannot exceed 510 characters."))
+	}
+	return errs
+}
+
+// GET /admin/applications
+
+func NewAdminSearchEntityApplicationReq(r *http.Request) (*AdminSearchEntityApplicationReq, []error) {
+	q := r.URL.Query()
+	page, err := util.ToInt(q.Get("page"), 1)
+	if err != nil {
+		return nil, []error{err}
+	}
+	pageSize, err := util.ToInt(q.Get("page_size"), viper.GetInt("page_size"))
+	if err != nil {
+		return nil, []error{err}
+	}
+	req := &AdminSearchEntityApplicationReq{
+		Page:     page,
+		PageSize: pageSize,
+		Status:   q.Get("status"),
+	}
+	return req, req.validate()
+}
+
+type AdminSearchEntityApplicationReq struct {
+	Page     int
+	PageSize int
+	Status   string
+}
+
+func (req *AdminSearchEntityApplicationReq) validate() []error {
+	errs := []error{}
+	if req.Page < 1 || req.PageSize < 1 {
+		errs = append(errs, errors.New("Please specify a valid page."))
+	}
+	if req.Status != "" &&
+		req.Status != constant.EntityApplication.Draft &&
+		req.Status != constant.EntityApplication.Submitted &&
+		req.Status != constant.EntityApplication.InfoRequested &&
+		req.Status != constant.EntityApplication.Approved &&
+		req.Status != constant.EntityApplication.Rejected {
+		errs = append(errs, errors.New("Please specify a valid status."))
+	}
+	return errs
+}
+
+// POST /admin/entities/{entityID}/application/review
+
+// NewAdminReviewApplicationReq validates the decision and the entity status it leads to.
+// Approving and rejecting move the entity through the same status transition as PATCH /admin/entities/{entityID}.
+func NewAdminReviewApplicationReq(r *http.Request, entity *Entity, balanceLimit *BalanceLimit) (*AdminReviewApplicationReq, []error) {
+	var body struct {
+		Decision string `json:"decision"`
+		Comment  string `json:"comment"`
+		Status   string `json:"status"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+
+	req := &AdminReviewApplicationReq{
+		Entity:   entity,
+		Decision: body.Decision,
+		Comment:  strings.TrimSpace(body.Comment),
+		AdminID:  r.Header.Get("userID"),
+	}
+	errs := req.validate()
+	if len(errs) != 0 {
+		return nil, errs
+	}
+
+	status := body.Status
+	switch req.Decision {
+	case constant.ApplicationDecision.RequestInfo:
+		req.ApplicationStatus = constant.EntityApplication.InfoRequested
+		if status != "" {
+			return nil, []error{errors.New("The entity status cannot be changed when more information is requested.")}
+		}
+		return req, nil
+	case constant.ApplicationDecision.Approve:
+		req.ApplicationStatus = constant.EntityApplication.Approved
+		if status == "" {
+			status = constant.Entity.Accepted
+		}
+		if status != constant.Entity.Accepted && status != constant.Trading.Accepted {
+			return nil, []error{errors.New("An approved entity can only be moved to accepted or tradingAccepted.")}
+		}
+	case constant.ApplicationDecision.Reject:
+		req.ApplicationStatus = constant.EntityApplication.Rejected
+		if status == "" {
+			status = constant.Entity.Rejected
+			// Entities that already applied for trading lose the trading status only.
+			if entity.Status == constant.Trading.Pending || entity.Status == constant.Trading.Accepted || entity.Status == constant.Trading.Rejected {
+				status = constant.Trading.Rejected
+			}
+		}
+		if status != constant.Entity.Rejected && status != constant.Trading.Rejected {
+			return nil, []error{errors.New("A rejected entity can only be moved to rejected or tradingRejected.")}
+		}
+	}
+
+	req.EntityUpdate, errs = NewAdminUpdateEntityReq(AdminUpdateEntityJSON{
+		Status:       status,
+		StatusReason: req.Comment,
+	}, entity, balanceLimit)
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return req, nil
 }
 
-type AdminUpdateEntityJSON struct {
-	Status           string    `json:"status"`
-	Name             string    `json:"name"`
-	Email            string    `json:"email"`
-	Telephone        string    `json:"telephone"`
-	IncType          string    `json:"incType"`
-	CompanyNumber    string    `json:"companyNumber"`
-	Website          string    `json:"website"`
-	DeclaredTurnover *int      `json:"declaredTurnover"`
-	Description      string    `json:"description"`
-	Users            *[]string `json:"users"`
-	// Tags
-	Offers     *[]string `json:"offers"`
-	Wants      *[]string `json:"wants"`
-	Categories *[]string `json:"categories"`
-	// Address
-	Address    string `json:"address"`
-	City       string `json:"city"`
-	Region     string `json:"region"`
-	PostalCode string `json:"postalCode"`
-	Country    string `json:"country"`
-	// flags
-	ReceiveDailyMatchNotificationEmail *bool `json:"receiveDailyMatchNotificationEmail"`
-	ShowTagsMatchedSinceLastLogin      *bool `json:"showTagsMatchedSinceLastLogin"`
-	// Account
-	MaxPosBal *float64 `json:"maxPositiveBalance"`
-	MaxNegBal *float64 `json:"maxNegativeBalance"`
-	// Useless (Do not use it)
-	ID            string `json:"id"`
-	AccountNumber string `json:"accountNumber"`
+type AdminReviewApplicationReq struct {
+	Entity            *Entity
+	Decision          string
+	Comment           string
+	AdminID           string
+	ApplicationStatus string
+	// The status change of the entity. It is nil when more information is requested
+	// and its status is empty when the entity is already in the status.
+	EntityUpdate *AdminUpdateEntityReq
 }
 
-func (req *AdminUpdateEntityJSON) validate() []error {
+func (req *AdminReviewApplicationReq) validate() []error {
 	errs := []error{}
+	if req.Decision != constant.ApplicationDecision.RequestInfo &&
+		req.Decision != constant.ApplicationDecision.Approve &&
+		req.Decision != constant.ApplicationDecision.Reject {
+		errs = append(errs, errors.New("Please specify a valid decision."))
+	}
+	if req.Comment == "" {
+		errs = append(errs, errors.New("Please enter a comment for the entity."))
+	} else if len(req.Comment) > 510 {
+		errs = append(errs, errors.New("Comment length cannot exceed 510 characters."))
+	}
+	return errs
+}
 
-	if req.ID != "" {
-		errs = append(errs, errors.New("The entity ID cannot be changed."))
+// POST /admin/entities/imports
+
+// NewEntityImportReq reads the user of an imported row. The entity is only read when withEntity is true,
+// the other rows of the entity only add their user to it.
+func NewEntityImportReq(row *EntityImportRow, withEntity bool) (*EntityImportReq, []error) {
+	errs := []error{}
+	req := &EntityImportReq{
+		Row: row,
+		User: &User{
+			Email:     strings.ToLower(row.UserEmail),
+			FirstName: row.FirstName,
+			LastName:  row.LastName,
+			Telephone: row.UserPhone,
+		},
 	}
-	if req.AccountNumber != "" {
-		errs = append(errs, errors.New("The account number cannot be changed."))
+	if row.EntityName == "" {
+		errs = append(errs, errors.New("Entity name is empty."))
+	}
+	if row.UserEmail == "" {
+		errs = append(errs, errors.New("User email is empty."))
+	}
+	errs = append(errs, req.User.Validate()...)
+	if !withEntity {
+		return req, errs
+	}
+
+	req.Entity = &Entity{
+		Name:          row.EntityName,
+		Email:         strings.ToLower(row.EntityEmail),
+		IncType:       row.IncType,
+		CompanyNumber: row.CompanyNumber,
+		Telephone:     row.EntityPhone,
+		Website:       row.Website,
+		Description:   row.Description,
+		Address:       row.Address,
+		City:          row.City,
+		Region:        row.Region,
+		PostalCode:    row.PostalCode,
+		Country:       row.Country,
+		Status:        row.EntityStatus,
+		Categories:    util.FormatTags(splitImportList(row.Categories)),
+	}
+	// Use the user email address for the entity like the signup does.
+	if req.Entity.Email == "" {
+		req.Entity.Email = req.User.Email
+	}
+	if req.Entity.Status == "" {
+		req.Entity.Status = constant.Entity.Pending
+	}
+	if row.DeclaredTurnover != "" {
+		turnover, err := strconv.Atoi(row.DeclaredTurnover)
+		if err != nil {
+			errs = append(errs, errors.New("Declared turnover should be a whole number."))
+		} else {
+			req.Entity.DeclaredTurnover = &turnover
+		}
 	}
-	if req.MaxPosBal != nil && *req.MaxPosBal < 0 {
-		errs = append(errs, errors.New("The max positive balance should be positive."))
+	offers := splitImportList(row.Offers)
+	wants := splitImportList(row.Wants)
+	errs = append(errs, validateTags(offers)...)
+	errs = append(errs, validateTags(wants)...)
+	req.Entity.Offers = ToTagFields(util.FormatTags(offers))
+	req.Entity.Wants = ToTagFields(util.FormatTags(wants))
+	errs = append(errs, req.Entity.Validate()...)
+
+	if row.MaxPosBal != "" {
+		maxPosBal, err := strconv.ParseFloat(row.MaxPosBal, 64)
+		if err != nil {
+			errs = append(errs, errors.New("The max positive balance should be a number."))
+		} else if maxPosBal < 0 {
+			errs = append(errs, errors.New("The max positive balance should be positive."))
+		} else {
+			req.MaxPosBal = &maxPosBal
+		}
 	}
-	if req.MaxNegBal != nil && *req.MaxNegBal < 0 {
-		errs = append(errs, errors.New("The max negative balance should be positive."))
+	if row.MaxNegBal != "" {
+		maxNegBal, err := strconv.ParseFloat(row.MaxNegBal, 64)
+		if err != nil {
+			errs = append(errs, errors.New("The max negative balance should be a number."))
+		} else if maxNegBal < 0 {
+			errs = append(errs, errors.New("The max negative balance should be positive."))
+		} else {
+			req.MaxNegBal = &maxNegBal
+		}
 	}
+	return req, errs
+}
 
-	categories := []string{}
-	if req.Categories != nil {
-		categories = *req.Categories
+// splitImportList splits the offers, wants and categories cells, which are separated by semicolons.
+func splitImportList(cell string) []string {
+	list := []string{}
+	for _, item := range strings.Split(cell, ";") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
 	}
+	return list
+}
 
-	entity := Entity{
-		Email:            req.Email,
-		Name:             req.Name,
-		Telephone:        req.Telephone,
-		IncType:          req.IncType,
-		CompanyNumber:    req.CompanyNumber,
-		Website:          req.Website,
-		DeclaredTurnover: req.DeclaredTurnover,
-		Description:      req.Description,
-		City:             req.City,
-		Country:          req.Country,
-		Address:          req.Address,
-		Region:           req.Region,
-		PostalCode:       req.PostalCode,
-		Categories:       categories,
-		Status:           req.Status,
+type EntityImportReq struct {
+	Row *EntityImportRow
+	// Entity is nil when the row adds its user to the entity of a previous row.
+	Entity    *Entity
+	User      *User
+	MaxPosBal *float64
+	MaxNegBal *float64
+}
+
+// POST /admin/entities/imports/{importID}/execution
+
+func NewAdminExecuteEntityImportReq(r *http.Request, record *EntityImport) (*AdminExecuteEntityImportReq, []error) {
+	var body struct {
+		SendInvitations bool `json:"sendInvitations"`
 	}
-	errs = append(errs, entity.Validate()...)
-	if req.Offers != nil {
-		errs = append(errs, validateTags(*req.Offers)...)
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	// The body is optional, the invitations are not sent by default.
+	if err != nil && err != io.EOF {
+		return nil, []error{err}
 	}
-	if req.Wants != nil {
-		errs = append(errs, validateTags(*req.Wants)...)
+	req := &AdminExecuteEntityImportReq{
+		Import:          record,
+		SendInvitations: body.SendInvitations,
 	}
+	return req, req.validate()
+}
+
+type AdminExecuteEntityImportReq struct {
+	Import          *EntityImport
+	SendInvitations bool
+}
 
+func (req *AdminExecuteEntityImportReq) validate() []error {
+	errs := []error{}
+	if req.Import.Status != constant.EntityImport.Validated {
+		errs = append(errs, errors.New("This import has already been executed."))
+	}
 	return errs
 }
 
@@ -1420,13 +2812,13 @@ func NewAdminDeleteEntity(r *http.Request) (*AdminDeleteEntity, []error) {
 
 // POST /admin/transfers
 
-func NewAdminTransferReq(userReq *AdminTransferUserReq, payerEntity *Entity, payeeEntity *Entity) (*AdminTransferReq, []error) {
+func NewAdminTransferReq(userReq *AdminTransferUserReq, payerEntity *Entity, payeeEntity *Entity, transferType string) (*AdminTransferReq, []error) {
 	req := &AdminTransferReq{
 		PayerEntity:  payerEntity,
 		PayeeEntity:  payeeEntity,
-		TransferType: constant.TransferType.AdminTransfer,
+		TransferType: transferType,
 		Amount:       userReq.Amount,
-		Description:  userReq.Description,
+		Description:  strings.TrimSpace(userReq.Description),
 	}
 	return req, req.Validate()
 }
@@ -1449,16 +2841,32 @@ type AdminTransferReq struct {
 func (req *AdminTransferReq) Validate() []error {
 	errs := []error{}
 
+	rule, ok := constant.TransferTypeRules[req.TransferType]
+	if !ok {
+		return []error{errors.New("Unknown transfer type.")}
+	}
+	if !rule.AdminOnly {
+		return []error{errors.New("Admins cannot create " + req.TransferType + " journals.")}
+	}
+
 	// Amount should be positive value and with up to two decimal places.
 	if req.Amount <= 0 || !util.IsDecimalValid(req.Amount) {
 		errs = append(errs, errors.New("Please enter a valid numeric amount to send with up to two decimal places."))
 	}
+	if rule.RequireReason && req.Description == "" {
+		errs = append(errs, errors.New("Please explain the reason for the "+req.TransferType+" in the description."))
+	}
+	if len(req.Description) > 510 {
+		errs = append(errs, errors.New("Description length cannot exceed 510 characters."))
+	}
 
 	// Only allow transfers with accounts that also have "trading-accepted" status
-	if req.PayerEntity.Status != constant.Trading.Accepted {
-		errs = append(errs, errors.New("Sender is not a trading member. Transfers can only be made when both entities have trading member status."))
-	} else if req.PayeeEntity.Status != constant.Trading.Accepted {
-		errs = append(errs, errors.New("Recipient is not a trading member. Transfers can only be made when both entities have trading member status."))
+	if rule.RequireTradingMembers {
+		if req.PayerEntity.Status != constant.Trading.Accepted {
+			errs = append(errs, errors.New("Sender is not a trading member. Transfers can only be made when both entities have trading member status."))
+		} else if req.PayeeEntity.Status != constant.Trading.Accepted {
+			errs = append(errs, errors.New("Recipient is not a trading member. Transfers can only be made when both entities have trading member status."))
+		}
 	}
 
 	// Check if the user is doing the transaction to himself.
@@ -1487,6 +2895,137 @@ func (req *AdminGetTransfer) validate() []error {
 	return errs
 }
 
+// PATCH /admin/transfers/{transferID}
+
+func NewAdminUpdateTransferReq(r *http.Request, journal *Journal) (*AdminUpdateTransferReq, []error) {
+	var body struct {
+		Action string `json:"action"`
+		Reason string `json:"reason"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req := &AdminUpdateTransferReq{
+		Journal: journal,
+		Action:  body.Action,
+		Reason:  strings.TrimSpace(body.Reason),
+	}
+	return req, req.validate()
+}
+
+type AdminUpdateTransferReq struct {
+	Journal *Journal
+	Action  string
+	Reason  string
+}
+
+func (req *AdminUpdateTransferReq) validate() []error {
+	errs := []error{}
+	switch req.Action {
+	case "release", "refund":
+		if req.Journal.Status != constant.Transfer.Escrowed {
+			errs = append(errs, errors.New("Only transfers held in escrow can be released or refunded."))
+		}
+	case "approve", "reject":
+		if req.Journal.Status != constant.Transfer.PendingApproval {
+			errs = append(errs, errors.New("Only transfers pending approval can be approved or rejected."))
+		}
+	default:
+		errs = append(errs, errors.New("Please enter a valid action."))
+	}
+	if len(req.Reason) > 510 {
+		errs = append(errs, errors.New("Reason length cannot exceed 510 characters."))
+	}
+	return errs
+}
+
+// PATCH /admin/reviews/{reviewID}
+
+func NewAdminUpdateReviewReq(r *http.Request, review *Review) (*AdminUpdateReviewReq, []error) {
+	var body struct {
+		Hidden         *bool  `json:"hidden"`
+		ModerationNote string `json:"moderationNote"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req := &AdminUpdateReviewReq{
+		Review:         review,
+		Hidden:         body.Hidden,
+		ModerationNote: strings.TrimSpace(body.ModerationNote),
+		AdminID:        r.Header.Get("userID"),
+	}
+	return req, req.validate()
+}
+
+type AdminUpdateReviewReq struct {
+	Review         *Review
+	Hidden         *bool
+	ModerationNote string
+	AdminID        string
+}
+
+func (req *AdminUpdateReviewReq) validate() []error {
+	errs := []error{}
+	if req.Hidden == nil {
+		errs = append(errs, errors.New("Please specify whether the review is hidden."))
+	}
+	if len(req.ModerationNote) > 510 {
+		errs = append(errs, errors.New("Moderation note length cannot exceed 510 characters."))
+	}
+	return errs
+}
+
+// POST /admin/transfers/imports/{importID}/execution
+
+func NewAdminExecuteTransferImportReq(r *http.Request, record *TransferImport) (*AdminExecuteTransferImportReq, []error) {
+	var body struct {
+		Mode string `json:"mode"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req := &AdminExecuteTransferImportReq{
+		Import: record,
+		Mode:   body.Mode,
+	}
+	return req, req.validate()
+}
+
+type AdminExecuteTransferImportReq struct {
+	Import *TransferImport
+	Mode   string
+}
+
+func (req *AdminExecuteTransferImportReq) validate() []error {
+	errs := []error{}
+	if req.Mode != constant.TransferImportMode.Atomic && req.Mode != constant.TransferImportMode.PerRow {
+		errs = append(errs, errors.New("Mode can be only 'atomic' or 'perRow'."))
+	}
+	if req.Import.Status != constant.TransferImport.Validated {
+		errs = append(errs, errors.New("This import has already been executed."))
+	}
+	if req.Mode == constant.TransferImportMode.Atomic && req.Import.NumberOfTransferredRows() > 0 {
+		errs = append(errs, errors.New("Some rows of this import have already been transferred, it can only be executed 'perRow'."))
+	}
+	return errs
+}
+
 // GET /admin/transfers
 
 func NewAdminSearchTransferQuery(r *http.Request) (*AdminSearchTransferReq, []error) {
@@ -1507,6 +3046,7 @@ func NewAdminSearchTransferQuery(r *http.Request) (*AdminSearchTransferReq, []er
 		PageSize:      pageSize,
 		Offset:        (page - 1) * pageSize,
 		Status:        getStatus(q.Get("status")),
+		Types:         getTransferTypes(q.Get("type")),
 		AccountNumber: q.Get("account_number"),
 		DateFrom:      dateFrom,
 		DateTo:        dateTo,
@@ -1520,6 +3060,7 @@ type AdminSearchTransferReq struct {
 	PageSize      int
 	Offset        int
 	Status        []string
+	Types         []string
 	AccountNumber string
 	DateFrom      time.Time
 	DateTo        time.Time
@@ -1528,10 +3069,15 @@ type AdminSearchTransferReq struct {
 func (req *AdminSearchTransferReq) validate() []error {
 	errs := []error{}
 	for _, s := range req.Status {
-		if s != "initiated" && s != "completed" && s != "cancelled" {
+		if s != "initiated" && s != "completed" && s != "cancelled" && s != "escrowed" && s != "pendingapproval" {
 			errs = append(errs, errors.New("Please specify valid status."))
 		}
 	}
+	for _, t := range req.Types {
+		if !constant.IsValidTransferType(t) {
+			errs = append(errs, errors.New("Please specify valid type."))
+		}
+	}
 	return errs
 }
 
@@ -1542,6 +3088,143 @@ func getStatus(input string) []string {
 	return strings.FieldsFunc(strings.ToLower(input), splitFn)
 }
 
+// Transfer types are camel case so they are not lowercased.
+func getTransferTypes(input string) []string {
+	splitFn := func(c rune) bool {
+		return c == ',' || c == ' '
+	}
+	return strings.FieldsFunc(input, splitFn)
+}
+
+// PATCH /admin/accounts/{accountNumber}/freeze
+
+func NewAdminFreezeAccountReq(userReq *AdminFreezeAccountUserReq, originAccount *Account) (*AdminFreezeAccountReq, []error) {
+	req := &AdminFreezeAccountReq{
+		OriginAccount:   originAccount,
+		FrozenSending:   userReq.Sending,
+		FrozenReceiving: userReq.Receiving,
+		Reason:          strings.TrimSpace(userReq.Reason),
+	}
+	if userReq.Until != "" {
+		until := util.ParseTime(userReq.Until)
+		req.FrozenUntil = &until
+	}
+	return req, req.Validate()
+}
+
+type AdminFreezeAccountUserReq struct {
+	Sending   bool   `json:"sending"`
+	Receiving bool   `json:"receiving"`
+	Reason    string `json:"reason"`
+	Until     string `json:"until"`
+}
+
+type AdminFreezeAccountReq struct {
+	OriginAccount   *Account
+	FrozenSending   bool
+	FrozenReceiving bool
+	Reason          string
+	FrozenUntil     *time.Time
+}
+
+func (req *AdminFreezeAccountReq) Validate() []error {
+	errs := []error{}
+
+	if !req.FrozenSending && !req.FrozenReceiving {
+		// Unfreezing the account clears the reason and the end date.
+		req.Reason = ""
+		req.FrozenUntil = nil
+		return errs
+	}
+
+	if req.Reason == "" {
+		errs = append(errs, errors.New("Please enter the reason for freezing the account."))
+	} else if len(req.Reason) > 510 {
+		errs = append(errs, errors.New("Reason length cannot exceed 510 characters."))
+	}
+	if req.FrozenUntil != nil && !req.FrozenUntil.After(time.Now()) {
+		errs = append(errs, errors.New("Please enter a valid end date in the future."))
+	}
+
+	return errs
+}
+
+// POST /admin/accounts/{accountNumber}/opening-balance
+
+func NewAdminOpeningBalanceReq(userReq *AdminOpeningBalanceUserReq, entity *Entity) (*AdminOpeningBalanceReq, []error) {
+	req := &AdminOpeningBalanceReq{
+		Entity:      entity,
+		Amount:      userReq.Amount,
+		Description: strings.TrimSpace(userReq.Description),
+	}
+	return req, req.Validate()
+}
+
+type AdminOpeningBalanceUserReq struct {
+	// Negative amounts are debts migrated from the previous system.
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description"`
+}
+
+type AdminOpeningBalanceReq struct {
+	Entity      *Entity
+	Amount      float64
+	Description string
+}
+
+func (req *AdminOpeningBalanceReq) Validate() []error {
+	errs := []error{}
+
+	if req.Amount == 0 || !util.IsDecimalValid(req.Amount) {
+		errs = append(errs, errors.New("Please enter a valid non-zero amount with up to two decimal places."))
+	}
+	if constant.TransferTypeRules[constant.TransferType.OpeningBalance].RequireReason && req.Description == "" {
+		errs = append(errs, errors.New("Please explain the reason for the openingBalance in the description."))
+	}
+	if len(req.Description) > 510 {
+		errs = append(errs, errors.New("Description length cannot exceed 510 characters."))
+	}
+
+	return errs
+}
+
+// POST /admin/accounts/{accountNumber}/closure
+
+func NewAdminCloseAccountReq(userReq *AdminCloseAccountUserReq, account *Account, entity *Entity, settlementEntity *Entity) (*AdminCloseAccountReq, []error) {
+	req := &AdminCloseAccountReq{
+		Account:          account,
+		Entity:           entity,
+		SettlementEntity: settlementEntity,
+		Reason:           strings.TrimSpace(userReq.Reason),
+	}
+	return req, req.Validate()
+}
+
+type AdminCloseAccountUserReq struct {
+	SettlementAccountNumber string `json:"settlementAccountNumber"`
+	Reason                  string `json:"reason"`
+}
+
+type AdminCloseAccountReq struct {
+	Account          *Account
+	Entity           *Entity
+	SettlementEntity *Entity // Optional
+	Reason           string
+}
+
+func (req *AdminCloseAccountReq) Validate() []error {
+	errs := []error{}
+
+	if len(req.Reason) > 510 {
+		errs = append(errs, errors.New("Reason length cannot exceed 510 characters."))
+	}
+	if req.SettlementEntity != nil && req.SettlementEntity.AccountNumber == req.Account.AccountNumber {
+		errs = append(errs, errors.New("The residual balance cannot be settled to the account being closed."))
+	}
+
+	return errs
+}
+
 // GET /admin/logs
 
 func NewAdminSearchLog(r *http.Request) (*AdminSearchLogReq, []error) {
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ic3network/mccs-alpha-api/global/constant"
@@ -43,6 +45,7 @@ func NewEntityRespond(
 	return &EntityRespond{
 		ID:                                 entity.ID.Hex(),
 		AccountNumber:                      entity.AccountNumber,
+		Handle:                             entity.Handle,
 		Name:                               entity.Name,
 		Email:                              entity.Email,
 		Telephone:                          entity.Telephone,
@@ -56,22 +59,31 @@ func NewEntityRespond(
 		Region:                             entity.Region,
 		PostalCode:                         entity.PostalCode,
 		Country:                            entity.Country,
+		Latitude:                           entity.Latitude,
+		Longitude:                          entity.Longitude,
 		Status:                             entity.Status,
 		ShowTagsMatchedSinceLastLogin:      util.ToBool(entity.ShowTagsMatchedSinceLastLogin),
 		ReceiveDailyMatchNotificationEmail: util.ToBool(entity.ReceiveDailyMatchNotificationEmail),
 		Offers:                             TagFieldToNames(entity.Offers),
 		Wants:                              TagFieldToNames(entity.Wants),
 		Categories:                         entity.Categories,
+		Logo:                               NewImageRespond(entity.Logo),
+		Gallery:                            NewImagesRespond(entity.Gallery),
+		Rating:                             entity.Rating,
+		ReviewCount:                        entity.ReviewCount,
 		Balance:                            account.Balance,
 		MaxNegativeBalance:                 balanceLimit.MaxNegBal,
 		MaxPositiveBalance:                 balanceLimit.MaxPosBal,
 		PendingTransfers:                   pendingTransfers,
+		Freeze:                             NewFreezeRespond(account),
+		AutoAccept:                         entity.AutoAccept,
 	}
 }
 
 type EntityRespond struct {
 	ID                                 string             `json:"id"`
 	AccountNumber                      string             `json:"accountNumber"`
+	Handle                             string             `json:"handle,omitempty"`
 	Name                               string             `json:"name"`
 	Email                              string             `json:"email,omitempty"`
 	Telephone                          string             `json:"telephone"`
@@ -85,16 +97,151 @@ type EntityRespond struct {
 	Region                             string             `json:"region"`
 	PostalCode                         string             `json:"postalCode"`
 	Country                            string             `json:"country"`
+	Latitude                           *float64           `json:"latitude,omitempty"`
+	Longitude                          *float64           `json:"longitude,omitempty"`
 	Status                             string             `json:"status"`
 	ShowTagsMatchedSinceLastLogin      bool               `json:"showTagsMatchedSinceLastLogin"`
 	ReceiveDailyMatchNotificationEmail bool               `json:"receiveDailyMatchNotificationEmail"`
 	Offers                             []string           `json:"offers"`
 	Wants                              []string           `json:"wants"`
 	Categories                         []string           `json:"categories"`
+	Logo                               *ImageRespond      `json:"logo,omitempty"`
+	Gallery                            []*ImageRespond    `json:"gallery"`
+	Rating                             *float64           `json:"rating,omitempty"`
+	ReviewCount                        int                `json:"reviewCount"`
 	Balance                            float64            `json:"balance"`
 	MaxPositiveBalance                 float64            `json:"maxPositiveBalance"`
 	MaxNegativeBalance                 float64            `json:"maxNegativeBalance"`
 	PendingTransfers                   []*TransferRespond `json:"pendingTransfers"`
+	Freeze                             *FreezeRespond     `json:"freeze,omitempty"`
+	AutoAccept                         *AutoAcceptRules   `json:"autoAccept,omitempty"`
+}
+
+func NewImageRespond(image *EntityImage) *ImageRespond {
+	if image == nil {
+		return nil
+	}
+	return &ImageRespond{
+		ID:           image.ID,
+		URL:          image.URL,
+		ThumbnailURL: image.ThumbnailURL,
+	}
+}
+
+func NewImagesRespond(images []*EntityImage) []*ImageRespond {
+	result := []*ImageRespond{}
+	for _, image := range images {
+		result = append(result, NewImageRespond(image))
+	}
+	return result
+}
+
+type ImageRespond struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailURL"`
+}
+
+func NewFreezeRespond(account *Account) *FreezeRespond {
+	if !account.IsSendingFrozen() && !account.IsReceivingFrozen() {
+		return nil
+	}
+	return &FreezeRespond{
+		Sending:   account.IsSendingFrozen(),
+		Receiving: account.IsReceivingFrozen(),
+		Reason:    account.FreezeReason,
+		Until:     account.FrozenUntil,
+	}
+}
+
+type FreezeRespond struct {
+	Sending   bool       `json:"sending"`
+	Receiving bool       `json:"receiving"`
+	Reason    string     `json:"reason"`
+	Until     *time.Time `json:"until,omitempty"`
+}
+
+// GET /user/entities/{entityID}/payees
+
+func NewPayeeRespond(payee *Payee) *PayeeRespond {
+	res := &PayeeRespond{
+		ID:                 payee.ID.Hex(),
+		Nickname:           payee.Nickname,
+		AccountNumber:      payee.AccountNumber,
+		DefaultDescription: payee.DefaultDescription,
+		DefaultAmount:      payee.DefaultAmount,
+	}
+	if !payee.LastUsedAt.IsZero() {
+		res.LastUsedAt = &payee.LastUsedAt
+	}
+	return res
+}
+
+func NewPayeesRespond(payees []*Payee) []*PayeeRespond {
+	res := []*PayeeRespond{}
+	for _, payee := range payees {
+		res = append(res, NewPayeeRespond(payee))
+	}
+	return res
+}
+
+type PayeeRespond struct {
+	ID                 string     `json:"id"`
+	Nickname           string     `json:"nickname"`
+	AccountNumber      string     `json:"accountNumber"`
+	DefaultDescription string     `json:"defaultDescription"`
+	DefaultAmount      float64    `json:"defaultAmount"`
+	LastUsedAt         *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// POST /vouchers
+
+func NewVoucherRespond(voucher *Voucher, publicKey string) *VoucherRespond {
+	res := &VoucherRespond{
+		ID:                 voucher.Nonce,
+		Code:               voucher.Code(),
+		PublicKey:          publicKey,
+		PayerAccountNumber: voucher.PayerAccountNumber,
+		PayerEntityName:    voucher.PayerEntityName,
+		Amount:             voucher.Amount,
+		Description:        voucher.Description,
+		Status:             voucher.Status,
+		CreatedAt:          voucher.CreatedAt,
+		ExpiresAt:          voucher.ExpiresAt,
+		RedeemedBy:         voucher.RedeemedBy,
+		TransferID:         voucher.TransferID,
+		RedeemedAt:         voucher.RedeemedAt,
+		CancelledAt:        voucher.CancelledAt,
+	}
+	if voucher.Status == constant.Voucher.Issued && voucher.IsExpired() {
+		res.Status = constant.Voucher.Expired
+	}
+	return res
+}
+
+func NewVouchersRespond(vouchers []*Voucher, publicKey string) []*VoucherRespond {
+	res := []*VoucherRespond{}
+	for _, voucher := range vouchers {
+		res = append(res, NewVoucherRespond(voucher, publicKey))
+	}
+	return res
+}
+
+type VoucherRespond struct {
+	ID                 string     `json:"id"`
+	Code               string     `json:"code"`
+	PublicKey          string     `json:"publicKey"`
+	PayerAccountNumber string     `json:"payerAccountNumber"`
+	PayerEntityName    string     `json:"payerEntityName"`
+	Amount             float64    `json:"amount"`
+	Description        string     `json:"description"`
+	Status             string     `json:"status"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	ExpiresAt          time.Time  `json:"expiresAt"`
+	RedeemedBy         string     `json:"redeemedBy,omitempty"`
+	TransferID         string     `json:"transferID,omitempty"`
+	RedeemedAt         *time.Time `json:"redeemedAt,omitempty"`
+	CancelledAt        *time.Time `json:"cancelledAt,omitempty"`
 }
 
 // GET /entities
@@ -107,6 +254,7 @@ func NewSearchEntityRespond(entity *Entity, queryingEntityStatus string, favorit
 	return &SearchEntityRespond{
 		ID:               entity.ID.Hex(),
 		AccountNumber:    entity.AccountNumber,
+		Handle:           entity.Handle,
 		Name:             entity.Name,
 		Email:            email,
 		Telephone:        entity.Telephone,
@@ -120,17 +268,93 @@ func NewSearchEntityRespond(entity *Entity, queryingEntityStatus string, favorit
 		Region:           entity.Region,
 		PostalCode:       entity.PostalCode,
 		Country:          entity.Country,
+		Latitude:         entity.Latitude,
+		Longitude:        entity.Longitude,
 		Status:           entity.Status,
 		Offers:           TagFieldToNames(entity.Offers),
 		Wants:            TagFieldToNames(entity.Wants),
 		Categories:       entity.Categories,
+		Logo:             NewImageRespond(entity.Logo),
+		Gallery:          NewImagesRespond(entity.Gallery),
+		Rating:           entity.Rating,
+		ReviewCount:      entity.ReviewCount,
 		IsFavorite:       util.ContainID(favoriteEntities, entity.ID.Hex()),
 	}
 }
 
 type SearchEntityRespond struct {
+	ID               string          `json:"id"`
+	AccountNumber    string          `json:"accountNumber"`
+	Handle           string          `json:"handle,omitempty"`
+	Name             string          `json:"name"`
+	Email            string          `json:"email,omitempty"`
+	Telephone        string          `json:"telephone"`
+	IncType          string          `json:"incType"`
+	CompanyNumber    string          `json:"companyNumber"`
+	Website          string          `json:"website"`
+	DeclaredTurnover *int            `json:"declaredTurnover"`
+	Description      string          `json:"description"`
+	Address          string          `json:"address"`
+	City             string          `json:"city"`
+	Region           string          `json:"region"`
+	PostalCode       string          `json:"postalCode"`
+	Country          string          `json:"country"`
+	Latitude         *float64        `json:"latitude,omitempty"`
+	Longitude        *float64        `json:"longitude,omitempty"`
+	Status           string          `json:"status"`
+	Offers           []string        `json:"offers"`
+	Wants            []string        `json:"wants"`
+	Categories       []string        `json:"categories"`
+	Logo             *ImageRespond   `json:"logo,omitempty"`
+	Gallery          []*ImageRespond `json:"gallery"`
+	Rating           *float64        `json:"rating,omitempty"`
+	ReviewCount      int             `json:"reviewCount"`
+	IsFavorite       bool            `json:"isFavorite"`
+	// Distance in kilometers from the point of the search.
+	Distance *float64 `json:"distance,omitempty"`
+}
+
+// GET /entities/export
+
+// NewEntityExportRespond has the public fields of the entity, like the directory search.
+func NewEntityExportRespond(entity *Entity, queryingEntityStatus string) *EntityExportRespond {
+	email := ""
+	if util.IsTradingAccepted(entity.Status) && util.IsTradingAccepted(queryingEntityStatus) {
+		email = entity.Email
+	}
+	return &EntityExportRespond{
+		ID:               entity.ID.Hex(),
+		AccountNumber:    entity.AccountNumber,
+		Handle:           entity.Handle,
+		Name:             entity.Name,
+		Email:            email,
+		Telephone:        entity.Telephone,
+		IncType:          entity.IncType,
+		CompanyNumber:    entity.CompanyNumber,
+		Website:          entity.Website,
+		DeclaredTurnover: entity.DeclaredTurnover,
+		Description:      entity.Description,
+		Address:          entity.Address,
+		City:             entity.City,
+		Region:           entity.Region,
+		PostalCode:       entity.PostalCode,
+		Country:          entity.Country,
+		Latitude:         entity.Latitude,
+		Longitude:        entity.Longitude,
+		Status:           entity.Status,
+		Offers:           TagFieldToNames(entity.Offers),
+		Wants:            TagFieldToNames(entity.Wants),
+		Categories:       entity.Categories,
+		Rating:           entity.Rating,
+		ReviewCount:      entity.ReviewCount,
+	}
+}
+
+// EntityExportRespond is an exported entity. The admin fields are only set by the admin export.
+type EntityExportRespond struct {
 	ID               string   `json:"id"`
 	AccountNumber    string   `json:"accountNumber"`
+	Handle           string   `json:"handle,omitempty"`
 	Name             string   `json:"name"`
 	Email            string   `json:"email,omitempty"`
 	Telephone        string   `json:"telephone"`
@@ -144,35 +368,121 @@ type SearchEntityRespond struct {
 	Region           string   `json:"region"`
 	PostalCode       string   `json:"postalCode"`
 	Country          string   `json:"country"`
+	Latitude         *float64 `json:"latitude,omitempty"`
+	Longitude        *float64 `json:"longitude,omitempty"`
 	Status           string   `json:"status"`
 	Offers           []string `json:"offers"`
 	Wants            []string `json:"wants"`
 	Categories       []string `json:"categories"`
-	IsFavorite       bool     `json:"isFavorite"`
+	Rating           *float64 `json:"rating,omitempty"`
+	ReviewCount      int      `json:"reviewCount"`
+	// Admin
+	CreatedAt                          *time.Time                 `json:"createdAt,omitempty"`
+	MemberStartedAt                    *time.Time                 `json:"memberStartedAt,omitempty"`
+	ShowTagsMatchedSinceLastLogin      *bool                      `json:"showTagsMatchedSinceLastLogin,omitempty"`
+	ReceiveDailyMatchNotificationEmail *bool                      `json:"receiveDailyMatchNotificationEmail,omitempty"`
+	Balance                            *float64                   `json:"balance,omitempty"`
+	MaxPositiveBalance                 *float64                   `json:"maxPositiveBalance,omitempty"`
+	MaxNegativeBalance                 *float64                   `json:"maxNegativeBalance,omitempty"`
+	Users                              []*EntityExportUserRespond `json:"users,omitempty"`
+}
+
+type EntityExportUserRespond struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Telephone string `json:"telephone"`
+	Role      string `json:"role"`
+}
+
+// GET /feeds/members
+// GET /feeds/tags
+
+// Feed is written as Atom, RSS or JSON Feed. The items are ordered from the newest.
+type Feed struct {
+	Title       string
+	Description string
+	// The web page of the feed and the URL of the feed itself.
+	Link    string
+	FeedURL string
+	// The time of the newest item, zero when the feed is empty.
+	Updated time.Time
+	Items   []*FeedItem
+}
+
+type FeedItem struct {
+	ID         string
+	Title      string
+	URL        string
+	Content    string
+	Published  time.Time
+	Categories []string
+}
+
+// NewMemberFeedItem announces an entity that has been accepted as a trading member.
+func NewMemberFeedItem(entity *Entity, url string) *FeedItem {
+	content := []string{}
+	if entity.Description != "" {
+		content = append(content, entity.Description)
+	}
+	if len(entity.Offers) != 0 {
+		content = append(content, "Offers: "+strings.Join(TagFieldToNames(entity.Offers), ", "))
+	}
+	if len(entity.Wants) != 0 {
+		content = append(content, "Wants: "+strings.Join(TagFieldToNames(entity.Wants), ", "))
+	}
+	return &FeedItem{
+		ID:         url,
+		Title:      entity.Name,
+		URL:        url,
+		Content:    strings.Join(content, "\n\n"),
+		Published:  entity.MemberStartedAt,
+		Categories: entity.Categories,
+	}
+}
+
+// NewTagFeedItem announces an offer or want added by an entity.
+// The ID includes the time so a tag added again after being removed is a new item.
+func NewTagFeedItem(added *EntityTagAddition, url string) *FeedItem {
+	verb := " offers "
+	if added.Type == constant.FeedTagType.Want {
+		verb = " wants "
+	}
+	return &FeedItem{
+		ID:         url + "#" + added.Type + "-" + added.Tag.Name + "-" + strconv.FormatInt(added.Tag.CreatedAt.Unix(), 10),
+		Title:      added.Name + verb + added.Tag.Name,
+		URL:        url,
+		Content:    added.Description,
+		Published:  added.Tag.CreatedAt,
+		Categories: append([]string{added.Tag.Name}, added.Categories...),
+	}
 }
 
 // POST /transfers
 
 func NewProposeTransferRespond(journal *Journal) *ProposeTransferRespond {
 	return &ProposeTransferRespond{
-		ID:          journal.TransferID,
-		From:        journal.FromAccountNumber,
-		To:          journal.ToAccountNumber,
-		Amount:      journal.Amount,
-		Description: journal.Description,
-		Status:      journal.Status,
-		CreatedAt:   &journal.CreatedAt,
+		ID:              journal.TransferID,
+		From:            journal.FromAccountNumber,
+		To:              journal.ToAccountNumber,
+		Amount:          journal.Amount,
+		Description:     journal.Description,
+		Status:          journal.Status,
+		CreatedAt:       &journal.CreatedAt,
+		EscrowReleaseAt: journal.EscrowReleaseAt,
 	}
 }
 
 type ProposeTransferRespond struct {
-	ID          string     `json:"id"`
-	From        string     `json:"from"`
-	To          string     `json:"to"`
-	Amount      float64    `json:"amount"`
-	Description string     `json:"description"`
-	Status      string     `json:"status"`
-	CreatedAt   *time.Time `json:"dateProposed,omitempty"`
+	ID              string     `json:"id"`
+	From            string     `json:"from"`
+	To              string     `json:"to"`
+	Amount          float64    `json:"amount"`
+	Description     string     `json:"description"`
+	Status          string     `json:"status"`
+	CreatedAt       *time.Time `json:"dateProposed,omitempty"`
+	EscrowReleaseAt *time.Time `json:"escrowReleaseAt,omitempty"`
 }
 
 // GET /transfers
@@ -204,6 +514,9 @@ func NewJournalsToTransfersRespond(journals []*Journal, queryingAccountNumber st
 		if j.Status == constant.Transfer.Completed {
 			t.CompletedAt = &j.UpdatedAt
 		}
+		if j.Status == constant.Transfer.Escrowed {
+			t.EscrowReleaseAt = j.EscrowReleaseAt
+		}
 
 		transfers = append(transfers, t)
 	}
@@ -223,6 +536,204 @@ type TransferRespond struct {
 	CancellationReason string     `json:"cancellationReason,omitempty"`
 	CreatedAt          *time.Time `json:"dateProposed,omitempty"`
 	CompletedAt        *time.Time `json:"dateCompleted,omitempty"`
+	EscrowReleaseAt    *time.Time `json:"escrowReleaseAt,omitempty"`
+}
+
+// GET /transfers/{transferID}
+
+type TransferDetailRespond struct {
+	*TransferRespond
+	Type string `json:"type"`
+	// Counterparty is nil when the other side is a system account, such as the escrow account.
+	Counterparty *TransferCounterpartyRespond `json:"counterparty,omitempty"`
+	Lifecycle    []*TransferEventRespond      `json:"lifecycle"`
+	Postings     []*PostingRespond            `json:"postings"`
+	Comments     []*TransferCommentRespond    `json:"comments"`
+}
+
+type TransferCounterpartyRespond struct {
+	ID            string `json:"id"`
+	AccountNumber string `json:"accountNumber"`
+	Handle        string `json:"handle,omitempty"`
+	Name          string `json:"name"`
+	Email         string `json:"email,omitempty"`
+	Telephone     string `json:"telephone"`
+	Website       string `json:"website"`
+	City          string `json:"city"`
+	Region        string `json:"region"`
+	Country       string `json:"country"`
+	Status        string `json:"status"`
+}
+
+// TransferEventRespond is a step of the transfer's lifecycle.
+// The account number is empty when the step was done by the system or an admin.
+type TransferEventRespond struct {
+	Event         string     `json:"event"`
+	AccountNumber string     `json:"accountNumber,omitempty"`
+	EntityName    string     `json:"entityName,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	Date          *time.Time `json:"date"`
+}
+
+type PostingRespond struct {
+	AccountNumber string    `json:"accountNumber"`
+	Amount        float64   `json:"amount"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+func NewTransferDetailRespond(
+	j *Journal,
+	queryingEntity *Entity,
+	counterparty *Entity,
+	postings []*Posting,
+	comments []*TransferComment,
+) *TransferDetailRespond {
+	res := &TransferDetailRespond{
+		TransferRespond: NewJournalsToTransfersRespond([]*Journal{j}, queryingEntity.AccountNumber)[0],
+		Type:            j.Type,
+		Lifecycle:       newTransferLifecycle(j),
+		Postings:        []*PostingRespond{},
+		Comments:        NewTransferCommentsRespond(comments),
+	}
+	if counterparty != nil {
+		email := ""
+		if util.IsTradingAccepted(counterparty.Status) && util.IsTradingAccepted(queryingEntity.Status) {
+			email = counterparty.Email
+		}
+		res.Counterparty = &TransferCounterpartyRespond{
+			ID:            counterparty.ID.Hex(),
+			AccountNumber: counterparty.AccountNumber,
+			Handle:        counterparty.Handle,
+			Name:          counterparty.Name,
+			Email:         email,
+			Telephone:     counterparty.Telephone,
+			Website:       counterparty.Website,
+			City:          counterparty.City,
+			Region:        counterparty.Region,
+			Country:       counterparty.Country,
+			Status:        counterparty.Status,
+		}
+	}
+	for _, p := range postings {
+		res.Postings = append(res.Postings, &PostingRespond{
+			AccountNumber: p.AccountNumber,
+			Amount:        p.Amount,
+			CreatedAt:     p.CreatedAt,
+		})
+	}
+	return res
+}
+
+func newTransferLifecycle(j *Journal) []*TransferEventRespond {
+	events := []*TransferEventRespond{
+		{
+			Event:         "initiated",
+			AccountNumber: j.InitiatedBy,
+			EntityName:    j.EntityName(j.InitiatedBy),
+			Date:          &j.CreatedAt,
+		},
+	}
+	if j.Status == constant.Transfer.Escrowed || j.EscrowReleaseAt != nil {
+		events = append(events, &TransferEventRespond{
+			Event: "escrowed",
+			Date:  &j.CreatedAt,
+		})
+	}
+	if j.Status == constant.Transfer.Completed {
+		completedAt := j.CompletedAt
+		if completedAt.IsZero() {
+			completedAt = j.UpdatedAt
+		}
+		events = append(events, &TransferEventRespond{
+			Event:         "completed",
+			AccountNumber: j.AcceptedBy,
+			EntityName:    j.EntityName(j.AcceptedBy),
+			Date:          &completedAt,
+		})
+	}
+	if j.Status == constant.Transfer.Cancelled {
+		cancelledAt := j.CancelledAt
+		if cancelledAt == nil {
+			cancelledAt = &j.UpdatedAt
+		}
+		events = append(events, &TransferEventRespond{
+			Event:         "cancelled",
+			AccountNumber: j.CancelledBy,
+			EntityName:    j.EntityName(j.CancelledBy),
+			Reason:        j.CancellationReason,
+			Date:          cancelledAt,
+		})
+	}
+	return events
+}
+
+// POST /transfers/{transferID}/comments
+// GET /transfers/{transferID}/comments
+
+type TransferCommentRespond struct {
+	AccountNumber string    `json:"accountNumber"`
+	EntityName    string    `json:"entityName"`
+	Body          string    `json:"body"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+func NewTransferCommentRespond(c *TransferComment) *TransferCommentRespond {
+	return &TransferCommentRespond{
+		AccountNumber: c.AccountNumber,
+		EntityName:    c.EntityName,
+		Body:          c.Body,
+		CreatedAt:     c.CreatedAt,
+	}
+}
+
+func NewTransferCommentsRespond(comments []*TransferComment) []*TransferCommentRespond {
+	result := []*TransferCommentRespond{}
+	for _, c := range comments {
+		result = append(result, NewTransferCommentRespond(c))
+	}
+	return result
+}
+
+// POST /transfers/{transferID}/reviews
+// GET /transfers/{transferID}/reviews
+// GET /entities/{searchEntityID}/reviews
+
+type ReviewRespond struct {
+	ID                    string     `json:"id"`
+	TransferID            string     `json:"transferID"`
+	ReviewerAccountNumber string     `json:"reviewerAccountNumber"`
+	ReviewerEntityName    string     `json:"reviewerEntityName"`
+	RevieweeAccountNumber string     `json:"revieweeAccountNumber"`
+	RevieweeEntityName    string     `json:"revieweeEntityName"`
+	Rating                int        `json:"rating"`
+	Body                  string     `json:"body"`
+	Reply                 string     `json:"reply,omitempty"`
+	RepliedAt             *time.Time `json:"repliedAt,omitempty"`
+	CreatedAt             time.Time  `json:"createdAt"`
+}
+
+func NewReviewRespond(review *Review) *ReviewRespond {
+	return &ReviewRespond{
+		ID:                    review.ReviewID,
+		TransferID:            review.TransferID,
+		ReviewerAccountNumber: review.ReviewerAccountNumber,
+		ReviewerEntityName:    review.ReviewerEntityName,
+		RevieweeAccountNumber: review.RevieweeAccountNumber,
+		RevieweeEntityName:    review.RevieweeEntityName,
+		Rating:                review.Rating,
+		Body:                  review.Body,
+		Reply:                 review.Reply,
+		RepliedAt:             review.RepliedAt,
+		CreatedAt:             review.CreatedAt,
+	}
+}
+
+func NewReviewsRespond(reviews []*Review) []*ReviewRespond {
+	result := []*ReviewRespond{}
+	for _, review := range reviews {
+		result = append(result, NewReviewRespond(review))
+	}
+	return result
 }
 
 type SearchTransferRespond struct {
@@ -248,6 +759,8 @@ func NewAdminEntityRespond(entity *Entity) *AdminEntityRespond {
 		Region:                             entity.Region,
 		PostalCode:                         entity.PostalCode,
 		Country:                            entity.Country,
+		Latitude:                           entity.Latitude,
+		Longitude:                          entity.Longitude,
 		Status:                             entity.Status,
 		Offers:                             TagFieldToNames(entity.Offers),
 		Wants:                              TagFieldToNames(entity.Wants),
@@ -273,6 +786,8 @@ type AdminEntityRespond struct {
 	Region                             string   `json:"region"`
 	PostalCode                         string   `json:"postalCode"`
 	Country                            string   `json:"country"`
+	Latitude                           *float64 `json:"latitude,omitempty"`
+	Longitude                          *float64 `json:"longitude,omitempty"`
 	Status                             string   `json:"status"`
 	Offers                             []string `json:"offers,omitempty"`
 	Wants                              []string `json:"wants,omitempty"`
@@ -425,6 +940,7 @@ func NewAdminSearchEntityRespond(
 	return &AdminSearchEntityRespond{
 		ID:                                 entity.ID.Hex(),
 		AccountNumber:                      entity.AccountNumber,
+		Handle:                             entity.Handle,
 		Name:                               entity.Name,
 		Email:                              entity.Email,
 		Telephone:                          entity.Telephone,
@@ -438,6 +954,8 @@ func NewAdminSearchEntityRespond(
 		Region:                             entity.Region,
 		PostalCode:                         entity.PostalCode,
 		Country:                            entity.Country,
+		Latitude:                           entity.Latitude,
+		Longitude:                          entity.Longitude,
 		Status:                             entity.Status,
 		Offers:                             TagFieldToNames(entity.Offers),
 		Wants:                              TagFieldToNames(entity.Wants),
@@ -454,6 +972,7 @@ func NewAdminSearchEntityRespond(
 type AdminSearchEntityRespond struct {
 	ID                                 string              `json:"id"`
 	AccountNumber                      string              `json:"accountNumber"`
+	Handle                             string              `json:"handle,omitempty"`
 	Name                               string              `json:"name"`
 	Email                              string              `json:"email,omitempty"`
 	Telephone                          string              `json:"telephone"`
@@ -467,6 +986,8 @@ type AdminSearchEntityRespond struct {
 	Region                             string              `json:"region"`
 	PostalCode                         string              `json:"postalCode"`
 	Country                            string              `json:"country"`
+	Latitude                           *float64            `json:"latitude,omitempty"`
+	Longitude                          *float64            `json:"longitude,omitempty"`
 	Status                             string              `json:"status"`
 	Offers                             []string            `json:"offers,omitempty"`
 	Wants                              []string            `json:"wants,omitempty"`
@@ -479,6 +1000,42 @@ type AdminSearchEntityRespond struct {
 	Users                              []*AdminUserRespond `json:"users"`
 }
 
+// GET /admin/entities/export
+
+// NewAdminEntityExportRespond has every field of the entity, its account and its users.
+func NewAdminEntityExportRespond(
+	entity *Entity,
+	users []*User,
+	account *Account,
+	balanceLimit *BalanceLimit,
+) *EntityExportRespond {
+	res := NewEntityExportRespond(entity, "")
+	res.Email = entity.Email
+	res.CreatedAt = &entity.CreatedAt
+	if !entity.MemberStartedAt.IsZero() {
+		res.MemberStartedAt = &entity.MemberStartedAt
+	}
+	showTagsMatchedSinceLastLogin := util.ToBool(entity.ShowTagsMatchedSinceLastLogin)
+	receiveDailyMatchNotificationEmail := util.ToBool(entity.ReceiveDailyMatchNotificationEmail)
+	res.ShowTagsMatchedSinceLastLogin = &showTagsMatchedSinceLastLogin
+	res.ReceiveDailyMatchNotificationEmail = &receiveDailyMatchNotificationEmail
+	res.Balance = &account.Balance
+	res.MaxPositiveBalance = &balanceLimit.MaxPosBal
+	res.MaxNegativeBalance = &balanceLimit.MaxNegBal
+	res.Users = make([]*EntityExportUserRespond, 0, len(users))
+	for _, user := range users {
+		res.Users = append(res.Users, &EntityExportUserRespond{
+			ID:        user.ID.Hex(),
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Telephone: user.Telephone,
+			Role:      entity.Role(user.ID),
+		})
+	}
+	return res
+}
+
 // GET /admin/entities/{entityID}
 
 func NewAdminGetEntityRespond(
@@ -487,6 +1044,7 @@ func NewAdminGetEntityRespond(
 	account *Account,
 	balanceLimit *BalanceLimit,
 	pendingTransfers []*AdminTransferRespond,
+	statusChanges []*EntityStatusChange,
 ) *AdminGetEntityRespond {
 	adminUserResponds := []*AdminUserRespond{}
 	for _, u := range users {
@@ -496,6 +1054,7 @@ func NewAdminGetEntityRespond(
 	return &AdminGetEntityRespond{
 		ID:                                 entity.ID.Hex(),
 		AccountNumber:                      entity.AccountNumber,
+		Handle:                             entity.Handle,
 		Name:                               entity.Name,
 		Email:                              entity.Email,
 		Telephone:                          entity.Telephone,
@@ -509,6 +1068,8 @@ func NewAdminGetEntityRespond(
 		Region:                             entity.Region,
 		PostalCode:                         entity.PostalCode,
 		Country:                            entity.Country,
+		Latitude:                           entity.Latitude,
+		Longitude:                          entity.Longitude,
 		Status:                             entity.Status,
 		Offers:                             TagFieldToNames(entity.Offers),
 		Wants:                              TagFieldToNames(entity.Wants),
@@ -520,12 +1081,15 @@ func NewAdminGetEntityRespond(
 		MaxPositiveBalance:                 balanceLimit.MaxPosBal,
 		PendingTransfers:                   pendingTransfers,
 		Users:                              adminUserResponds,
+		Freeze:                             NewFreezeRespond(account),
+		StatusHistory:                      NewEntityStatusChangesRespond(statusChanges),
 	}
 }
 
 type AdminGetEntityRespond struct {
 	ID                                 string                  `json:"id"`
 	AccountNumber                      string                  `json:"accountNumber"`
+	Handle                             string                  `json:"handle,omitempty"`
 	Name                               string                  `json:"name"`
 	Email                              string                  `json:"email,omitempty"`
 	Telephone                          string                  `json:"telephone"`
@@ -539,6 +1103,8 @@ type AdminGetEntityRespond struct {
 	Region                             string                  `json:"region"`
 	PostalCode                         string                  `json:"postalCode"`
 	Country                            string                  `json:"country"`
+	Latitude                           *float64                `json:"latitude,omitempty"`
+	Longitude                          *float64                `json:"longitude,omitempty"`
 	Status                             string                  `json:"status"`
 	Offers                             []string                `json:"offers,omitempty"`
 	Wants                              []string                `json:"wants,omitempty"`
@@ -550,6 +1116,44 @@ type AdminGetEntityRespond struct {
 	MaxNegativeBalance                 float64                 `json:"maxNegativeBalance"`
 	PendingTransfers                   []*AdminTransferRespond `json:"pendingTransfers"`
 	Users                              []*AdminUserRespond     `json:"users"`
+	Freeze                             *FreezeRespond          `json:"freeze,omitempty"`
+	// Newest first.
+	StatusHistory []*EntityStatusChangeRespond `json:"statusHistory"`
+}
+
+// GET /admin/entities/{entityID}
+// GET /admin/entities/status-changes
+
+type EntityStatusChangeRespond struct {
+	ID         string    `json:"id"`
+	EntityID   string    `json:"entityID"`
+	EntityName string    `json:"entityName"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Reason     string    `json:"reason"`
+	ChangedBy  string    `json:"changedBy"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func NewEntityStatusChangeRespond(change *EntityStatusChange) *EntityStatusChangeRespond {
+	return &EntityStatusChangeRespond{
+		ID:         change.ID.Hex(),
+		EntityID:   change.EntityID.Hex(),
+		EntityName: change.EntityName,
+		From:       change.From,
+		To:         change.To,
+		Reason:     change.Reason,
+		ChangedBy:  change.ChangedBy.Hex(),
+		CreatedAt:  change.CreatedAt,
+	}
+}
+
+func NewEntityStatusChangesRespond(changes []*EntityStatusChange) []*EntityStatusChangeRespond {
+	result := []*EntityStatusChangeRespond{}
+	for _, change := range changes {
+		result = append(result, NewEntityStatusChangeRespond(change))
+	}
+	return result
 }
 
 // PATCH /admin/entities/{entityID}
@@ -562,6 +1166,7 @@ func NewAdminUpdateEntityRespond(users []*User, entity *Entity, balanceLimit *Ba
 	respond := &AdminUpdateEntityRespond{
 		ID:                                 entity.ID.Hex(),
 		AccountNumber:                      entity.AccountNumber,
+		Handle:                             entity.Handle,
 		Name:                               entity.Name,
 		Email:                              entity.Email,
 		Telephone:                          entity.Telephone,
@@ -575,6 +1180,8 @@ func NewAdminUpdateEntityRespond(users []*User, entity *Entity, balanceLimit *Ba
 		Region:                             entity.Region,
 		PostalCode:                         entity.PostalCode,
 		Country:                            entity.Country,
+		Latitude:                           entity.Latitude,
+		Longitude:                          entity.Longitude,
 		Status:                             entity.Status,
 		Offers:                             TagFieldToNames(entity.Offers),
 		Wants:                              TagFieldToNames(entity.Wants),
@@ -592,6 +1199,7 @@ func NewAdminUpdateEntityRespond(users []*User, entity *Entity, balanceLimit *Ba
 type AdminUpdateEntityRespond struct {
 	ID                                 string              `json:"id"`
 	AccountNumber                      string              `json:"accountNumber"`
+	Handle                             string              `json:"handle,omitempty"`
 	Name                               string              `json:"name"`
 	Email                              string              `json:"email,omitempty"`
 	Telephone                          string              `json:"telephone"`
@@ -605,6 +1213,8 @@ type AdminUpdateEntityRespond struct {
 	Region                             string              `json:"region"`
 	PostalCode                         string              `json:"postalCode"`
 	Country                            string              `json:"country"`
+	Latitude                           *float64            `json:"latitude,omitempty"`
+	Longitude                          *float64            `json:"longitude,omitempty"`
 	Status                             string              `json:"status"`
 	Offers                             []string            `json:"offers,omitempty"`
 	Wants                              []string            `json:"wants,omitempty"`
@@ -637,6 +1247,8 @@ func NewAdminDeleteEntityRespond(entity *Entity) *AdminDeleteEntityRespond {
 		Region:                             entity.Region,
 		PostalCode:                         entity.PostalCode,
 		Country:                            entity.Country,
+		Latitude:                           entity.Latitude,
+		Longitude:                          entity.Longitude,
 		Status:                             entity.Status,
 		Offers:                             TagFieldToNames(entity.Offers),
 		Wants:                              TagFieldToNames(entity.Wants),
@@ -662,6 +1274,8 @@ type AdminDeleteEntityRespond struct {
 	Region                             string   `json:"region"`
 	PostalCode                         string   `json:"postalCode"`
 	Country                            string   `json:"country"`
+	Latitude                           *float64 `json:"latitude,omitempty"`
+	Longitude                          *float64 `json:"longitude,omitempty"`
 	Status                             string   `json:"status"`
 	Offers                             []string `json:"offers,omitempty"`
 	Wants                              []string `json:"wants,omitempty"`
@@ -670,6 +1284,35 @@ type AdminDeleteEntityRespond struct {
 	ReceiveDailyMatchNotificationEmail bool     `json:"receiveDailyMatchNotificationEmail"`
 }
 
+// GET /admin/reviews
+// PATCH /admin/reviews/{reviewID}
+
+type AdminReviewRespond struct {
+	*ReviewRespond
+	UserID         string `json:"userID"`
+	Hidden         bool   `json:"hidden"`
+	ModerationNote string `json:"moderationNote,omitempty"`
+	ModeratedBy    string `json:"moderatedBy,omitempty"`
+}
+
+func NewAdminReviewRespond(review *Review) *AdminReviewRespond {
+	return &AdminReviewRespond{
+		ReviewRespond:  NewReviewRespond(review),
+		UserID:         review.UserID,
+		Hidden:         review.Hidden,
+		ModerationNote: review.ModerationNote,
+		ModeratedBy:    review.ModeratedBy,
+	}
+}
+
+func NewAdminReviewsRespond(reviews []*Review) []*AdminReviewRespond {
+	result := []*AdminReviewRespond{}
+	for _, review := range reviews {
+		result = append(result, NewAdminReviewRespond(review))
+	}
+	return result
+}
+
 // admin/transfer
 
 type AdminTransferRespond struct {
@@ -685,6 +1328,11 @@ type AdminTransferRespond struct {
 	CancellationReason string     `json:"cancellationReason,omitempty"`
 	CreatedAt          *time.Time `json:"dateProposed,omitempty"`
 	CompletedAt        *time.Time `json:"dateCompleted,omitempty"`
+	EscrowReleaseAt    *time.Time `json:"escrowReleaseAt,omitempty"`
+	RequestedBy        string     `json:"requestedBy,omitempty"`
+	ReviewedBy         string     `json:"reviewedBy,omitempty"`
+	// Only included in GET /admin/transfers/{transferID}.
+	Comments []*TransferCommentRespond `json:"comments,omitempty"`
 }
 
 // GET /admin/transfer
@@ -706,10 +1354,15 @@ func NewJournalsToAdminTransfersRespond(journals []*Journal) []*AdminTransferRes
 			Status:             j.Status,
 			CancellationReason: j.CancellationReason,
 			CreatedAt:          &j.CreatedAt,
+			RequestedBy:        j.RequestedBy,
+			ReviewedBy:         j.ReviewedBy,
 		}
 		if j.Status == constant.Transfer.Completed {
 			t.CompletedAt = &j.UpdatedAt
 		}
+		if j.Status == constant.Transfer.Escrowed {
+			t.EscrowReleaseAt = j.EscrowReleaseAt
+		}
 
 		adminTransferRespond = append(adminTransferRespond, t)
 	}
@@ -739,9 +1392,390 @@ func NewJournalToAdminTransferRespond(j *Journal) *AdminTransferRespond {
 		Status:             j.Status,
 		CancellationReason: j.CancellationReason,
 		CreatedAt:          &j.CreatedAt,
+		RequestedBy:        j.RequestedBy,
+		ReviewedBy:         j.ReviewedBy,
 	}
 	if j.Status == constant.Transfer.Completed {
 		res.CompletedAt = &j.UpdatedAt
 	}
+	if j.Status == constant.Transfer.Escrowed {
+		res.EscrowReleaseAt = j.EscrowReleaseAt
+	}
 	return res
 }
+
+// POST /admin/transfers/imports
+// GET /admin/transfers/imports/{importID}
+
+type TransferImportRespond struct {
+	ImportID                    string                      `json:"id"`
+	FileName                    string                      `json:"fileName"`
+	Status                      string                      `json:"status"`
+	Mode                        string                      `json:"mode,omitempty"`
+	NumberOfRows                int                         `json:"numberOfRows"`
+	NumberOfInvalidRows         int                         `json:"numberOfInvalidRows"`
+	NumberOfCreatedRows         int                         `json:"numberOfCreatedRows"`
+	NumberOfPendingApprovalRows int                         `json:"numberOfPendingApprovalRows"`
+	NumberOfFailedRows          int                         `json:"numberOfFailedRows"`
+	Rows                        []*TransferImportRowRespond `json:"rows"`
+	CreatedAt                   time.Time                   `json:"createdAt"`
+	ExecutedAt                  *time.Time                  `json:"executedAt,omitempty"`
+}
+
+type TransferImportRowRespond struct {
+	Line        int     `json:"line"`
+	Payer       string  `json:"payer"`
+	Payee       string  `json:"payee"`
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description"`
+	Status      string  `json:"status"`
+	TransferID  string  `json:"transferID,omitempty"`
+	Error       string  `json:"error,omitempty"`
+}
+
+func NewTransferImportRespond(record *TransferImport) *TransferImportRespond {
+	rows := make([]*TransferImportRowRespond, 0, len(record.Rows))
+	for _, row := range record.Rows {
+		rows = append(rows, &TransferImportRowRespond{
+			Line:        row.Line,
+			Payer:       row.Payer,
+			Payee:       row.Payee,
+			Amount:      row.Amount,
+			Description: row.Description,
+			Status:      row.Status,
+			TransferID:  row.TransferID,
+			Error:       row.Error,
+		})
+	}
+	return &TransferImportRespond{
+		ImportID:                    record.ImportID,
+		FileName:                    record.FileName,
+		Status:                      record.Status,
+		Mode:                        record.Mode,
+		NumberOfRows:                len(record.Rows),
+		NumberOfInvalidRows:         record.NumberOfRowsWithStatus(constant.TransferImportRow.Invalid),
+		NumberOfCreatedRows:         record.NumberOfRowsWithStatus(constant.TransferImportRow.Created),
+		NumberOfPendingApprovalRows: record.NumberOfRowsWithStatus(constant.TransferImportRow.PendingApproval),
+		NumberOfFailedRows:          record.NumberOfRowsWithStatus(constant.TransferImportRow.Failed),
+		Rows:                        rows,
+		CreatedAt:                   record.CreatedAt,
+		ExecutedAt:                  record.ExecutedAt,
+	}
+}
+
+type EntityImportRespond struct {
+	ImportID              string                    `json:"id"`
+	FileName              string                    `json:"fileName"`
+	Status                string                    `json:"status"`
+	SendInvitations       bool                      `json:"sendInvitations"`
+	NumberOfRows          int                       `json:"numberOfRows"`
+	NumberOfInvalidRows   int                       `json:"numberOfInvalidRows"`
+	NumberOfCreatedRows   int                       `json:"numberOfCreatedRows"`
+	NumberOfUserAddedRows int                       `json:"numberOfUserAddedRows"`
+	NumberOfFailedRows    int                       `json:"numberOfFailedRows"`
+	Rows                  []*EntityImportRowRespond `json:"rows"`
+	CreatedAt             time.Time                 `json:"createdAt"`
+	ExecutedAt            *time.Time                `json:"executedAt,omitempty"`
+}
+
+type EntityImportRowRespond struct {
+	Line         int    `json:"line"`
+	EntityName   string `json:"entityName"`
+	EntityStatus string `json:"entityStatus,omitempty"`
+	FirstName    string `json:"firstName,omitempty"`
+	LastName     string `json:"lastName,omitempty"`
+	UserEmail    string `json:"userEmail"`
+	Status       string `json:"status"`
+	EntityID     string `json:"entityID,omitempty"`
+	UserID       string `json:"userID,omitempty"`
+	Invited      bool   `json:"invited"`
+	Error        string `json:"error,omitempty"`
+}
+
+func NewEntityImportRespond(record *EntityImport) *EntityImportRespond {
+	rows := make([]*EntityImportRowRespond, 0, len(record.Rows))
+	for _, row := range record.Rows {
+		rows = append(rows, &EntityImportRowRespond{
+			Line:         row.Line,
+			EntityName:   row.EntityName,
+			EntityStatus: row.EntityStatus,
+			FirstName:    row.FirstName,
+			LastName:     row.LastName,
+			UserEmail:    row.UserEmail,
+			Status:       row.Status,
+			EntityID:     row.EntityID,
+			UserID:       row.UserID,
+			Invited:      row.Invited,
+			Error:        row.Error,
+		})
+	}
+	return &EntityImportRespond{
+		ImportID:              record.ImportID,
+		FileName:              record.FileName,
+		Status:                record.Status,
+		SendInvitations:       record.SendInvitations,
+		NumberOfRows:          len(record.Rows),
+		NumberOfInvalidRows:   record.NumberOfRowsWithStatus(constant.EntityImportRow.Invalid),
+		NumberOfCreatedRows:   record.NumberOfRowsWithStatus(constant.EntityImportRow.Created),
+		NumberOfUserAddedRows: record.NumberOfRowsWithStatus(constant.EntityImportRow.UserAdded),
+		NumberOfFailedRows:    record.NumberOfRowsWithStatus(constant.EntityImportRow.Failed),
+		Rows:                  rows,
+		CreatedAt:             record.CreatedAt,
+		ExecutedAt:            record.ExecutedAt,
+	}
+}
+
+// PATCH /admin/accounts/{accountNumber}/freeze
+
+func NewAdminFreezeAccountRespond(account *Account) *AdminFreezeAccountRespond {
+	return &AdminFreezeAccountRespond{
+		AccountNumber: account.AccountNumber,
+		Balance:       account.Balance,
+		Freeze:        NewFreezeRespond(account),
+	}
+}
+
+type AdminFreezeAccountRespond struct {
+	AccountNumber string         `json:"accountNumber"`
+	Balance       float64        `json:"balance"`
+	Freeze        *FreezeRespond `json:"freeze"`
+}
+
+// POST /admin/accounts/{accountNumber}/closure
+// GET /admin/accounts/{accountNumber}/closure
+
+func NewAccountClosureRespond(c *AccountClosure) *AccountClosureRespond {
+	return &AccountClosureRespond{
+		AccountNumber:           c.AccountNumber,
+		SettlementAccountNumber: c.SettlementAccountNumber,
+		SettlementTransferID:    c.SettlementTransferID,
+		Reason:                  c.Reason,
+		Step:                    c.Step,
+		LastError:               c.LastError,
+		RequestedAt:             c.CreatedAt,
+		TransfersCancelledAt:    c.TransfersCancelledAt,
+		BalanceSettledAt:        c.BalanceSettledAt,
+		BalanceVerifiedAt:       c.BalanceVerifiedAt,
+		ClosedAt:                c.ClosedAt,
+	}
+}
+
+type AccountClosureRespond struct {
+	AccountNumber           string     `json:"accountNumber"`
+	SettlementAccountNumber string     `json:"settlementAccountNumber,omitempty"`
+	SettlementTransferID    string     `json:"settlementTransferID,omitempty"`
+	Reason                  string     `json:"reason,omitempty"`
+	Step                    string     `json:"step"`
+	LastError               string     `json:"lastError,omitempty"`
+	RequestedAt             time.Time  `json:"requestedAt"`
+	TransfersCancelledAt    *time.Time `json:"transfersCancelledAt,omitempty"`
+	BalanceSettledAt        *time.Time `json:"balanceSettledAt,omitempty"`
+	BalanceVerifiedAt       *time.Time `json:"balanceVerifiedAt,omitempty"`
+	ClosedAt                *time.Time `json:"closedAt,omitempty"`
+}
+
+// GET /admin/documents/{code}
+
+func NewDocumentRespond(d *Document) *DocumentRespond {
+	return &DocumentRespond{
+		Code:           d.Code,
+		Type:           d.Type,
+		AccountNumber:  d.AccountNumber,
+		EntityName:     d.EntityName,
+		TransferID:     d.TransferID,
+		Amount:         d.Amount,
+		PeriodFrom:     d.PeriodFrom,
+		PeriodTo:       d.PeriodTo,
+		OpeningBalance: d.OpeningBalance,
+		ClosingBalance: d.ClosingBalance,
+		IssuedAt:       d.CreatedAt,
+	}
+}
+
+type DocumentRespond struct {
+	Code           string     `json:"code"`
+	Type           string     `json:"type"`
+	AccountNumber  string     `json:"accountNumber"`
+	EntityName     string     `json:"entityName"`
+	TransferID     string     `json:"transferID,omitempty"`
+	Amount         float64    `json:"amount,omitempty"`
+	PeriodFrom     *time.Time `json:"periodFrom,omitempty"`
+	PeriodTo       *time.Time `json:"periodTo,omitempty"`
+	OpeningBalance float64    `json:"openingBalance"`
+	ClosingBalance float64    `json:"closingBalance"`
+	IssuedAt       time.Time  `json:"issuedAt"`
+}
+
+// GET /user/entities/{entityID}/members
+
+func NewEntityMemberRespond(entity *Entity, user *User) *EntityMemberRespond {
+	return &EntityMemberRespond{
+		UserID:    user.ID.Hex(),
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Email:     user.Email,
+		Role:      entity.Role(user.ID),
+	}
+}
+
+type EntityMemberRespond struct {
+	UserID    string `json:"userID"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+}
+
+// GET /user/entities/{entityID}/invitations
+
+func NewEntityInvitationRespond(i *EntityInvitation) *EntityInvitationRespond {
+	return &EntityInvitationRespond{
+		ID:        i.ID.Hex(),
+		Email:     i.Email,
+		Role:      i.Role,
+		CreatedAt: i.CreatedAt,
+		ExpiresAt: i.ExpiresAt,
+	}
+}
+
+type EntityInvitationRespond struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// GET /application-questions
+// GET /admin/application-questions
+
+func NewApplicationQuestionRespond(q *ApplicationQuestion) *ApplicationQuestionRespond {
+	return &ApplicationQuestionRespond{
+		ID:       q.ID.Hex(),
+		Text:     q.Text,
+		Required: q.Required,
+		Order:    q.Order,
+	}
+}
+
+func NewApplicationQuestionsRespond(questions []*ApplicationQuestion) []*ApplicationQuestionRespond {
+	result := []*ApplicationQuestionRespond{}
+	for _, q := range questions {
+		result = append(result, NewApplicationQuestionRespond(q))
+	}
+	return result
+}
+
+type ApplicationQuestionRespond struct {
+	ID       string `json:"id"`
+	Text     string `json:"text"`
+	Required bool   `json:"required"`
+	Order    int    `json:"order"`
+}
+
+// GET /user/entities/{entityID}/application
+
+func NewEntityApplicationRespond(a *EntityApplication) *EntityApplicationRespond {
+	respond := &EntityApplicationRespond{
+		EntityID:   a.EntityID.Hex(),
+		EntityName: a.EntityName,
+		Status:     a.Status,
+		Answers:    []*ApplicationAnswerRespond{},
+		Documents:  []*ApplicationDocumentRespond{},
+		Comments:   []*ApplicationCommentRespond{},
+		CreatedAt:  a.CreatedAt,
+		UpdatedAt:  a.UpdatedAt,
+	}
+	for _, answer := range a.Answers {
+		respond.Answers = append(respond.Answers, &ApplicationAnswerRespond{
+			QuestionID: answer.QuestionID.Hex(),
+			Question:   answer.Question,
+			Answer:     answer.Answer,
+		})
+	}
+	for _, d := range a.Documents {
+		respond.Documents = append(respond.Documents, &ApplicationDocumentRespond{
+			ID:          d.ID,
+			Type:        d.Type,
+			FileName:    d.FileName,
+			ContentType: d.ContentType,
+			Size:        d.Size,
+			CreatedAt:   d.CreatedAt,
+		})
+	}
+	for _, c := range a.Comments {
+		respond.Comments = append(respond.Comments, &ApplicationCommentRespond{
+			Author:    c.Author,
+			Body:      c.Body,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+	if !a.SubmittedAt.IsZero() {
+		respond.SubmittedAt = &a.SubmittedAt
+	}
+	if !a.ReviewedAt.IsZero() {
+		respond.ReviewedAt = &a.ReviewedAt
+	}
+	return respond
+}
+
+type EntityApplicationRespond struct {
+	EntityID    string                        `json:"entityID"`
+	EntityName  string                        `json:"entityName"`
+	Status      string                        `json:"status"`
+	Answers     []*ApplicationAnswerRespond   `json:"answers"`
+	Documents   []*ApplicationDocumentRespond `json:"documents"`
+	Comments    []*ApplicationCommentRespond  `json:"comments"`
+	SubmittedAt *time.Time                    `json:"submittedAt,omitempty"`
+	ReviewedAt  *time.Time                    `json:"reviewedAt,omitempty"`
+	CreatedAt   time.Time                     `json:"createdAt"`
+	UpdatedAt   time.Time                     `json:"updatedAt"`
+}
+
+type ApplicationAnswerRespond struct {
+	QuestionID string `json:"questionID"`
+	Question   string `json:"question"`
+	Answer     string `json:"answer"`
+}
+
+type ApplicationDocumentRespond struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int       `json:"size"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type ApplicationCommentRespond struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GET /admin/applications
+// GET /admin/entities/{entityID}/application
+
+func NewAdminEntityApplicationRespond(a *EntityApplication) *AdminEntityApplicationRespond {
+	respond := &AdminEntityApplicationRespond{
+		EntityApplicationRespond: NewEntityApplicationRespond(a),
+	}
+	if !a.ReviewedBy.IsZero() {
+		respond.ReviewedBy = a.ReviewedBy.Hex()
+	}
+	return respond
+}
+
+func NewAdminEntityApplicationsRespond(applications []*EntityApplication) []*AdminEntityApplicationRespond {
+	result := []*AdminEntityApplicationRespond{}
+	for _, a := range applications {
+		result = append(result, NewAdminEntityApplicationRespond(a))
+	}
+	return result
+}
+
+type AdminEntityApplicationRespond struct {
+	*EntityApplicationRespond
+	// The admin who made the last decision.
+	ReviewedBy string `json:"reviewedBy,omitempty"`
+}
@@ -11,14 +11,31 @@ type EntityESRecord struct {
 	Wants      []*TagField `json:"wants,omitempty"`
 	Categories []string    `json:"categories,omitempty"`
 	// Address
-	City    string `json:"city,omitempty"`
-	Region  string `json:"region,omitempty"`
-	Country string `json:"country,omitempty"`
+	City     string    `json:"city,omitempty"`
+	Region   string    `json:"region,omitempty"`
+	Country  string    `json:"country,omitempty"`
+	Location *GeoPoint `json:"location,omitempty"`
 	// Account
 	AccountNumber string   `json:"accountNumber,omitempty"`
 	Balance       *float64 `json:"balance,omitempty"`
 	MaxNegBal     *float64 `json:"maxNegBal,omitempty"`
 	MaxPosBal     *float64 `json:"maxPosBal,omitempty"`
+	// Reviews
+	Rating      *float64 `json:"rating,omitempty"`
+	ReviewCount *int     `json:"reviewCount,omitempty"`
+}
+
+// GeoPoint is the ES geo_point of an entity.
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+func NewGeoPoint(latitude, longitude *float64) *GeoPoint {
+	if latitude == nil || longitude == nil {
+		return nil
+	}
+	return &GeoPoint{Lat: *latitude, Lon: *longitude}
 }
 
 type ESSearchEntityResult struct {
@@ -6,6 +6,7 @@ type JournalESRecord struct {
 	TransferID        string    `json:"transferID,omitempty"`
 	FromAccountNumber string    `json:"fromAccountNumber,omitempty"`
 	ToAccountNumber   string    `json:"toAccountNumber,omitempty"`
+	Type              string    `json:"type,omitempty"`
 	Status            string    `json:"status,omitempty"`
 	CreatedAt         time.Time `json:"createdAt,omitempty"`
 }
@@ -0,0 +1,19 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ApplicationQuestion is a question that admins ask every entity in its membership application.
+type ApplicationQuestion struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+
+	Text     string `json:"text,omitempty" bson:"text,omitempty"`
+	Required bool   `json:"required" bson:"required"`
+	// Questions are shown in ascending order.
+	Order int `json:"order" bson:"order"`
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/ShiraazMoollatjie/goluhn"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/util"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -16,6 +18,8 @@ type Entity struct {
 	DeletedAt time.Time          `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
 
 	Users []primitive.ObjectID `json:"users,omitempty" bson:"users,omitempty"`
+	// Users without a role are owners, e.g. the user who signed up the entity.
+	Roles []*EntityMemberRole `json:"roles,omitempty" bson:"roles,omitempty"`
 
 	Name             string      `json:"name,omitempty" bson:"name,omitempty"`
 	Telephone        string      `json:"telephone,omitempty" bson:"telephone,omitempty"`
@@ -32,8 +36,11 @@ type Entity struct {
 	Region           string      `json:"region,omitempty" bson:"region,omitempty"`
 	PostalCode       string      `json:"postalCode,omitempty" bson:"postalCode,omitempty"`
 	Country          string      `json:"country,omitempty" bson:"country,omitempty"`
-	Status           string      `json:"status,omitempty" bson:"status,omitempty"`
-	Categories       []string    `json:"categories,omitempty" bson:"categories,omitempty"`
+	// Set directly or geocoded from the postal code.
+	Latitude   *float64 `json:"latitude,omitempty" bson:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty" bson:"longitude,omitempty"`
+	Status     string   `json:"status,omitempty" bson:"status,omitempty"`
+	Categories []string `json:"categories,omitempty" bson:"categories,omitempty"`
 	// Timestamp when trading status applied
 	MemberStartedAt time.Time `json:"memberStartedAt,omitempty" bson:"memberStartedAt,omitempty"`
 
@@ -44,7 +51,119 @@ type Entity struct {
 	LastNotificationSentDate time.Time `json:"lastNotificationSentDate,omitempty" bson:"lastNotificationSentDate,omitempty"`
 
 	AccountNumber    string               `json:"accountNumber,omitempty" bson:"accountNumber,omitempty"`
+	Handle           string               `json:"handle,omitempty" bson:"handle,omitempty"`
 	FavoriteEntities []primitive.ObjectID `json:"favoriteEntities,omitempty" bson:"favoriteEntities,omitempty"`
+
+	AutoAccept *AutoAcceptRules `json:"autoAccept,omitempty" bson:"autoAccept,omitempty"`
+
+	Logo    *EntityImage   `json:"logo,omitempty" bson:"logo,omitempty"`
+	Gallery []*EntityImage `json:"gallery,omitempty" bson:"gallery,omitempty"`
+
+	// The average rating of the visible reviews. It is nil until the entity has been reviewed.
+	Rating      *float64 `json:"rating,omitempty" bson:"rating,omitempty"`
+	ReviewCount int      `json:"reviewCount,omitempty" bson:"reviewCount,omitempty"`
+}
+
+// EntityImage is an uploaded image and its thumbnail. The keys locate the files in the storage.
+type EntityImage struct {
+	ID           string    `json:"id" bson:"id"`
+	URL          string    `json:"url" bson:"url"`
+	ThumbnailURL string    `json:"thumbnailURL" bson:"thumbnailURL"`
+	Key          string    `json:"key" bson:"key"`
+	ThumbnailKey string    `json:"thumbnailKey" bson:"thumbnailKey"`
+	CreatedAt    time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+type EntityMemberRole struct {
+	UserID primitive.ObjectID `json:"userID" bson:"userID"`
+	Role   string             `json:"role" bson:"role"`
+}
+
+// Role returns the role of the user in the entity, or an empty string if the user is not a member or has no role.
+func (entity *Entity) Role(userID primitive.ObjectID) string {
+	if !util.ContainID(entity.Users, userID.Hex()) {
+		return ""
+	}
+	for _, r := range entity.Roles {
+		if r.UserID == userID {
+			return r.Role
+		}
+	}
+	// Members added before entities had roles are given theirs by the migration, until then they have no access.
+	return ""
+}
+
+// HasRoleEntry checks whether the user has a role in the entity, whether or not they are a member.
+func (entity *Entity) HasRoleEntry(userID primitive.ObjectID) bool {
+	for _, r := range entity.Roles {
+		if r.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole checks whether the user is a member of the entity with at least the role.
+func (entity *Entity) HasRole(userID primitive.ObjectID, role string) bool {
+	r := entity.Role(userID)
+	return r != "" && constant.EntityRoleRank[r] >= constant.EntityRoleRank[role]
+}
+
+// NumberOfOwners counts the members who are owners.
+func (entity *Entity) NumberOfOwners() int {
+	count := 0
+	for _, userID := range entity.Users {
+		if entity.Role(userID) == constant.EntityRole.Owner {
+			count++
+		}
+	}
+	return count
+}
+
+// AutoAcceptRules decides which incoming transfers are accepted without the entity reviewing them.
+// A transfer is accepted automatically when it matches any of the rules.
+type AutoAcceptRules struct {
+	FromFavorites bool `json:"fromFavorites" bson:"fromFavorites"`
+	// Transfers below this amount are accepted. Zero disables the rule.
+	BelowAmount  float64  `json:"belowAmount" bson:"belowAmount"`
+	FromAccounts []string `json:"fromAccounts" bson:"fromAccounts"`
+}
+
+// AutoAccepts checks whether the entity accepts the transfer from the sender automatically.
+func (entity *Entity) AutoAccepts(sender *Entity, amount float64) bool {
+	rules := entity.AutoAccept
+	if rules == nil {
+		return false
+	}
+	if rules.FromFavorites {
+		for _, id := range entity.FavoriteEntities {
+			if id == sender.ID {
+				return true
+			}
+		}
+	}
+	if rules.BelowAmount > 0 && amount < rules.BelowAmount {
+		return true
+	}
+	for _, accountNumber := range rules.FromAccounts {
+		if accountNumber == sender.AccountNumber {
+			return true
+		}
+	}
+	return false
+}
+
+func (rules *AutoAcceptRules) Validate() []error {
+	errs := []error{}
+	if rules.BelowAmount < 0 || !util.IsDecimalValid(rules.BelowAmount) {
+		errs = append(errs, errors.New("Please enter a valid auto-accept amount with up to two decimal places."))
+	}
+	for _, accountNumber := range rules.FromAccounts {
+		if goluhn.Validate(accountNumber) != nil {
+			errs = append(errs, errors.New("Auto-accept account number "+accountNumber+" is invalid."))
+		}
+	}
+	return errs
 }
 
 func (entity *Entity) Validate() []error {
@@ -93,6 +212,15 @@ func (entity *Entity) Validate() []error {
 	if len(entity.Country) > 50 {
 		errs = append(errs, errors.New("Country length cannot exceed 50 characters."))
 	}
+	if (entity.Latitude == nil) != (entity.Longitude == nil) {
+		errs = append(errs, errors.New("Please specify both the latitude and the longitude."))
+	}
+	if entity.Latitude != nil && (*entity.Latitude < -90 || *entity.Latitude > 90) {
+		errs = append(errs, errors.New("Latitude should be between -90 and 90."))
+	}
+	if entity.Longitude != nil && (*entity.Longitude < -180 || *entity.Longitude > 180) {
+		errs = append(errs, errors.New("Longitude should be between -180 and 180."))
+	}
 	return errs
 }
 
@@ -104,6 +232,17 @@ type SearchEntityResult struct {
 	TotalPages      int
 }
 
+// EntityTagAddition is an offer or want tag of an entity with the entity's public fields.
+type EntityTagAddition struct {
+	EntityID    primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name        string             `json:"name,omitempty" bson:"name,omitempty"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	Categories  []string           `json:"categories,omitempty" bson:"categories,omitempty"`
+	Tag         *TagField          `json:"tag,omitempty" bson:"tag,omitempty"`
+	// "offer" or "want"
+	Type string `json:"type,omitempty" bson:"-"`
+}
+
 type UpdateOfferAndWants struct {
 	EntityID      primitive.ObjectID
 	OriginStatus  string
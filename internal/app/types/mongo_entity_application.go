@@ -0,0 +1,60 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EntityApplication is the membership application of an entity: its answers to the application questions
+// and its verification documents. Admins review it before accepting the entity.
+// Every entity has at most one application.
+type EntityApplication struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+
+	EntityID   primitive.ObjectID     `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	EntityName string                 `json:"entityName,omitempty" bson:"entityName,omitempty"`
+	Status     string                 `json:"status,omitempty" bson:"status,omitempty"`
+	Answers    []*ApplicationAnswer   `json:"answers,omitempty" bson:"answers,omitempty"`
+	Documents  []*ApplicationDocument `json:"documents,omitempty" bson:"documents,omitempty"`
+	// The conversation between the entity and the admins, oldest first.
+	Comments []*ApplicationComment `json:"comments,omitempty" bson:"comments,omitempty"`
+
+	SubmittedAt time.Time          `json:"submittedAt,omitempty" bson:"submittedAt,omitempty"`
+	ReviewedAt  time.Time          `json:"reviewedAt,omitempty" bson:"reviewedAt,omitempty"`
+	ReviewedBy  primitive.ObjectID `json:"reviewedBy,omitempty" bson:"reviewedBy,omitempty"`
+}
+
+// ApplicationAnswer keeps the question text as it was answered so later edits of the question don't change it.
+type ApplicationAnswer struct {
+	QuestionID primitive.ObjectID `json:"questionID" bson:"questionID"`
+	Question   string             `json:"question" bson:"question"`
+	Answer     string             `json:"answer" bson:"answer"`
+}
+
+// ApplicationDocument is an uploaded verification document. The key locates the file in the private storage.
+type ApplicationDocument struct {
+	ID          string    `json:"id" bson:"id"`
+	Type        string    `json:"type" bson:"type"`
+	FileName    string    `json:"fileName" bson:"fileName"`
+	ContentType string    `json:"contentType" bson:"contentType"`
+	Size        int       `json:"size" bson:"size"`
+	Key         string    `json:"key" bson:"key"`
+	CreatedAt   time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+type ApplicationComment struct {
+	// Either the entity or an admin.
+	Author    string             `json:"author" bson:"author"`
+	AuthorID  primitive.ObjectID `json:"authorID" bson:"authorID"`
+	Body      string             `json:"body" bson:"body"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+type SearchEntityApplicationResult struct {
+	Applications    []*EntityApplication
+	NumberOfResults int
+	TotalPages      int
+}
@@ -0,0 +1,28 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EntityInvitation is an invitation for a user to join an entity with a role.
+type EntityInvitation struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+
+	EntityID  primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	Email     string             `json:"email,omitempty" bson:"email,omitempty"`
+	Role      string             `json:"role,omitempty" bson:"role,omitempty"`
+	Token     string             `json:"token,omitempty" bson:"token,omitempty"`
+	InvitedBy primitive.ObjectID `json:"invitedBy,omitempty" bson:"invitedBy,omitempty"`
+	ExpiresAt time.Time          `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+
+	AcceptedBy primitive.ObjectID `json:"acceptedBy,omitempty" bson:"acceptedBy,omitempty"`
+	AcceptedAt *time.Time         `json:"acceptedAt,omitempty" bson:"acceptedAt,omitempty"`
+}
+
+// IsValid checks whether the invitation can still be accepted.
+func (i *EntityInvitation) IsValid() bool {
+	return i.AcceptedAt == nil && time.Now().Before(i.ExpiresAt)
+}
@@ -0,0 +1,26 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EntityStatusChange records an admin moving an entity from one status to another.
+type EntityStatusChange struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+
+	EntityID   primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	EntityName string             `json:"entityName,omitempty" bson:"entityName,omitempty"`
+	From       string             `json:"from,omitempty" bson:"from,omitempty"`
+	To         string             `json:"to,omitempty" bson:"to,omitempty"`
+	Reason     string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	ChangedBy  primitive.ObjectID `json:"changedBy,omitempty" bson:"changedBy,omitempty"`
+}
+
+type SearchEntityStatusChangeResult struct {
+	Changes         []*EntityStatusChange
+	NumberOfResults int
+	TotalPages      int
+}
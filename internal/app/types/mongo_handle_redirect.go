@@ -0,0 +1,17 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HandleRedirect keeps an old handle resolving to its entity for a grace period after the handle has changed.
+type HandleRedirect struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+
+	Handle    string             `json:"handle,omitempty" bson:"handle,omitempty"`
+	EntityID  primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	ExpiresAt time.Time          `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+}
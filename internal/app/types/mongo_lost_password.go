@@ -13,4 +13,6 @@ type LostPassword struct {
 	Email     string             `json:"email,omitempty" bson:"email,omitempty"`
 	Token     string             `json:"token,omitempty" bson:"token,omitempty"`
 	TokenUsed bool               `json:"tokenUsed,omitempty" bson:"tokenUsed,omitempty"`
+	// ExpiresAt overrides the reset_password_timeout, e.g. for the invitations of imported users.
+	ExpiresAt time.Time `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
 }
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Payee is an entry in the entity's address book of transfer receivers.
+type Payee struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+	DeletedAt time.Time          `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+
+	// The entity owning the payee list.
+	EntityID primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+
+	Nickname           string    `json:"nickname,omitempty" bson:"nickname,omitempty"`
+	AccountNumber      string    `json:"accountNumber,omitempty" bson:"accountNumber,omitempty"`
+	DefaultDescription string    `json:"defaultDescription,omitempty" bson:"defaultDescription,omitempty"`
+	DefaultAmount      float64   `json:"defaultAmount,omitempty" bson:"defaultAmount,omitempty"`
+	LastUsedAt         time.Time `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"`
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	"github.com/jinzhu/gorm"
 )
 
@@ -10,4 +12,25 @@ type Account struct {
 	Postings      []Posting
 	AccountNumber string  `gorm:"type:varchar(16);not null;unique_index"`
 	Balance       float64 `gorm:"not null;default:0"`
+
+	// Freeze stops the account from moving money without touching the entity status.
+	FrozenSending   bool   `gorm:"not null;default:false"`
+	FrozenReceiving bool   `gorm:"not null;default:false"`
+	FreezeReason    string `gorm:"type:varchar(510);not null;default:''"`
+	// The freeze is lifted automatically after this time. Nil means indefinitely.
+	FrozenUntil *time.Time
+}
+
+func (a *Account) isFreezeActive() bool {
+	return a.FrozenUntil == nil || a.FrozenUntil.After(time.Now())
+}
+
+// IsSendingFrozen checks whether the account is currently blocked from sending.
+func (a *Account) IsSendingFrozen() bool {
+	return a.FrozenSending && a.isFreezeActive()
+}
+
+// IsReceivingFrozen checks whether the account is currently blocked from receiving.
+func (a *Account) IsReceivingFrozen() bool {
+	return a.FrozenReceiving && a.isFreezeActive()
 }
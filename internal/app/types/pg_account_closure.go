@@ -0,0 +1,27 @@
+package types
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// AccountClosure records the progress of closing an account so the workflow can be resumed if a step fails.
+type AccountClosure struct {
+	gorm.Model
+	AccountNumber string `gorm:"type:varchar(16);not null;index"`
+	EntityID      string `gorm:"type:varchar(24);not null;default:''"`
+	// The residual balance is moved to this account. Empty means the balance must already be zero.
+	SettlementAccountNumber string `gorm:"type:varchar(16);not null;default:''"`
+	SettlementTransferID    string `gorm:"type:varchar(27);not null;default:''"`
+	RequestedBy             string `gorm:"type:varchar(24);not null;default:''"`
+	Reason                  string `gorm:"type:varchar(510);not null;default:''"`
+	// Step is the last step that has been completed successfully.
+	Step      string `gorm:"type:varchar(31);not null;default:''"`
+	LastError string `gorm:"type:varchar(510);not null;default:''"`
+
+	TransfersCancelledAt *time.Time
+	BalanceSettledAt     *time.Time
+	BalanceVerifiedAt    *time.Time
+	ClosedAt             *time.Time
+}
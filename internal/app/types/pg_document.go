@@ -0,0 +1,27 @@
+package types
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Document records a receipt or statement that was issued so the verification code printed on it can be checked.
+type Document struct {
+	gorm.Model
+	Code          string `gorm:"type:varchar(19);not null;unique_index"`
+	Type          string `gorm:"type:varchar(31);not null;default:''"`
+	AccountNumber string `gorm:"type:varchar(16);not null;default:''"`
+	EntityName    string `gorm:"type:varchar(120);not null;default:''"`
+	UserID        string `gorm:"type:varchar(24);not null;default:''"`
+
+	// Receipts only.
+	TransferID string  `gorm:"type:varchar(27);not null;default:''"`
+	Amount     float64 `gorm:"not null;default:0"`
+
+	// Statements only.
+	PeriodFrom     *time.Time
+	PeriodTo       *time.Time
+	OpeningBalance float64 `gorm:"not null;default:0"`
+	ClosingBalance float64 `gorm:"not null;default:0"`
+}
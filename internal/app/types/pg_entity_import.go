@@ -0,0 +1,70 @@
+package types
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// EntityImport is a CSV file of entities and their users. It is validated when uploaded
+// and executed once an admin has confirmed the dry-run report.
+type EntityImport struct {
+	gorm.Model
+	ImportID        string `gorm:"type:varchar(27);not null;unique_index"`
+	FileName        string `gorm:"type:varchar(255);not null;default:''"`
+	RequestedBy     string `gorm:"type:varchar(24);not null;default:''"`
+	Status          string `gorm:"type:varchar(31);not null;default:''"`
+	SendInvitations bool   `gorm:"not null;default:false"`
+	// EntityImport has many rows, EntityImportID is the foreign key
+	Rows       []*EntityImportRow
+	ExecutedAt *time.Time
+}
+
+func (e *EntityImport) NumberOfRowsWithStatus(status string) int {
+	count := 0
+	for _, row := range e.Rows {
+		if row.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// EntityImportRow is a user of an entity. Rows with the same entity name belong to the same entity,
+// the entity columns are read from the first of them.
+type EntityImportRow struct {
+	gorm.Model
+	EntityImportID uint `gorm:"not null;index"`
+	// Line is the line number in the CSV file.
+	Line int `gorm:"not null;default:0"`
+	// The cells are stored as they are in the file, even when they are too long or cannot be parsed.
+	EntityName       string `gorm:"type:text;not null;default:''"`
+	EntityEmail      string `gorm:"type:text;not null;default:''"`
+	IncType          string `gorm:"type:text;not null;default:''"`
+	CompanyNumber    string `gorm:"type:text;not null;default:''"`
+	EntityPhone      string `gorm:"type:text;not null;default:''"`
+	Website          string `gorm:"type:text;not null;default:''"`
+	DeclaredTurnover string `gorm:"type:text;not null;default:''"`
+	Description      string `gorm:"type:text;not null;default:''"`
+	Address          string `gorm:"type:text;not null;default:''"`
+	City             string `gorm:"type:text;not null;default:''"`
+	Region           string `gorm:"type:text;not null;default:''"`
+	PostalCode       string `gorm:"type:text;not null;default:''"`
+	Country          string `gorm:"type:text;not null;default:''"`
+	Offers           string `gorm:"type:text;not null;default:''"`
+	Wants            string `gorm:"type:text;not null;default:''"`
+	Categories       string `gorm:"type:text;not null;default:''"`
+	EntityStatus     string `gorm:"type:text;not null;default:''"`
+	MaxPosBal        string `gorm:"type:text;not null;default:''"`
+	MaxNegBal        string `gorm:"type:text;not null;default:''"`
+	FirstName        string `gorm:"type:text;not null;default:''"`
+	LastName         string `gorm:"type:text;not null;default:''"`
+	UserEmail        string `gorm:"type:text;not null;default:''"`
+	UserPhone        string `gorm:"type:text;not null;default:''"`
+
+	Status   string `gorm:"type:varchar(31);not null;default:''"`
+	Error    string `gorm:"type:text;not null;default:''"`
+	EntityID string `gorm:"type:varchar(24);not null;default:''"`
+	UserID   string `gorm:"type:varchar(24);not null;default:''"`
+	Invited  bool   `gorm:"not null;default:false"`
+}
@@ -27,6 +27,38 @@ type Journal struct {
 	Status      string  `gorm:"type:varchar(31);not null;default:''"`
 
 	CompletedAt time.Time
+	// Escrowed transfers are released to the payee automatically at this time.
+	EscrowReleaseAt *time.Time
+
+	// Admin transfers above the approval threshold are requested by one admin
+	// and approved or rejected by another one.
+	RequestedBy string `gorm:"type:varchar(24);not null;default:''"`
+	ReviewedBy  string `gorm:"type:varchar(24);not null;default:''"`
 
 	CancellationReason string `gorm:"type:varchar(510);not null;default:''"`
+
+	// The account numbers of the parties who accepted or cancelled the transfer.
+	// They are empty when it was done by the system or an admin.
+	AcceptedBy  string `gorm:"type:varchar(16);not null;default:''"`
+	CancelledBy string `gorm:"type:varchar(16);not null;default:''"`
+	CancelledAt *time.Time
+}
+
+// ReceiverAccountNumber returns the account number of the party who has to accept or reject the transfer.
+func (j *Journal) ReceiverAccountNumber() string {
+	if j.InitiatedBy == j.FromAccountNumber {
+		return j.ToAccountNumber
+	}
+	return j.FromAccountNumber
+}
+
+// EntityName returns the name of the party of the transfer with the account number.
+func (j *Journal) EntityName(accountNumber string) string {
+	if accountNumber == j.FromAccountNumber {
+		return j.FromEntityName
+	}
+	if accountNumber == j.ToAccountNumber {
+		return j.ToEntityName
+	}
+	return ""
 }
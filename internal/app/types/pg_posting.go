@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	"github.com/jinzhu/gorm"
 )
 
@@ -10,3 +12,33 @@ type Posting struct {
 	JournalID     uint    `gorm:"not null"`
 	Amount        float64 `gorm:"not null"`
 }
+
+// StatementEntry is a posting of an account together with the details of its transfer.
+type StatementEntry struct {
+	TransferID        string
+	Type              string
+	Description       string
+	FromAccountNumber string
+	FromEntityName    string
+	ToAccountNumber   string
+	ToEntityName      string
+	// Positive for money received, negative for money sent.
+	Amount    float64
+	CreatedAt time.Time
+}
+
+// CounterpartyAccountNumber returns the account number of the other party of the transfer.
+func (e *StatementEntry) CounterpartyAccountNumber() string {
+	if e.Amount > 0 {
+		return e.FromAccountNumber
+	}
+	return e.ToAccountNumber
+}
+
+// CounterpartyEntityName returns the name of the other party of the transfer.
+func (e *StatementEntry) CounterpartyEntityName() string {
+	if e.Amount > 0 {
+		return e.FromEntityName
+	}
+	return e.ToEntityName
+}
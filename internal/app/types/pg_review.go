@@ -0,0 +1,37 @@
+package types
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Review is a rating and a short review that a party of a completed transfer leaves about the other party.
+// Every party can review a transfer once.
+type Review struct {
+	gorm.Model
+	ReviewID              string `gorm:"type:varchar(27);not null;unique_index"`
+	TransferID            string `gorm:"type:varchar(27);not null;unique_index:idx_reviews_transfer_reviewer"`
+	ReviewerAccountNumber string `gorm:"type:varchar(16);not null;unique_index:idx_reviews_transfer_reviewer"`
+	ReviewerEntityName    string `gorm:"type:varchar(120);not null;default:''"`
+	RevieweeAccountNumber string `gorm:"type:varchar(16);not null;index"`
+	RevieweeEntityName    string `gorm:"type:varchar(120);not null;default:''"`
+	UserID                string `gorm:"type:varchar(24);not null;default:''"`
+	Rating                int    `gorm:"not null;default:0"`
+	Body                  string `gorm:"type:varchar(1000);not null;default:''"`
+
+	// The reviewed entity's answer to the review.
+	Reply     string `gorm:"type:varchar(1000);not null;default:''"`
+	RepliedAt *time.Time
+
+	// Hidden reviews are only shown to admins and do not count towards the rating.
+	Hidden         bool   `gorm:"not null;default:false"`
+	ModerationNote string `gorm:"type:varchar(510);not null;default:''"`
+	ModeratedBy    string `gorm:"type:varchar(24);not null;default:''"`
+}
+
+type SearchReviewResult struct {
+	Reviews         []*Review
+	NumberOfResults int
+	TotalPages      int
+}
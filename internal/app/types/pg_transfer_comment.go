@@ -0,0 +1,15 @@
+package types
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// TransferComment is a message posted on a transfer by one of its parties.
+type TransferComment struct {
+	gorm.Model
+	TransferID    string `gorm:"type:varchar(27);not null;index"`
+	AccountNumber string `gorm:"type:varchar(16);not null;default:''"`
+	EntityName    string `gorm:"type:varchar(120);not null;default:''"`
+	UserID        string `gorm:"type:varchar(24);not null;default:''"`
+	Body          string `gorm:"type:varchar(1000);not null;default:''"`
+}
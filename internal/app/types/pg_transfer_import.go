@@ -0,0 +1,67 @@
+package types
+
+import (
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/jinzhu/gorm"
+)
+
+// TransferImport is a CSV file of admin transfers. It is validated when uploaded
+// and executed once an admin has confirmed the dry-run report.
+type TransferImport struct {
+	gorm.Model
+	ImportID    string `gorm:"type:varchar(27);not null;unique_index"`
+	FileName    string `gorm:"type:varchar(255);not null;default:''"`
+	RequestedBy string `gorm:"type:varchar(24);not null;default:''"`
+	Status      string `gorm:"type:varchar(31);not null;default:''"`
+	Mode        string `gorm:"type:varchar(31);not null;default:''"`
+	// TransferImport has many rows, TransferImportID is the foreign key
+	Rows       []*TransferImportRow
+	ExecutedAt *time.Time
+}
+
+func (t *TransferImport) NumberOfRowsWithStatus(status string) int {
+	count := 0
+	for _, row := range t.Rows {
+		if row.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// NumberOfTransferredRows counts the rows that have a transfer, e.g. from an execution that was interrupted.
+func (t *TransferImport) NumberOfTransferredRows() int {
+	count := 0
+	for _, row := range t.Rows {
+		if row.TransferID != "" {
+			count++
+		}
+	}
+	return count
+}
+
+type TransferImportRow struct {
+	gorm.Model
+	TransferImportID uint `gorm:"not null;index"`
+	// Line is the line number in the CSV file.
+	Line int `gorm:"not null;default:0"`
+	// The cells are stored as they are in the file, even when they are too long to be valid.
+	Payer       string  `gorm:"type:text;not null;default:''"`
+	Payee       string  `gorm:"type:text;not null;default:''"`
+	Amount      float64 `gorm:"not null;default:0"`
+	Description string  `gorm:"type:text;not null;default:''"`
+	Status      string  `gorm:"type:varchar(31);not null;default:''"`
+	Error       string  `gorm:"type:text;not null;default:''"`
+	TransferID  string  `gorm:"type:varchar(27);not null;default:''"`
+}
+
+// SetResult records the transfer created for the row.
+func (row *TransferImportRow) SetResult(j *Journal) {
+	row.TransferID = j.TransferID
+	row.Status = constant.TransferImportRow.Created
+	if j.Status == constant.Transfer.PendingApproval {
+		row.Status = constant.TransferImportRow.PendingApproval
+	}
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// VoucherKey is the server-issued key pair an entity's vouchers are signed with.
+type VoucherKey struct {
+	gorm.Model
+	AccountNumber string `gorm:"type:varchar(16);not null;unique_index"`
+	// Hex encoded ed25519 public key.
+	PublicKey string `gorm:"type:varchar(64);not null;default:''"`
+	// The ed25519 private key encrypted with voucher.key_encryption_key.
+	PrivateKey string `gorm:"type:varchar(255);not null;default:''"`
+}
+
+// Voucher is a signed promise to pay that can be handed over offline and redeemed later.
+// The amount is reserved against the payer's balance until the voucher is redeemed, cancelled or expired.
+type Voucher struct {
+	gorm.Model
+	Nonce              string    `gorm:"type:varchar(27);not null;unique_index"`
+	PayerAccountNumber string    `gorm:"type:varchar(16);not null;index"`
+	PayerEntityName    string    `gorm:"type:varchar(120);not null;default:''"`
+	Amount             float64   `gorm:"not null;default:0"`
+	Description        string    `gorm:"type:varchar(510);not null;default:''"`
+	ExpiresAt          time.Time `gorm:"not null"`
+	// Base64url encoded ed25519 signature of the payload.
+	Signature string `gorm:"type:varchar(128);not null;default:''"`
+	Status    string `gorm:"type:varchar(31);not null;default:''"`
+
+	RedeemedBy  string `gorm:"type:varchar(16);not null;default:''"`
+	TransferID  string `gorm:"type:varchar(27);not null;default:''"`
+	RedeemedAt  *time.Time
+	CancelledAt *time.Time
+}
+
+func (v *Voucher) IsExpired() bool {
+	return !v.ExpiresAt.After(time.Now())
+}
+
+// VoucherPayload is the signed part of a voucher.
+type VoucherPayload struct {
+	Payer     string  `json:"payer"`
+	Amount    float64 `json:"amount"`
+	ExpiresAt int64   `json:"expiry"`
+	Nonce     string  `json:"nonce"`
+}
+
+func (v *Voucher) Payload() *VoucherPayload {
+	return &VoucherPayload{
+		Payer:     v.PayerAccountNumber,
+		Amount:    v.Amount,
+		ExpiresAt: v.ExpiresAt.Unix(),
+		Nonce:     v.Nonce,
+	}
+}
+
+// Code is the voucher as it is handed over to the payee: the encoded payload and its signature joined by a dot.
+func (v *Voucher) Code() string {
+	payload, _ := json.Marshal(v.Payload())
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + v.Signature
+}
+
+// ParseVoucherCode splits the voucher code into its payload, the payload bytes the signature is over, and the signature.
+func ParseVoucherCode(code string) (*VoucherPayload, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimSpace(code), ".")
+	if len(parts) != 2 {
+		return nil, nil, nil, errors.New("The voucher is malformed.")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, nil, errors.New("The voucher is malformed.")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, nil, errors.New("The voucher is malformed.")
+	}
+	var p VoucherPayload
+	err = json.Unmarshal(payload, &p)
+	if err != nil {
+		return nil, nil, nil, errors.New("The voucher is malformed.")
+	}
+	return &p, payload, signature, nil
+}
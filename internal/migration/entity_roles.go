@@ -0,0 +1,18 @@
+package migration
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+// EntityOwnerRoles makes the members who were added before entities had roles the owners of their entities.
+// Members without a role have no access, so it has to run once after the upgrade. Running it again changes nothing.
+func EntityOwnerRoles() {
+	updated, err := mongo.Entity.AddMissingOwnerRoles()
+	if err != nil {
+		l.Logger.Error("[Error] migration.EntityOwnerRoles failed:", zap.Error(err))
+		return
+	}
+	l.Logger.Info("[Info] migration.EntityOwnerRoles added the owner roles", zap.Int("entities", updated))
+}
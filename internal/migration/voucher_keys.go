@@ -0,0 +1,18 @@
+package migration
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+// VoucherKeys encrypts the voucher signing keys that were stored before the keys were encrypted.
+// Vouchers cannot be issued with an unencrypted key, so it has to run once after the upgrade. Running it again changes nothing.
+func VoucherKeys() {
+	encrypted, err := logic.Voucher.EncryptPrivateKeys()
+	if err != nil {
+		l.Logger.Error("[Error] migration.VoucherKeys failed:", zap.Error(err))
+		return
+	}
+	l.Logger.Info("[Info] migration.VoucherKeys encrypted the private keys", zap.Int("keys", encrypted))
+}
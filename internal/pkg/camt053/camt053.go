@@ -0,0 +1,214 @@
+// Package camt053 defines the ISO 20022 BankToCustomerStatement message (camt.053.001.08).
+// Only the elements needed for a single account statement are defined, in the order required by the schema.
+package camt053
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+const Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"
+
+const (
+	Credit = "CRDT"
+	Debit  = "DBIT"
+
+	OpeningBooked = "OPBD"
+	ClosingBooked = "CLBD"
+
+	Booked = "BOOK"
+)
+
+type Document struct {
+	XMLName xml.Name         `xml:"Document"`
+	Xmlns   string           `xml:"xmlns,attr"`
+	Stmt    BkToCstmrStmtV08 `xml:"BkToCstmrStmt"`
+}
+
+type BkToCstmrStmtV08 struct {
+	GrpHdr GroupHeader        `xml:"GrpHdr"`
+	Stmt   []AccountStatement `xml:"Stmt"`
+}
+
+type GroupHeader struct {
+	MsgId   string   `xml:"MsgId"`
+	CreDtTm DateTime `xml:"CreDtTm"`
+}
+
+type AccountStatement struct {
+	Id           string             `xml:"Id"`
+	CreDtTm      DateTime           `xml:"CreDtTm"`
+	FrToDt       *DateTimePeriod    `xml:"FrToDt,omitempty"`
+	Acct         CashAccount        `xml:"Acct"`
+	Bal          []CashBalance      `xml:"Bal"`
+	TxsSummry    *TotalTransactions `xml:"TxsSummry,omitempty"`
+	Ntry         []ReportEntry      `xml:"Ntry"`
+	AddtlStmtInf string             `xml:"AddtlStmtInf,omitempty"`
+}
+
+type DateTimePeriod struct {
+	FrDtTm DateTime `xml:"FrDtTm"`
+	ToDtTm DateTime `xml:"ToDtTm"`
+}
+
+type CashAccount struct {
+	Id  AccountIdentification `xml:"Id"`
+	Ccy string                `xml:"Ccy,omitempty"`
+	Nm  string                `xml:"Nm,omitempty"`
+}
+
+type AccountIdentification struct {
+	Othr GenericAccountIdentification `xml:"Othr"`
+}
+
+type GenericAccountIdentification struct {
+	Id string `xml:"Id"`
+}
+
+type CashBalance struct {
+	Tp        BalanceType `xml:"Tp"`
+	Amt       Amount      `xml:"Amt"`
+	CdtDbtInd string      `xml:"CdtDbtInd"`
+	Dt        DateChoice  `xml:"Dt"`
+}
+
+type BalanceType struct {
+	Cd string `xml:"CdOrPrtry>Cd"`
+}
+
+type DateChoice struct {
+	Dt   *Date     `xml:"Dt,omitempty"`
+	DtTm *DateTime `xml:"DtTm,omitempty"`
+}
+
+type TotalTransactions struct {
+	TtlNtries    NumberAndSumOfTransactions4 `xml:"TtlNtries"`
+	TtlCdtNtries NumberAndSumOfTransactions1 `xml:"TtlCdtNtries"`
+	TtlDbtNtries NumberAndSumOfTransactions1 `xml:"TtlDbtNtries"`
+}
+
+type NumberAndSumOfTransactions4 struct {
+	NbOfNtries int                `xml:"NbOfNtries"`
+	Sum        Decimal            `xml:"Sum"`
+	TtlNetNtry AmountAndDirection `xml:"TtlNetNtry"`
+}
+
+type AmountAndDirection struct {
+	Amt       Decimal `xml:"Amt"`
+	CdtDbtInd string  `xml:"CdtDbtInd"`
+}
+
+type NumberAndSumOfTransactions1 struct {
+	NbOfNtries int     `xml:"NbOfNtries"`
+	Sum        Decimal `xml:"Sum"`
+}
+
+type ReportEntry struct {
+	NtryRef   string              `xml:"NtryRef,omitempty"`
+	Amt       Amount              `xml:"Amt"`
+	CdtDbtInd string              `xml:"CdtDbtInd"`
+	RvslInd   bool                `xml:"RvslInd,omitempty"`
+	Sts       string              `xml:"Sts>Cd"`
+	BookgDt   DateChoice          `xml:"BookgDt"`
+	ValDt     DateChoice          `xml:"ValDt"`
+	BkTxCd    BankTransactionCode `xml:"BkTxCd"`
+	NtryDtls  []EntryDetails      `xml:"NtryDtls"`
+}
+
+type BankTransactionCode struct {
+	Prtry ProprietaryBankTransactionCode `xml:"Prtry"`
+}
+
+type ProprietaryBankTransactionCode struct {
+	Cd string `xml:"Cd"`
+}
+
+type EntryDetails struct {
+	TxDtls []TransactionDetails `xml:"TxDtls"`
+}
+
+type TransactionDetails struct {
+	Refs      *TransactionReferences `xml:"Refs,omitempty"`
+	RltdPties *TransactionParties    `xml:"RltdPties,omitempty"`
+	RmtInf    *RemittanceInformation `xml:"RmtInf,omitempty"`
+}
+
+type TransactionReferences struct {
+	EndToEndId string `xml:"EndToEndId"`
+}
+
+type TransactionParties struct {
+	Dbtr     *PartyIdentification `xml:"Dbtr>Pty,omitempty"`
+	DbtrAcct *CashAccount         `xml:"DbtrAcct,omitempty"`
+	Cdtr     *PartyIdentification `xml:"Cdtr>Pty,omitempty"`
+	CdtrAcct *CashAccount         `xml:"CdtrAcct,omitempty"`
+}
+
+type PartyIdentification struct {
+	Nm string `xml:"Nm,omitempty"`
+}
+
+type RemittanceInformation struct {
+	Ustrd []string `xml:"Ustrd"`
+}
+
+// Amount is a non-negative amount with its currency. The direction is given by the CdtDbtInd next to it.
+type Amount struct {
+	Ccy   string  `xml:"Ccy,attr"`
+	Value Decimal `xml:",chardata"`
+}
+
+type Decimal float64
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%.2f", float64(d))), nil
+}
+
+type Date time.Time
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(time.Time(d).UTC().Format("2006-01-02")), nil
+}
+
+type DateTime time.Time
+
+func (d DateTime) MarshalText() ([]byte, error) {
+	return []byte(time.Time(d).UTC().Format("2006-01-02T15:04:05Z")), nil
+}
+
+// NewAmount splits the signed amount into the amount and its credit or debit indicator.
+func NewAmount(amount float64, currency string) (Amount, string) {
+	if amount < 0 {
+		return Amount{Ccy: currency, Value: Decimal(-amount)}, Debit
+	}
+	return Amount{Ccy: currency, Value: Decimal(amount)}, Credit
+}
+
+func NewDate(t time.Time) DateChoice {
+	d := Date(t)
+	return DateChoice{Dt: &d}
+}
+
+func NewDateTime(t time.Time) DateChoice {
+	d := DateTime(t)
+	return DateChoice{DtTm: &d}
+}
+
+// Write writes the document with the XML declaration.
+func (d *Document) Write(w io.Writer) error {
+	d.Xmlns = Namespace
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(d)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,295 @@
+package camt053
+
+import (
+	"bytes"
+	"math"
+	"os/exec"
+	"sort"
+	"testing"
+	"time"
+)
+
+const (
+	member       = "1000000000000001"
+	supplier     = "1000000000000002"
+	customer     = "1000000000000003"
+	escrow       = "9000000000000001"
+	currency     = "XXX"
+	schemaPath   = "testdata/camt.053.001.08.xsd"
+	transferType = "transfer"
+)
+
+var (
+	periodStart = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd   = time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// ledgerPosting is a row of the postings table.
+type ledgerPosting struct {
+	account string
+	amount  float64
+	at      time.Time
+}
+
+type ledgerJournal struct {
+	transferID  string
+	description string
+	from        string
+	to          string
+	postings    []ledgerPosting
+}
+
+var names = map[string]string{
+	member:   "Member & Sons",
+	supplier: "Supplier",
+	customer: "Customer",
+}
+
+// transfer moves the amount between two accounts with two postings.
+func transfer(id string, from string, to string, amount float64, at time.Time) *ledgerJournal {
+	return &ledgerJournal{
+		transferID:  id,
+		description: "Invoice " + id,
+		from:        from,
+		to:          to,
+		postings: []ledgerPosting{
+			{account: from, amount: -amount, at: at},
+			{account: to, amount: amount, at: at},
+		},
+	}
+}
+
+// escrowed moves the amount to the escrow account and either releases it to the payee or refunds the payer,
+// which makes four postings for the journal.
+func escrowed(id string, from string, to string, amount float64, escrowedAt time.Time, settledAt time.Time, refunded bool) *ledgerJournal {
+	settledTo := to
+	if refunded {
+		settledTo = from
+	}
+	return &ledgerJournal{
+		transferID:  id,
+		description: "Escrow " + id,
+		from:        from,
+		to:          to,
+		postings: []ledgerPosting{
+			{account: from, amount: -amount, at: escrowedAt},
+			{account: escrow, amount: amount, at: escrowedAt},
+			{account: escrow, amount: -amount, at: settledAt},
+			{account: settledTo, amount: amount, at: settledAt},
+		},
+	}
+}
+
+// balanceAt sums the postings of the account before the time, like pg.Posting.BalanceAt.
+func balanceAt(journals []*ledgerJournal, account string, at time.Time) float64 {
+	balance := 0.0
+	for _, j := range journals {
+		for _, p := range j.postings {
+			if p.account == account && p.at.Before(at) {
+				balance += p.amount
+			}
+		}
+	}
+	return balance
+}
+
+// newStatement builds the statement of the account from the ledger, like the camt.053 transfer export.
+func newStatement(journals []*ledgerJournal, account string) *Statement {
+	s := &Statement{
+		MsgID:          "1ZT9Xg2CIY3ZxaRkQ4ZyjMCa5uK",
+		AccountNumber:  account,
+		AccountName:    names[account],
+		Currency:       currency,
+		AdditionalInfo: "Amounts in mock units.",
+		From:           periodStart,
+		To:             periodEnd,
+		OpeningBalance: balanceAt(journals, account, periodStart),
+	}
+	for _, j := range journals {
+		for _, p := range j.postings {
+			if p.account != account || p.at.Before(periodStart) || !p.at.Before(periodEnd) {
+				continue
+			}
+			s.Postings = append(s.Postings, Posting{
+				TransferID:      j.transferID,
+				Type:            transferType,
+				Description:     j.description,
+				DebtorAccount:   j.from,
+				DebtorName:      names[j.from],
+				CreditorAccount: j.to,
+				CreditorName:    names[j.to],
+				Amount:          p.amount,
+				CreatedAt:       p.at,
+			})
+		}
+	}
+	sort.SliceStable(s.Postings, func(i, j int) bool {
+		return s.Postings[i].CreatedAt.Before(s.Postings[j].CreatedAt)
+	})
+	return s
+}
+
+func day(d int) time.Time {
+	return time.Date(2020, 6, d, 12, 0, 0, 0, time.UTC)
+}
+
+func ledger() []*ledgerJournal {
+	return []*ledgerJournal{
+		transfer("1ZT9Xaaaaaaaaaaaaaaaaaaaaa1", customer, member, 100, day(1).AddDate(0, -1, 0)),
+		// Escrowed before the period and refunded during it.
+		escrowed("1ZT9Xaaaaaaaaaaaaaaaaaaaaa2", member, supplier, 15, day(1).AddDate(0, -1, 5), day(3), true),
+		transfer("1ZT9Xaaaaaaaaaaaaaaaaaaaaa3", member, supplier, 30.5, day(2)),
+		// Escrowed and released during the period.
+		escrowed("1ZT9Xaaaaaaaaaaaaaaaaaaaaa4", member, supplier, 20, day(4), day(10), false),
+		// Escrowed during the period and released after it.
+		escrowed("1ZT9Xaaaaaaaaaaaaaaaaaaaaa5", member, customer, 10.25, day(20), day(1).AddDate(0, 1, 2), false),
+		transfer("1ZT9Xaaaaaaaaaaaaaaaaaaaaa6", supplier, member, 7.75, day(25)),
+		// After the period.
+		transfer("1ZT9Xaaaaaaaaaaaaaaaaaaaaa7", customer, member, 1000, day(1).AddDate(0, 1, 5)),
+	}
+}
+
+func TestNewDocumentBalances(t *testing.T) {
+	journals := ledger()
+	tests := []struct {
+		name             string
+		account          string
+		openingAmount    float64
+		openingIndicator string
+		closingAmount    float64
+		closingIndicator string
+		entries          int
+	}{
+		// 100 received and 15 in escrow, then a payment, the refund, two escrows and a payment received.
+		{"credit balances", member, 85, Credit, 47, Credit, 5},
+		// The refunded escrow is never credited, the released one is credited when it is released.
+		{"zero opening balance", supplier, 0, Credit, 42.75, Credit, 3},
+		// The escrow released to the customer after the period is not on the statement.
+		{"debit balances", customer, 100, Debit, 100, Debit, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStatement(journals, tt.account)
+			doc := NewDocument(s, periodEnd)
+			stmt := doc.Stmt.Stmt[0]
+
+			opening, closing := stmt.Bal[0], stmt.Bal[1]
+			if opening.Tp.Cd != OpeningBooked || closing.Tp.Cd != ClosingBooked {
+				t.Fatalf("balance types = %s, %s, want %s, %s", opening.Tp.Cd, closing.Tp.Cd, OpeningBooked, ClosingBooked)
+			}
+			checkAmount(t, "opening balance", opening.Amt, opening.CdtDbtInd, tt.openingAmount, tt.openingIndicator)
+			checkAmount(t, "closing balance", closing.Amt, closing.CdtDbtInd, tt.closingAmount, tt.closingIndicator)
+
+			// The closing balance has to be the balance of the postings at the end of the period.
+			want, wantIndicator := NewAmount(balanceAt(journals, tt.account, periodEnd), currency)
+			checkAmount(t, "closing balance from postings", closing.Amt, closing.CdtDbtInd, float64(want.Value), wantIndicator)
+
+			if len(stmt.Ntry) != tt.entries {
+				t.Fatalf("got %d entries, want %d", len(stmt.Ntry), tt.entries)
+			}
+			net := 0.0
+			for _, e := range stmt.Ntry {
+				if e.CdtDbtInd == Credit {
+					net += float64(e.Amt.Value)
+				} else {
+					net -= float64(e.Amt.Value)
+				}
+			}
+			if !equal(signed(opening.Amt, opening.CdtDbtInd)+net, signed(closing.Amt, closing.CdtDbtInd)) {
+				t.Errorf("opening balance plus the entries is %.2f, want the closing balance %.2f",
+					signed(opening.Amt, opening.CdtDbtInd)+net, signed(closing.Amt, closing.CdtDbtInd))
+			}
+			summary := stmt.TxsSummry
+			if summary.TtlNtries.NbOfNtries != tt.entries || summary.TtlCdtNtries.NbOfNtries+summary.TtlDbtNtries.NbOfNtries != tt.entries {
+				t.Errorf("summary counts %d entries, want %d", summary.TtlNtries.NbOfNtries, tt.entries)
+			}
+			checkAmount(t, "net entries", Amount{Ccy: currency, Value: summary.TtlNtries.TtlNetNtry.Amt}, summary.TtlNtries.TtlNetNtry.CdtDbtInd, math.Abs(net), indicator(net))
+
+			validate(t, doc)
+		})
+	}
+}
+
+func TestNewDocumentEscrowRefund(t *testing.T) {
+	doc := NewDocument(newStatement(ledger(), member), periodEnd)
+	refs := map[string]bool{}
+	for _, e := range doc.Stmt.Stmt[0].Ntry {
+		if refs[e.NtryRef] {
+			t.Errorf("entry reference %s is used twice", e.NtryRef)
+		}
+		refs[e.NtryRef] = true
+	}
+
+	for _, e := range doc.Stmt.Stmt[0].Ntry {
+		transferID := e.NtryDtls[0].TxDtls[0].Refs.EndToEndId
+		if transferID != "1ZT9Xaaaaaaaaaaaaaaaaaaaaa2" {
+			if e.RvslInd || e.NtryRef != transferID {
+				t.Errorf("entry %s of transfer %s, reversal %t, want the transfer ID and no reversal", e.NtryRef, transferID, e.RvslInd)
+			}
+			continue
+		}
+		if e.NtryRef != transferID+ReversalSuffix || !e.RvslInd || e.CdtDbtInd != Credit {
+			t.Errorf("refund entry = %s, reversal %t, %s, want a credit reversal", e.NtryRef, e.RvslInd, e.CdtDbtInd)
+		}
+	}
+}
+
+func TestNewDocumentEmptyPeriod(t *testing.T) {
+	s := &Statement{
+		MsgID:         "1ZT9Xg2CIY3ZxaRkQ4ZyjMCa5uK",
+		AccountNumber: member,
+		Currency:      currency,
+		From:          periodStart,
+		To:            periodEnd,
+	}
+	doc := NewDocument(s, periodEnd)
+	checkAmount(t, "closing balance", doc.Stmt.Stmt[0].Bal[1].Amt, doc.Stmt.Stmt[0].Bal[1].CdtDbtInd, 0, Credit)
+	validate(t, doc)
+}
+
+// validate validates the written document with xmllint against the published camt.053.001.08 schema.
+// xmllint is required, a missing xmllint fails the test.
+func validate(t *testing.T, doc *Document) {
+	t.Helper()
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Fatalf("xmllint is needed to validate the statement against the schema: %v", err)
+	}
+	var b bytes.Buffer
+	err = doc.Write(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(xmllint, "--noout", "--schema", schemaPath, "-")
+	cmd.Stdin = &b
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("the statement is not valid: %v\n%s", err, out)
+	}
+}
+
+func checkAmount(t *testing.T, name string, amt Amount, cdtDbtInd string, want float64, wantIndicator string) {
+	t.Helper()
+	if amt.Ccy != currency {
+		t.Errorf("%s currency = %s, want %s", name, amt.Ccy, currency)
+	}
+	if !equal(float64(amt.Value), want) || cdtDbtInd != wantIndicator {
+		t.Errorf("%s = %.2f %s, want %.2f %s", name, float64(amt.Value), cdtDbtInd, want, wantIndicator)
+	}
+}
+
+func signed(amt Amount, cdtDbtInd string) float64 {
+	if cdtDbtInd == Debit {
+		return -float64(amt.Value)
+	}
+	return float64(amt.Value)
+}
+
+func indicator(amount float64) string {
+	_, i := NewAmount(amount, currency)
+	return i
+}
+
+func equal(a float64, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
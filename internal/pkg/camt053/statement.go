@@ -0,0 +1,163 @@
+package camt053
+
+import (
+	"strings"
+	"time"
+)
+
+// Posting is a posting of the statement's account, positive for credits and negative for debits.
+type Posting struct {
+	TransferID      string
+	Type            string
+	Description     string
+	DebtorAccount   string
+	DebtorName      string
+	CreditorAccount string
+	CreditorName    string
+	Amount          float64
+	CreatedAt       time.Time
+}
+
+// Statement is the statement of an account for the period [From, To).
+type Statement struct {
+	MsgID         string
+	AccountNumber string
+	AccountName   string
+	Currency      string
+	// Written to AddtlStmtInf.
+	AdditionalInfo string
+	From           time.Time
+	To             time.Time
+	// The balance of the account at From.
+	OpeningBalance float64
+	// Every posting of the account in the period, in chronological order.
+	Postings []Posting
+}
+
+// ReversalSuffix is appended to the entry reference of a posting that returns the amount to the debtor, e.g. a refunded escrow.
+// It keeps the entry references unique when both postings of the transfer are on statements.
+const ReversalSuffix = "-R"
+
+// NewDocument builds the statement of the account.
+// The closing balance is the opening balance plus the postings of the period, so the balances always add up with the entries.
+func NewDocument(s *Statement, now time.Time) *Document {
+	// The period is [from, to) so the statement ends just before to.
+	lastMoment := s.To.Add(-time.Second)
+
+	closingBalance := s.OpeningBalance
+	for _, p := range s.Postings {
+		closingBalance += p.Amount
+	}
+	openingAmount, openingIndicator := NewAmount(s.OpeningBalance, s.Currency)
+	closingAmount, closingIndicator := NewAmount(closingBalance, s.Currency)
+
+	statement := AccountStatement{
+		Id:      maxText(s.AccountNumber+"-"+s.From.UTC().Format("20060102"), 35),
+		CreDtTm: DateTime(now),
+		FrToDt: &DateTimePeriod{
+			FrDtTm: DateTime(s.From),
+			ToDtTm: DateTime(lastMoment),
+		},
+		Acct: CashAccount{
+			Id:  newAccountIdentification(s.AccountNumber),
+			Ccy: s.Currency,
+			Nm:  maxText(s.AccountName, 70),
+		},
+		Bal: []CashBalance{
+			{
+				Tp:        BalanceType{Cd: OpeningBooked},
+				Amt:       openingAmount,
+				CdtDbtInd: openingIndicator,
+				Dt:        NewDate(s.From),
+			},
+			{
+				Tp:        BalanceType{Cd: ClosingBooked},
+				Amt:       closingAmount,
+				CdtDbtInd: closingIndicator,
+				Dt:        NewDate(lastMoment),
+			},
+		},
+		AddtlStmtInf: maxText(s.AdditionalInfo, 500),
+	}
+
+	var credits, debits NumberAndSumOfTransactions1
+	for _, p := range s.Postings {
+		statement.Ntry = append(statement.Ntry, newReportEntry(s, p))
+		amount, indicator := NewAmount(p.Amount, s.Currency)
+		if indicator == Credit {
+			credits.NbOfNtries++
+			credits.Sum += amount.Value
+		} else {
+			debits.NbOfNtries++
+			debits.Sum += amount.Value
+		}
+	}
+	net, netIndicator := NewAmount(float64(credits.Sum-debits.Sum), s.Currency)
+	statement.TxsSummry = &TotalTransactions{
+		TtlNtries: NumberAndSumOfTransactions4{
+			NbOfNtries: credits.NbOfNtries + debits.NbOfNtries,
+			Sum:        credits.Sum + debits.Sum,
+			TtlNetNtry: AmountAndDirection{Amt: net.Value, CdtDbtInd: netIndicator},
+		},
+		TtlCdtNtries: credits,
+		TtlDbtNtries: debits,
+	}
+
+	return &Document{
+		Stmt: BkToCstmrStmtV08{
+			GrpHdr: GroupHeader{
+				MsgId:   s.MsgID,
+				CreDtTm: DateTime(now),
+			},
+			Stmt: []AccountStatement{statement},
+		},
+	}
+}
+
+func newReportEntry(s *Statement, p Posting) ReportEntry {
+	amount, indicator := NewAmount(p.Amount, s.Currency)
+	// The debtor of the transfer is credited when the amount is returned to them.
+	reversal := indicator == Credit && p.DebtorAccount == s.AccountNumber
+	ref := p.TransferID
+	if reversal {
+		ref += ReversalSuffix
+	}
+
+	details := TransactionDetails{
+		Refs: &TransactionReferences{EndToEndId: maxText(p.TransferID, 35)},
+		RltdPties: &TransactionParties{
+			Dbtr:     &PartyIdentification{Nm: maxText(p.DebtorName, 140)},
+			DbtrAcct: &CashAccount{Id: newAccountIdentification(p.DebtorAccount)},
+			Cdtr:     &PartyIdentification{Nm: maxText(p.CreditorName, 140)},
+			CdtrAcct: &CashAccount{Id: newAccountIdentification(p.CreditorAccount)},
+		},
+	}
+	if description := maxText(p.Description, 140); description != "" {
+		details.RmtInf = &RemittanceInformation{Ustrd: []string{description}}
+	}
+
+	return ReportEntry{
+		NtryRef:   maxText(ref, 35),
+		Amt:       amount,
+		CdtDbtInd: indicator,
+		RvslInd:   reversal,
+		Sts:       Booked,
+		BookgDt:   NewDateTime(p.CreatedAt),
+		ValDt:     NewDate(p.CreatedAt),
+		BkTxCd:    BankTransactionCode{Prtry: ProprietaryBankTransactionCode{Cd: maxText(p.Type, 35)}},
+		NtryDtls:  []EntryDetails{{TxDtls: []TransactionDetails{details}}},
+	}
+}
+
+func newAccountIdentification(accountNumber string) AccountIdentification {
+	return AccountIdentification{Othr: GenericAccountIdentification{Id: maxText(accountNumber, 34)}}
+}
+
+// maxText puts the text on a single line and truncates it to the max length of the schema's text type.
+func maxText(s string, max int) string {
+	runes := []rune(strings.Join(strings.Fields(s), " "))
+	if len(runes) <= max {
+		return string(runes)
+	}
+	return string(runes[:max])
+}
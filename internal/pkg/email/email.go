@@ -94,6 +94,179 @@ func (_ *Email) signup(input *SignupNotificationEmail) {
 	}
 }
 
+// Entity invitation
+
+type EntityInvitationEmail struct {
+	EntityName    string
+	InviterName   string
+	Role          string
+	ReceiverEmail string
+	Token         string
+}
+
+func EntityInvitation(input *EntityInvitationEmail) {
+	e.entityInvitation(input)
+}
+func (_ *Email) entityInvitation(input *EntityInvitationEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.entity_invitation"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.ReceiverEmail, input.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("serverAddress", viper.GetString("url"))
+	p.SetDynamicTemplateData("entityName", input.EntityName)
+	p.SetDynamicTemplateData("inviterName", input.InviterName)
+	p.SetDynamicTemplateData("role", input.Role)
+	p.SetDynamicTemplateData("token", input.Token)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.EntityInvitation failed", zap.Error(err))
+	}
+}
+
+// Entity review
+
+type EntityReviewEmail struct {
+	ReviewerEntityName string
+	ReceiverEntityName string
+	ReceiverEmail      string
+	Rating             int
+	Body               string
+	TransferID         string
+}
+
+func EntityReview(input *EntityReviewEmail) {
+	e.entityReview(input)
+}
+func (_ *Email) entityReview(input *EntityReviewEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.entity_review"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.ReceiverEntityName+" ", input.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("serverAddress", viper.GetString("url"))
+	p.SetDynamicTemplateData("reviewerEntityName", input.ReviewerEntityName)
+	p.SetDynamicTemplateData("rating", input.Rating)
+	p.SetDynamicTemplateData("body", input.Body)
+	p.SetDynamicTemplateData("transferID", input.TransferID)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.EntityReview failed", zap.Error(err))
+	}
+}
+
+// Entity status change
+
+type EntityStatusChangeEmail struct {
+	EntityName    string
+	Status        string
+	Reason        string
+	ReceiverName  string
+	ReceiverEmail string
+}
+
+func EntityStatusChange(input *EntityStatusChangeEmail) {
+	e.entityStatusChange(input)
+}
+func (_ *Email) entityStatusChange(input *EntityStatusChangeEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.entity_status_changed"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.ReceiverName, input.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("serverAddress", viper.GetString("url"))
+	p.SetDynamicTemplateData("entityName", input.EntityName)
+	p.SetDynamicTemplateData("status", input.Status)
+	p.SetDynamicTemplateData("reason", input.Reason)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.EntityStatusChange failed", zap.Error(err))
+	}
+}
+
+// Entity application reviewed
+
+type EntityApplicationReviewedEmail struct {
+	EntityName    string
+	Decision      string
+	Status        string
+	Comment       string
+	ReceiverName  string
+	ReceiverEmail string
+}
+
+func EntityApplicationReviewed(input *EntityApplicationReviewedEmail) {
+	e.entityApplicationReviewed(input)
+}
+func (_ *Email) entityApplicationReviewed(input *EntityApplicationReviewedEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.entity_application_reviewed"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.ReceiverName, input.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("serverAddress", viper.GetString("url"))
+	p.SetDynamicTemplateData("entityName", input.EntityName)
+	p.SetDynamicTemplateData("decision", input.Decision)
+	p.SetDynamicTemplateData("status", input.Status)
+	p.SetDynamicTemplateData("comment", input.Comment)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.EntityApplicationReviewed failed", zap.Error(err))
+	}
+}
+
+// Imported user invitation
+
+type UserImportInvitationEmail struct {
+	EntityName    string
+	Receiver      string
+	ReceiverEmail string
+	Token         string
+}
+
+func UserImportInvitation(input *UserImportInvitationEmail) {
+	e.userImportInvitation(input)
+}
+func (_ *Email) userImportInvitation(input *UserImportInvitationEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.user_import_invitation"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.Receiver+" ", input.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("serverAddress", viper.GetString("url"))
+	p.SetDynamicTemplateData("entityName", input.EntityName)
+	p.SetDynamicTemplateData("token", input.Token)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.UserImportInvitation failed", zap.Error(err))
+	}
+}
+
 // Password reset
 
 type PasswordResetEmail struct {
@@ -83,6 +83,27 @@ func (tr *transfer) Accept(info *TransferEmailInfo) {
 	}
 }
 
+// Transfer accepted automatically by the receiver's auto-accept rules
+
+func (tr *transfer) AutoAccept(info *TransferEmailInfo) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.transfer_auto_accepted"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(info.ReceiverEntityName+" ", info.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("initiatorEntityName", info.InitiatorEntityName)
+	p.SetDynamicTemplateData("amount", fmt.Sprintf("%.2f", info.Amount))
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.Transfer.AutoAccept failed", zap.Error(err))
+	}
+}
+
 // Transfer rejected
 
 func (tr *transfer) Reject(info *TransferEmailInfo) {
@@ -157,3 +178,92 @@ func (tr *transfer) CancelBySystem(info *TransferEmailInfo) {
 		l.Logger.Error("email.Transfer.Cancel failed", zap.Error(err))
 	}
 }
+
+// Transfer held in escrow
+
+func (tr *transfer) Escrow(info *TransferEmailInfo) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.transfer_escrowed"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(info.ReceiverEntityName+" ", info.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("initiatorEntityName", info.InitiatorEntityName)
+	p.SetDynamicTemplateData("amount", fmt.Sprintf("%.2f", info.Amount))
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.Transfer.Escrow failed", zap.Error(err))
+	}
+}
+
+// Escrowed transfer released to the payee
+
+func (tr *transfer) EscrowRelease(info *TransferEmailInfo) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.transfer_escrow_released"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(info.ReceiverEntityName+" ", info.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("initiatorEntityName", info.InitiatorEntityName)
+	p.SetDynamicTemplateData("amount", fmt.Sprintf("%.2f", info.Amount))
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.Transfer.EscrowRelease failed", zap.Error(err))
+	}
+}
+
+// Escrowed transfer refunded to the payer
+
+func (tr *transfer) EscrowRefund(info *TransferEmailInfo) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.transfer_escrow_refunded"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(info.InitiatorEntityName+" ", info.InitiatorEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("receiverEntityName", info.ReceiverEntityName)
+	p.SetDynamicTemplateData("amount", fmt.Sprintf("%.2f", info.Amount))
+	p.SetDynamicTemplateData("reason", info.Reason)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.Transfer.EscrowRefund failed", zap.Error(err))
+	}
+}
+
+// Comment posted on a transfer
+
+func (tr *transfer) Comment(info *TransferEmailInfo, comment *types.TransferComment) {
+	url := viper.GetString("url") + "/transfers/" + comment.TransferID
+
+	m := e.newEmail(viper.GetString("sendgrid.template_id.transfer_comment"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(info.ReceiverEntityName+" ", info.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("authorEntityName", info.InitiatorEntityName)
+	p.SetDynamicTemplateData("amount", fmt.Sprintf("%.2f", info.Amount))
+	p.SetDynamicTemplateData("comment", comment.Body)
+	p.SetDynamicTemplateData("url", url)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.Transfer.Comment failed", zap.Error(err))
+	}
+}
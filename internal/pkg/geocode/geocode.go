@@ -0,0 +1,101 @@
+// Package geocode looks up the location of postal codes in a bundled dataset so no external service is needed.
+package geocode
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type Location struct {
+	Latitude  float64
+	Longitude float64
+}
+
+var (
+	once      sync.Once
+	postcodes map[string]*Location
+)
+
+// Lookup returns the location of the postal code. Postal codes that are not in the dataset fall back to
+// their outward code (e.g. "SW1A") and then to their area (e.g. "SW"), following the UK postcode format.
+func Lookup(postalCode string) (*Location, bool) {
+	once.Do(load)
+
+	code := strings.ToUpper(strings.Join(strings.Fields(postalCode), ""))
+	if code == "" {
+		return nil, false
+	}
+	candidates := []string{code}
+	if len(code) > 4 {
+		// The inward code is always the last three characters.
+		candidates = append(candidates, code[:len(code)-3])
+	}
+	// The area is made of the leading letters.
+	if i := strings.IndexFunc(code, func(r rune) bool { return r < 'A' || r > 'Z' }); i > 0 {
+		candidates = append(candidates, code[:i])
+	}
+
+	for _, candidate := range candidates {
+		if location, ok := postcodes[candidate]; ok {
+			return location, true
+		}
+	}
+	return nil, false
+}
+
+// Distance returns the great-circle distance between two points in kilometers.
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371.0
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadius * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// load reads the dataset, a CSV file with the postcode, latitude and longitude columns.
+func load() {
+	postcodes = map[string]*Location{}
+
+	file, err := os.Open(viper.GetString("geocode.postcode_file"))
+	if err != nil {
+		l.Logger.Error("geocode.load failed", zap.Error(err))
+		return
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = 3
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			l.Logger.Error("geocode.load failed", zap.Error(err))
+			return
+		}
+		latitude, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			// Skip the header.
+			continue
+		}
+		longitude, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			continue
+		}
+		code := strings.ToUpper(strings.Join(strings.Fields(record[0]), ""))
+		postcodes[code] = &Location{Latitude: latitude, Longitude: longitude}
+	}
+}
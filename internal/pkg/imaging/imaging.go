@@ -0,0 +1,146 @@
+// Package imaging validates uploaded images and resizes them using the standard library only.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif" // register the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// Images larger than this are rejected before they are decoded to protect the server's memory.
+const maxPixels = 40000000
+
+var (
+	ErrUnsupportedType = errors.New("Please upload a JPEG, PNG or GIF image.")
+	ErrTooLarge        = errors.New("The image dimensions are too large.")
+)
+
+// Image is an encoded image that is ready to be stored.
+type Image struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+}
+
+// Decode sniffs the content type of the data, rather than trusting the client, and decodes it.
+func Decode(data []byte) (image.Image, error) {
+	switch http.DetectContentType(data) {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	return img, nil
+}
+
+// Fit scales the image down so that it fits in a size by size box. Smaller images are not enlarged.
+func Fit(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return img
+	}
+	if width >= height {
+		height = max(1, height*size/width)
+		width = size
+	} else {
+		width = max(1, width*size/height)
+		height = size
+	}
+	return resize(img, width, height)
+}
+
+// Encode encodes the image as JPEG when it is opaque and as PNG otherwise.
+// Re-encoding also strips the metadata, such as the GPS location, of the upload.
+func Encode(img image.Image) (*Image, error) {
+	buf := &bytes.Buffer{}
+	result := &Image{Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+	if isOpaque(img) {
+		err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 85})
+		if err != nil {
+			return nil, err
+		}
+		result.ContentType, result.Extension = "image/jpeg", ".jpg"
+	} else {
+		err := png.Encode(buf, img)
+		if err != nil {
+			return nil, err
+		}
+		result.ContentType, result.Extension = "image/png", ".png"
+	}
+	result.Data = buf.Bytes()
+	return result, nil
+}
+
+// resize averages the source pixels covered by every destination pixel, which gives good results when shrinking.
+func resize(img image.Image, width, height int) image.Image {
+	src := toRGBA(img)
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcHeight/height, max((y+1)*srcHeight/height, y*srcHeight/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcWidth/width, max((x+1)*srcWidth/width, x*srcWidth/width+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				i := sy*src.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					b += int(src.Pix[i+2])
+					a += int(src.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+			j := y*dst.Stride + x*4
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(b / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
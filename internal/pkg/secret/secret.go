@@ -0,0 +1,60 @@
+// Package secret encrypts small secrets, e.g. signing keys, before they are stored.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// KeySize is the size of the AES-256 key the secrets are encrypted with.
+const KeySize = 32
+
+var errMalformed = errors.New("The encrypted secret is malformed.")
+
+// ParseKey decodes the hex encoded encryption key.
+func ParseKey(hexKey string) ([]byte, error) {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil || len(key) != KeySize {
+		return nil, errors.New("The encryption key must be 32 hex encoded bytes.")
+	}
+	return key, nil
+}
+
+// Seal encrypts the plaintext with AES-GCM and returns the hex encoded nonce followed by the ciphertext.
+func Seal(key []byte, plaintext []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// Open decrypts a secret encrypted by Seal.
+func Open(key []byte, sealed string) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := hex.DecodeString(sealed)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return nil, errMalformed
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores the files in a directory on the server and serves them itself.
+type Local struct {
+	dir string
+	url string
+}
+
+func NewLocal(dir string, url string) *Local {
+	return &Local{dir: dir, url: strings.TrimSuffix(url, "/")}
+}
+
+func (l *Local) Put(key string, contentType string, data []byte) (string, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(filepath.Dir(name), 0755)
+	if err != nil {
+		return "", err
+	}
+	err = ioutil.WriteFile(name, data, 0644)
+	if err != nil {
+		return "", err
+	}
+	return l.url + "/" + key, nil
+}
+
+func (l *Local) Get(key string) ([]byte, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(name)
+}
+
+func (l *Local) Delete(key string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(name)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// PathPrefix returns the path of the public URL the files are served from, e.g. "/uploads/".
+func (l *Local) PathPrefix() (string, error) {
+	u, err := url.Parse(l.url)
+	if err != nil {
+		return "", err
+	}
+	prefix := strings.TrimSuffix(u.Path, "/")
+	if prefix == "" {
+		return "", errors.New("storage: the URL of the local storage needs a path, such as /uploads")
+	}
+	return prefix + "/", nil
+}
+
+// Handler serves the stored files under the URL path prefix.
+func (l *Local) Handler(prefix string) http.Handler {
+	return http.StripPrefix(prefix, l)
+}
+
+// ServeHTTP serves the stored files. Directories are not listed.
+func (l *Local) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if l.dir == "" || strings.HasSuffix(r.URL.Path, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.FileServer(http.Dir(l.dir)).ServeHTTP(w, r)
+}
+
+// path makes sure the key cannot point outside of the directory.
+func (l *Local) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || cleaned != "/"+key {
+		return "", errors.New("Invalid file key.")
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(cleaned)), nil
+}
@@ -0,0 +1,64 @@
+// Package storage stores uploaded files. The backend is chosen with the "storage.driver" config.
+package storage
+
+import (
+	"log"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+func init() {
+	// The directories are relative to the working directory of the server, which also holds the configs.
+	// Without a default an unset directory would serve the working directory itself.
+	viper.SetDefault("storage.local.dir", "uploads")
+	viper.SetDefault("storage.local.url", "/uploads")
+	viper.SetDefault("storage.local.private_dir", "private")
+}
+
+// Storage stores files under a key such as "entities/5eec78f4a880b7c235f66e7c/logo.jpg".
+type Storage interface {
+	// Put stores the file and returns its public URL.
+	Put(key string, contentType string, data []byte) (string, error)
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+}
+
+var (
+	once     sync.Once
+	instance Storage
+
+	privateOnce     sync.Once
+	privateInstance Storage
+)
+
+// Default returns the storage configured with "storage.driver".
+func Default() Storage {
+	once.Do(func() {
+		switch viper.GetString("storage.driver") {
+		// Other drivers such as S3 can be added here.
+		default:
+			instance = mustNewLocal(viper.GetString("storage.local.dir"), viper.GetString("storage.local.url"))
+		}
+	})
+	return instance
+}
+
+// Private returns the storage for the files that must not be public, such as identity documents.
+// The files are only available through the API endpoints that check who is asking for them.
+func Private() Storage {
+	privateOnce.Do(func() {
+		switch viper.GetString("storage.driver") {
+		default:
+			privateInstance = mustNewLocal(viper.GetString("storage.local.private_dir"), "")
+		}
+	})
+	return privateInstance
+}
+
+func mustNewLocal(dir string, url string) *Local {
+	if dir == "" {
+		log.Fatal("storage: the directory of the local storage is not set")
+	}
+	return NewLocal(dir, url)
+}
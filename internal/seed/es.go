@@ -26,9 +26,10 @@ func (_ *elasticSearch) CreateEntity(
 		Status:     entity.Status,
 		Categories: entity.Categories,
 		// Address
-		City:    entity.City,
-		Region:  entity.Region,
-		Country: entity.Country,
+		City:     entity.City,
+		Region:   entity.Region,
+		Country:  entity.Country,
+		Location: types.NewGeoPoint(entity.Latitude, entity.Longitude),
 		// Account
 		AccountNumber: accountNumber,
 		Balance:       &balance,
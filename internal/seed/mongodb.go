@@ -3,6 +3,7 @@ package seed
 import (
 	"context"
 
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"github.com/ic3network/mccs-alpha-api/util/bcrypt"
@@ -33,6 +34,7 @@ func (_ *mongoDB) CreateUser(user types.User) (primitive.ObjectID, error) {
 func (_ *mongoDB) AssociateUserWithEntity(userID, entityID primitive.ObjectID) error {
 	_, err := mongo.DB().Collection("entities").UpdateOne(context.Background(), bson.M{"_id": entityID}, bson.M{
 		"$addToSet": bson.M{"users": userID},
+		"$push":     bson.M{"roles": bson.M{"userID": userID, "role": constant.EntityRole.Owner}},
 	})
 	return err
 }
@@ -33,3 +33,32 @@ func IsTradingAccepted(status string) bool {
 	}
 	return false
 }
+
+// entityStatusTransitions lists the statuses an entity can be moved to from each status.
+var entityStatusTransitions = map[string][]string{
+	constant.Entity.Pending:   {constant.Entity.Accepted, constant.Entity.Rejected},
+	constant.Entity.Rejected:  {constant.Entity.Pending, constant.Entity.Accepted},
+	constant.Entity.Accepted:  {constant.Entity.Rejected, constant.Trading.Pending, constant.Trading.Accepted},
+	constant.Trading.Pending:  {constant.Trading.Accepted, constant.Trading.Rejected},
+	constant.Trading.Accepted: {constant.Trading.Rejected},
+	constant.Trading.Rejected: {constant.Trading.Pending, constant.Trading.Accepted},
+}
+
+// AllowedStatusTransitions returns the statuses an entity in the status can be moved to.
+func AllowedStatusTransitions(from string) []string {
+	return entityStatusTransitions[from]
+}
+
+// IsAllowedStatusTransition checks whether an entity can be moved from one status to the other.
+// Entities without a valid status, e.g. from before the statuses were introduced, can be moved to any status.
+func IsAllowedStatusTransition(from string, to string) bool {
+	if !IsValidStatus(from) {
+		return IsValidStatus(to)
+	}
+	for _, status := range entityStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+var handleRe = regexp.MustCompile("^[a-z0-9][a-z0-9_-]{2,29}$")
+
+// IsHandle checks whether the input refers to a handle (e.g. @greengrocer) instead of an account number.
+func IsHandle(input string) bool {
+	return strings.HasPrefix(strings.TrimSpace(input), "@")
+}
+
+// FormatHandle transforms the user input into the stored format.
+// @GreenGrocer -> greengrocer
+func FormatHandle(input string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(input)), "@")
+}
+
+// IsValidHandle checks whether the formatted handle has 3-30 letters, digits, dashes or underscores.
+func IsValidHandle(handle string) bool {
+	return handleRe.MatchString(handle)
+}